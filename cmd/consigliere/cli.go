@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nuclight.org/consigliere/internal/bot"
+	"nuclight.org/consigliere/internal/config"
+	"nuclight.org/consigliere/internal/export"
+	"nuclight.org/consigliere/internal/nickimport"
+	"nuclight.org/consigliere/internal/poll"
+	"nuclight.org/consigliere/internal/storage"
+)
+
+const cliUsage = `Usage:
+  consigliere                  run the bot
+  consigliere export [flags]   export polls, votes and nicknames (see consigliere export -h)
+  consigliere import [flags] FILE
+                               import nicknames from a CSV file (see consigliere import -h)
+`
+
+// runCommand runs an offline subcommand and returns the process exit code.
+func runCommand(name string, args []string) int {
+	var err error
+	switch name {
+	case "export":
+		err = runExport(args)
+	case "import":
+		err = runImport(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(cliUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n%s", name, cliUsage)
+		return 2
+	}
+
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// openService opens and migrates the database at DB_PATH and creates the poll service over it.
+func openService() (*poll.Service, *storage.DB, error) {
+	dbPath, err := config.LoadDBPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := storage.NewDB(dbPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Migrate(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("migrate database: %w", err)
+	}
+	if nicks := db.UnassignedNicknames(); len(nicks) > 0 {
+		fmt.Fprintf(os.Stderr, "nicknames of players who never voted were left shared by all clubs: %s\n", strings.Join(nicks, ", "))
+	}
+	return newService(db), db, nil
+}
+
+// runExport writes the same files as the /export command, straight from the database.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	clubName := fs.String("club", "", "club to export: vanmo or tbilissimo (required)")
+	from := fs.String("from", "", "first event date, YYYY-MM-DD")
+	to := fs.String("to", "", "last event date, YYYY-MM-DD (inclusive)")
+	formatName := fs.String("format", string(export.FormatCSV), "csv (polls, votes and nicknames files) or json (one file)")
+	outDir := fs.String("out", ".", "directory to write the files to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	club, ok := poll.ParseClub(*clubName)
+	if !ok {
+		return fmt.Errorf("unknown club %q, use -club vanmo or -club tbilissimo", *clubName)
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	period, err := export.ParsePeriod(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	svc, db, err := openService()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	data, err := svc.Export(club, period)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	files, err := export.Files(data, format)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+	for _, f := range files {
+		path := filepath.Join(*outDir, f.Name)
+		if err := os.WriteFile(path, f.Data, 0644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
+
+// runImport creates nicknames from a CSV file with the same rows as the /import command.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	clubName := fs.String("club", "", "club to import into: vanmo or tbilissimo (required); rows marked shared go to all clubs")
+	actor := fs.Int64("actor", 0, "Telegram user ID recorded as the creator of the nicknames")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: consigliere import -club CLUB [-actor ID] FILE")
+		fmt.Fprintln(fs.Output(), "Rows: @username or user ID, nickname[, gender][, shared]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("import needs exactly one file")
+	}
+
+	club, ok := poll.ParseClub(*clubName)
+	if !ok {
+		return fmt.Errorf("unknown club %q, use -club vanmo or -club tbilissimo", *clubName)
+	}
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	svc, db, err := openService()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := nickimport.Import(svc, club, data, *actor, bot.ValidateImportRow)
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+
+	for _, row := range report.Created {
+		fmt.Printf("line %d: created %s -> %s\n", row.Line, row.Identity(), row.Nickname)
+	}
+	for _, row := range report.Duplicates {
+		fmt.Printf("line %d: nickname taken %s -> %s\n", row.Line, row.Identity(), row.Nickname)
+	}
+	for _, row := range report.Invalid {
+		fmt.Printf("line %d: invalid: %v\n", row.Line, row.Err)
+	}
+	fmt.Printf("created %d, duplicates %d, invalid %d\n", len(report.Created), len(report.Duplicates), len(report.Invalid))
+	return nil
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,9 +12,13 @@ import (
 
 	"github.com/getsentry/sentry-go"
 
+	"nuclight.org/consigliere/internal/api"
 	"nuclight.org/consigliere/internal/bot"
 	"nuclight.org/consigliere/internal/config"
+	"nuclight.org/consigliere/internal/dashboard"
+	"nuclight.org/consigliere/internal/health"
 	"nuclight.org/consigliere/internal/logger"
+	"nuclight.org/consigliere/internal/metrics"
 	"nuclight.org/consigliere/internal/poll"
 	"nuclight.org/consigliere/internal/storage"
 )
@@ -23,6 +30,11 @@ var (
 )
 
 func main() {
+	// Offline subcommands only need the database and exit when done
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	// Load configuration first (needed for Sentry DSN)
 	cfg, err := config.Load()
 	if err != nil {
@@ -64,6 +76,7 @@ func main() {
 
 	appLog.Info("config loaded",
 		"db_path", cfg.DBPath,
+		"webhook", cfg.WebhookURL != "",
 	)
 
 	// Initialize database
@@ -78,6 +91,9 @@ func main() {
 		appLog.Error("failed to migrate database", "error", err)
 		os.Exit(1)
 	}
+	if nicks := db.UnassignedNicknames(); len(nicks) > 0 {
+		appLog.Warn("nicknames of players who never voted were left shared by all clubs", "nicknames", nicks)
+	}
 
 	appLog.Info("database initialized")
 
@@ -87,13 +103,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create repositories
-	pollRepo := storage.NewPollRepository(db)
-	voteRepo := storage.NewVoteRepository(db)
-	nickRepo := storage.NewNicknameRepository(db)
-
 	// Create service
-	pollService := poll.NewService(pollRepo, voteRepo, nickRepo)
+	pollService := newService(db)
 
 	// Create and start bot
 	b, err := bot.New(cfg, pollService, appLog)
@@ -105,6 +116,40 @@ func main() {
 	b.RegisterCommands()
 	b.RegisterHandlers()
 
+	// Start the optional HTTP servers
+	var servers []*httpServer
+	if cfg.APIAddr != "" {
+		servers = append(servers, serveHTTP("admin api", cfg.APIAddr, api.New(pollService, b, cfg.APIToken, appLog).Handler(), appLog))
+	}
+	if cfg.DashboardAddr != "" {
+		dash, err := dashboard.New(pollService, b.DashboardClubs(), cfg.TelegramToken, b.Username(), appLog)
+		if err != nil {
+			appLog.Error("failed to initialize dashboard", "error", err)
+			os.Exit(1)
+		}
+		servers = append(servers, serveHTTP("dashboard", cfg.DashboardAddr, dash.Handler(), appLog))
+	}
+	if cfg.HealthAddr != "" {
+		updates := health.Check{Name: "updates", Run: b.CheckUpdates}
+		mux := http.NewServeMux()
+		mux.Handle("GET /healthz", health.Handler(updates))
+		mux.Handle("GET /readyz", health.Handler(
+			health.Check{Name: "db", Run: db.Ping},
+			updates,
+			health.Check{Name: "templates", Run: bot.CheckTemplates},
+		))
+		// One address may serve both, as a listener can't be shared between servers
+		if cfg.MetricsAddr == cfg.HealthAddr {
+			mux.Handle("GET /metrics", metrics.Handler())
+		}
+		servers = append(servers, serveHTTP("health", cfg.HealthAddr, mux, appLog))
+	}
+	if cfg.MetricsAddr != "" && cfg.MetricsAddr != cfg.HealthAddr {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", metrics.Handler())
+		servers = append(servers, serveHTTP("metrics", cfg.MetricsAddr, mux, appLog))
+	}
+
 	// Set up graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -118,4 +163,53 @@ func main() {
 	appLog.Info("bot started, press Ctrl+C to stop")
 	b.Start()
 	appLog.Info("bot stopped")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			appLog.Warn("failed to shut down http server", "server", srv.name, "error", err)
+		}
+	}
+}
+
+// httpServer is an optional HTTP server running next to the bot.
+type httpServer struct {
+	*http.Server
+	name string
+}
+
+// serveHTTP starts serving handler on addr in the background.
+func serveHTTP(name, addr string, handler http.Handler, appLog logger.Logger) *httpServer {
+	srv := &httpServer{
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		name: name,
+	}
+	go func() {
+		appLog.Info("http server listening", "server", name, "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			appLog.Error("http server failed", "server", name, "error", err)
+		}
+	}()
+	return srv
+}
+
+// newService creates the repositories over db and the poll service using them.
+func newService(db *storage.DB) *poll.Service {
+	return poll.NewService(poll.Repositories{
+		Polls:        storage.NewPollRepository(db),
+		Votes:        storage.NewVoteRepository(db),
+		Nicknames:    storage.NewNicknameRepository(db),
+		Attendance:   storage.NewAttendanceRepository(db),
+		Stats:        storage.NewStatsRepository(db),
+		Games:        storage.NewGameRepository(db),
+		Seating:      storage.NewSeatingRepository(db),
+		Payments:     storage.NewPaymentRepository(db),
+		Guests:       storage.NewGuestRepository(db),
+		NickRequests: storage.NewNicknameRequestRepository(db),
+	})
 }
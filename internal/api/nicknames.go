@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type nicknameJSON struct {
+	GameNick   string `json:"game_nick"`
+	Gender     string `json:"gender,omitempty"`
+	TgUserID   int64  `json:"tg_user_id,omitempty"`
+	TgUsername string `json:"tg_username,omitempty"`
+	Shared     bool   `json:"shared"`
+}
+
+// createNicknameRequest links a Telegram user (by ID, username or both) to a game nickname, like /nick.
+type createNicknameRequest struct {
+	TgUserID   int64  `json:"tg_user_id"`
+	TgUsername string `json:"tg_username"`
+	GameNick   string `json:"game_nick"`
+	Gender     string `json:"gender"`
+	Shared     bool   `json:"shared"`
+}
+
+// updateNicknameRequest renames a nickname and/or sets its gender; omitted fields are left as is.
+type updateNicknameRequest struct {
+	GameNick *string `json:"game_nick"`
+	Gender   *string `json:"gender"` // "" clears the gender
+}
+
+func validGender(gender string) bool {
+	return gender == "" || gender == "male" || gender == "female"
+}
+
+// handleNicknames lists the nicknames visible in the club: GET /api/clubs/{club}/nicknames
+func (s *Server) handleNicknames(w http.ResponseWriter, r *http.Request) {
+	club, ok := pathClub(w, r)
+	if !ok {
+		return
+	}
+	nicknames, err := s.svc.ListNicknames(club)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	out := make([]nicknameJSON, len(nicknames))
+	for i, n := range nicknames {
+		out[i] = nicknameJSON{
+			GameNick:   n.Nick,
+			Gender:     n.Gender,
+			TgUserID:   n.TgUserID,
+			TgUsername: n.TgUsername,
+			Shared:     n.Shared(),
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleCreateNickname creates a nickname in the club, or the shared namespace: POST /api/clubs/{club}/nicknames
+func (s *Server) handleCreateNickname(w http.ResponseWriter, r *http.Request) {
+	club, ok := pathClub(w, r)
+	if !ok {
+		return
+	}
+	var req createNicknameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.GameNick == "" || (req.TgUserID <= 0 && req.TgUsername == "") {
+		writeError(w, http.StatusBadRequest, "game_nick and tg_user_id or tg_username are required")
+		return
+	}
+	if !validGender(req.Gender) {
+		writeError(w, http.StatusBadRequest, "gender must be male, female or empty")
+		return
+	}
+
+	var tgUserID *int64
+	var tgUsername *string
+	if req.TgUserID > 0 {
+		tgUserID = &req.TgUserID
+	}
+	if req.TgUsername != "" {
+		tgUsername = &req.TgUsername
+	}
+	namespace := club
+	if req.Shared {
+		namespace = poll.SharedNicknames
+	}
+
+	created, err := s.svc.CreateNickname(namespace, tgUserID, tgUsername, req.GameNick, req.Gender, 0)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if !created {
+		s.writeServiceError(w, r, poll.ErrNickTaken)
+		return
+	}
+
+	s.logger.Info("nickname created via api", "club", club, "game_nick", req.GameNick, "shared", req.Shared)
+	s.nicknamesChanged(namespace)
+	writeJSON(w, http.StatusCreated, nicknameJSON{
+		GameNick:   req.GameNick,
+		Gender:     req.Gender,
+		TgUserID:   req.TgUserID,
+		TgUsername: poll.NormalizeUsername(req.TgUsername),
+		Shared:     req.Shared,
+	})
+}
+
+// handleUpdateNickname renames a nickname and/or sets its gender, like /nick rename and /nick gender:
+// PATCH /api/clubs/{club}/nicknames/{nick}
+func (s *Server) handleUpdateNickname(w http.ResponseWriter, r *http.Request) {
+	club, ok := pathClub(w, r)
+	if !ok {
+		return
+	}
+	var req updateNicknameRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.GameNick != nil && *req.GameNick == "" {
+		writeError(w, http.StatusBadRequest, "game_nick must not be empty")
+		return
+	}
+	if req.Gender != nil && !validGender(*req.Gender) {
+		writeError(w, http.StatusBadRequest, "gender must be male, female or empty")
+		return
+	}
+
+	nick := r.PathValue("nick")
+	namespace := club
+	var err error
+	if req.GameNick != nil && *req.GameNick != nick {
+		if namespace, err = s.svc.RenameNickname(club, nick, *req.GameNick, true); err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		nick = *req.GameNick
+	}
+	if req.Gender != nil {
+		if namespace, err = s.svc.SetNicknameGender(club, nick, *req.Gender, true); err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+	}
+
+	s.logger.Info("nickname updated via api", "club", club, "old_nick", r.PathValue("nick"), "game_nick", nick)
+	s.nicknamesChanged(namespace)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteNickname removes a nickname visible in the club, like /nick rm: DELETE /api/clubs/{club}/nicknames/{nick}
+func (s *Server) handleDeleteNickname(w http.ResponseWriter, r *http.Request) {
+	club, ok := pathClub(w, r)
+	if !ok {
+		return
+	}
+	nick := r.PathValue("nick")
+	namespace, err := s.svc.DeleteNickname(club, nick, true)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.logger.Info("nickname deleted via api", "club", club, "game_nick", nick)
+	s.nicknamesChanged(namespace)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) nicknamesChanged(club poll.Club) {
+	if s.chats != nil {
+		s.chats.NicknamesChanged(club)
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"nuclight.org/consigliere/internal/export"
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type pollJSON struct {
+	ID          int64      `json:"id"`
+	Club        poll.Club  `json:"club"`
+	ChatID      int64      `json:"chat_id"`
+	EventDate   string     `json:"event_date"`
+	IsActive    bool       `json:"is_active"`
+	IsCancelled bool       `json:"is_cancelled"`
+	StartTime   string     `json:"start_time,omitempty"`
+	Price       int        `json:"price,omitempty"`
+	JudgeUserID int64      `json:"judge_user_id,omitempty"`
+	JudgeName   string     `json:"judge_name,omitempty"`
+	Votes       []voteJSON `json:"votes,omitempty"`
+}
+
+type voteJSON struct {
+	TgUserID    int64     `json:"tg_user_id"`
+	TgUsername  string    `json:"tg_username,omitempty"`
+	TgFirstName string    `json:"tg_first_name"`
+	GameNick    string    `json:"game_nick,omitempty"`
+	Gender      string    `json:"gender,omitempty"`
+	Option      string    `json:"option"`
+	Guests      int       `json:"guests,omitempty"`
+	IsManual    bool      `json:"is_manual"`
+	VotedAt     time.Time `json:"voted_at"`
+}
+
+func newPollJSON(p *poll.Poll) pollJSON {
+	return pollJSON{
+		ID:          p.ID,
+		Club:        p.Club,
+		ChatID:      p.TgChatID,
+		EventDate:   p.EventDate.Format(time.DateOnly),
+		IsActive:    p.IsActive,
+		IsCancelled: p.IsCancelled(),
+		StartTime:   p.StartTime,
+		Price:       p.Price,
+		JudgeUserID: p.JudgeUserID,
+		JudgeName:   p.JudgeName,
+	}
+}
+
+// handleClubs lists the clubs: GET /api/clubs
+func (s *Server) handleClubs(w http.ResponseWriter, r *http.Request) {
+	if s.chats != nil {
+		writeJSON(w, http.StatusOK, s.chats.Clubs())
+		return
+	}
+
+	clubs := make([]Club, len(poll.Clubs))
+	for i, club := range poll.Clubs {
+		clubs[i] = Club{Club: club, Name: string(club), Chats: []int64{}}
+	}
+	writeJSON(w, http.StatusOK, clubs)
+}
+
+// handlePolls lists the club's polls, optionally by event date: GET /api/clubs/{club}/polls?from=2025-01-01&to=2025-03-31
+func (s *Server) handlePolls(w http.ResponseWriter, r *http.Request) {
+	club, ok := pathClub(w, r)
+	if !ok {
+		return
+	}
+	period, err := export.ParsePeriod(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	polls, err := s.svc.ListPolls(club, period)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	out := make([]pollJSON, len(polls))
+	for i, p := range polls {
+		out[i] = newPollJSON(p)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handlePoll returns a poll with its current votes and the voters' nicknames: GET /api/polls/{id}
+func (s *Server) handlePoll(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid poll id")
+		return
+	}
+	p, err := s.svc.GetPollByID(id)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	if p == nil {
+		writeError(w, http.StatusNotFound, "poll not found")
+		return
+	}
+
+	ep, err := s.svc.ExportPoll(p)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	out := newPollJSON(p)
+	out.Votes = make([]voteJSON, len(ep.Votes))
+	for i, v := range ep.Votes {
+		out.Votes[i] = voteJSON{
+			TgUserID:    v.TgUserID,
+			TgUsername:  v.TgUsername,
+			TgFirstName: v.TgFirstName,
+			GameNick:    v.Nickname.Nick,
+			Gender:      v.Nickname.Gender,
+			Option:      export.OptionName(v.OptionKind()),
+			Guests:      v.Guests,
+			IsManual:    v.IsManual,
+			VotedAt:     v.VotedAt,
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleCancel cancels the chat's active poll like /cancel: POST /api/chats/{chat}/cancel
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.pathChat(w, r)
+	if !ok {
+		return
+	}
+	p, err := s.svc.CancelPoll(chatID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.logger.Info("poll cancelled via api", "chat_id", chatID, "poll_id", p.ID)
+	if s.chats != nil {
+		s.chats.PollCancelled(p)
+	}
+	writeJSON(w, http.StatusOK, newPollJSON(p))
+}
+
+// handleRestore restores the chat's latest cancelled poll like /restore: POST /api/chats/{chat}/restore
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	chatID, ok := s.pathChat(w, r)
+	if !ok {
+		return
+	}
+	p, err := s.svc.RestorePoll(chatID)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+
+	s.logger.Info("poll restored via api", "chat_id", chatID, "poll_id", p.ID)
+	if s.chats != nil {
+		s.chats.PollRestored(p)
+	}
+	writeJSON(w, http.StatusOK, newPollJSON(p))
+}
+
+// pathChat parses the {chat} path value, writing 404 for chats not registered with the bot.
+func (s *Server) pathChat(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	chatID, err := strconv.ParseInt(r.PathValue("chat"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid chat id")
+		return 0, false
+	}
+	if s.chats != nil && !slices.ContainsFunc(s.chats.Clubs(), func(c Club) bool { return slices.Contains(c.Chats, chatID) }) {
+		writeError(w, http.StatusNotFound, "unknown chat")
+		return 0, false
+	}
+	return chatID, true
+}
@@ -0,0 +1,139 @@
+// Package api serves a JSON admin API over poll.Service: clubs and their polls, current votes
+// with nicknames, nickname management and poll cancel/restore. It shares the service layer
+// with the Telegram handlers, and the bot keeps the club chats in sync through Chats.
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Club is a club served by the bot with its registered Telegram chats.
+type Club struct {
+	Club  poll.Club `json:"club"`
+	Name  string    `json:"name"`
+	Chats []int64   `json:"chats"`
+}
+
+// Chats is the Telegram side of the API: the registered club chats and the bot messages in them.
+// The bot implements it; without one the API only reads and changes the database.
+type Chats interface {
+	// Clubs lists the clubs with their registered chats.
+	Clubs() []Club
+	// PollCancelled posts the cancellation of a poll cancelled through the API to its chat.
+	PollCancelled(p *poll.Poll)
+	// PollRestored posts the restore of a poll restored through the API to its chat.
+	PollRestored(p *poll.Poll)
+	// NicknamesChanged refreshes the messages of the club's active polls,
+	// or of every club for poll.SharedNicknames.
+	NicknamesChanged(club poll.Club)
+}
+
+// Server handles admin API requests.
+type Server struct {
+	svc    *poll.Service
+	chats  Chats
+	token  string
+	logger *slog.Logger
+}
+
+// New creates an API server. Every request must carry "Authorization: Bearer <token>";
+// with an empty token all requests are rejected. chats may be nil.
+func New(svc *poll.Service, chats Chats, token string, logger *slog.Logger) *Server {
+	return &Server{svc: svc, chats: chats, token: token, logger: logger}
+}
+
+// Handler returns the HTTP handler serving the API under /api/.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/clubs", s.handleClubs)
+	mux.HandleFunc("GET /api/clubs/{club}/polls", s.handlePolls)
+	mux.HandleFunc("GET /api/polls/{id}", s.handlePoll)
+	mux.HandleFunc("POST /api/chats/{chat}/cancel", s.handleCancel)
+	mux.HandleFunc("POST /api/chats/{chat}/restore", s.handleRestore)
+	mux.HandleFunc("GET /api/clubs/{club}/nicknames", s.handleNicknames)
+	mux.HandleFunc("POST /api/clubs/{club}/nicknames", s.handleCreateNickname)
+	mux.HandleFunc("PATCH /api/clubs/{club}/nicknames/{nick}", s.handleUpdateNickname)
+	mux.HandleFunc("DELETE /api/clubs/{club}/nicknames/{nick}", s.handleDeleteNickname)
+	return s.authorize(mux)
+}
+
+// authorize checks the bearer token. The client address is not trusted: behind a reverse proxy
+// on the same host every request comes from loopback.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			s.logger.Warn("unauthorized api request", "remote_addr", r.RemoteAddr, "method", r.Method, "path", r.URL.Path)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// errorResponse is the body of every failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeServiceError maps service errors to HTTP statuses; unexpected errors are logged
+// and reported without details.
+func (s *Server) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, poll.ErrNickNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, poll.ErrNoActivePoll),
+		errors.Is(err, poll.ErrNoCancelledPoll),
+		errors.Is(err, poll.ErrPollDatePassed),
+		errors.Is(err, poll.ErrNickTaken),
+		errors.Is(err, poll.ErrNickHasHistory):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		s.logger.Error("api request failed", "error", err, "method", r.Method, "path", r.URL.Path)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// pathClub parses the {club} path value, writing 404 for unknown clubs.
+func pathClub(w http.ResponseWriter, r *http.Request) (poll.Club, bool) {
+	club, ok := poll.ParseClub(r.PathValue("club"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown club")
+	}
+	return club, ok
+}
+
+// decodeJSON decodes a request body, writing 400 if it is not valid JSON of the expected shape.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+	"nuclight.org/consigliere/internal/storage"
+)
+
+const (
+	testChatID = int64(-100123)
+	testToken  = "secret"
+)
+
+func newTestService(t *testing.T) *poll.Service {
+	t.Helper()
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	return poll.NewService(poll.Repositories{
+		Polls:        storage.NewPollRepository(db),
+		Votes:        storage.NewVoteRepository(db),
+		Nicknames:    storage.NewNicknameRepository(db),
+		Attendance:   storage.NewAttendanceRepository(db),
+		Stats:        storage.NewStatsRepository(db),
+		Games:        storage.NewGameRepository(db),
+		Seating:      storage.NewSeatingRepository(db),
+		Payments:     storage.NewPaymentRepository(db),
+		Guests:       storage.NewGuestRepository(db),
+		NickRequests: storage.NewNicknameRequestRepository(db),
+	})
+}
+
+// fakeChats records the chat updates requested by the API.
+type fakeChats struct {
+	cancelled []int64
+	restored  []int64
+	refreshed []poll.Club
+}
+
+func (f *fakeChats) Clubs() []Club {
+	return []Club{{Club: poll.ClubVanmo, Name: "VANMO", Chats: []int64{testChatID}}}
+}
+func (f *fakeChats) PollCancelled(p *poll.Poll)      { f.cancelled = append(f.cancelled, p.ID) }
+func (f *fakeChats) PollRestored(p *poll.Poll)       { f.restored = append(f.restored, p.ID) }
+func (f *fakeChats) NicknamesChanged(club poll.Club) { f.refreshed = append(f.refreshed, club) }
+
+func newTestServer(t *testing.T, token string) (*Server, *poll.Service, *fakeChats) {
+	svc := newTestService(t)
+	chats := &fakeChats{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(svc, chats, token, logger), svc, chats
+}
+
+// do sends an authorized request and decodes a JSON response into out, if given.
+func do(t *testing.T, h http.Handler, method, path, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		remoteAddr string
+		header     string
+		want       int
+	}{
+		{"loopback without token", "", "127.0.0.1:1234", "", http.StatusUnauthorized},
+		{"loopback without token, bearer sent", "", "127.0.0.1:1234", "Bearer ", http.StatusUnauthorized},
+		{"remote without token", "", "192.0.2.1:1234", "", http.StatusUnauthorized},
+		{"loopback with token", "secret", "[::1]:1234", "Bearer secret", http.StatusOK},
+		{"remote with token", "secret", "192.0.2.1:1234", "Bearer secret", http.StatusOK},
+		{"wrong token", "secret", "127.0.0.1:1234", "Bearer wrong", http.StatusUnauthorized},
+		{"missing token", "secret", "127.0.0.1:1234", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _, _ := newTestServer(t, tt.token)
+			req := httptest.NewRequest(http.MethodGet, "/api/clubs", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollEndpoints(t *testing.T) {
+	srv, svc, chats := newTestServer(t, testToken)
+	h := srv.Handler()
+
+	eventDate := time.Now().AddDate(0, 0, 3)
+	result, err := svc.CreatePoll(testChatID, eventDate, poll.ClubVanmo, 20)
+	if err != nil {
+		t.Fatalf("CreatePoll failed: %v", err)
+	}
+	p := result.Poll
+	if err := svc.RecordVote(&poll.Vote{PollID: p.ID, TgUserID: 1, TgUsername: "alice", TgFirstName: "Alice", TgOptionIndex: int(poll.OptionComeAt20), VotedAt: time.Now()}); err != nil {
+		t.Fatalf("RecordVote failed: %v", err)
+	}
+	if _, err := svc.CreateNickname(poll.ClubVanmo, nil, ptr("alice"), "Секртис", "female", 0); err != nil {
+		t.Fatalf("CreateNickname failed: %v", err)
+	}
+
+	var clubs []Club
+	if code := do(t, h, http.MethodGet, "/api/clubs", "", &clubs); code != http.StatusOK || len(clubs) != 1 {
+		t.Fatalf("GET /api/clubs = %d %+v", code, clubs)
+	}
+
+	var polls []pollJSON
+	if code := do(t, h, http.MethodGet, "/api/clubs/vanmo/polls", "", &polls); code != http.StatusOK {
+		t.Fatalf("GET polls = %d", code)
+	}
+	if len(polls) != 1 || polls[0].ID != p.ID || !polls[0].IsActive || polls[0].Price != 20 {
+		t.Errorf("polls = %+v", polls)
+	}
+	if code := do(t, h, http.MethodGet, "/api/clubs/nope/polls", "", nil); code != http.StatusNotFound {
+		t.Errorf("GET unknown club polls = %d, want 404", code)
+	}
+	if code := do(t, h, http.MethodGet, "/api/clubs/vanmo/polls?from=2000-13-01", "", nil); code != http.StatusBadRequest {
+		t.Errorf("GET polls with bad date = %d, want 400", code)
+	}
+
+	var got pollJSON
+	if code := do(t, h, http.MethodGet, "/api/polls/"+strconv.FormatInt(p.ID, 10), "", &got); code != http.StatusOK {
+		t.Fatalf("GET poll = %d", code)
+	}
+	if len(got.Votes) != 1 || got.Votes[0].GameNick != "Секртис" || got.Votes[0].Option != "20:00" {
+		t.Errorf("votes = %+v", got.Votes)
+	}
+	if code := do(t, h, http.MethodGet, "/api/polls/999", "", nil); code != http.StatusNotFound {
+		t.Errorf("GET missing poll = %d, want 404", code)
+	}
+
+	chat := "/api/chats/" + strconv.FormatInt(testChatID, 10)
+	if code := do(t, h, http.MethodPost, chat+"/restore", "", nil); code != http.StatusConflict {
+		t.Errorf("restore active poll = %d, want 409", code)
+	}
+	if code := do(t, h, http.MethodPost, chat+"/cancel", "", &got); code != http.StatusOK || got.IsActive {
+		t.Errorf("cancel = %d %+v", code, got)
+	}
+	if code := do(t, h, http.MethodPost, chat+"/cancel", "", nil); code != http.StatusConflict {
+		t.Errorf("cancel again = %d, want 409", code)
+	}
+	if code := do(t, h, http.MethodPost, chat+"/restore", "", &got); code != http.StatusOK || !got.IsActive {
+		t.Errorf("restore = %d %+v", code, got)
+	}
+	if code := do(t, h, http.MethodPost, "/api/chats/-1/cancel", "", nil); code != http.StatusNotFound {
+		t.Errorf("cancel in unknown chat = %d, want 404", code)
+	}
+
+	if len(chats.cancelled) != 1 || len(chats.restored) != 1 {
+		t.Errorf("chat updates: cancelled %v, restored %v", chats.cancelled, chats.restored)
+	}
+}
+
+func TestNicknameEndpoints(t *testing.T) {
+	srv, _, chats := newTestServer(t, testToken)
+	h := srv.Handler()
+	base := "/api/clubs/vanmo/nicknames"
+
+	var created nicknameJSON
+	if code := do(t, h, http.MethodPost, base, `{"tg_username":"Alice","game_nick":"Секртис","gender":"female"}`, &created); code != http.StatusCreated {
+		t.Fatalf("POST nickname = %d", code)
+	}
+	if created.TgUsername != "alice" || created.Shared {
+		t.Errorf("created = %+v", created)
+	}
+	if code := do(t, h, http.MethodPost, base, `{"tg_user_id":2,"game_nick":"Секртис"}`, nil); code != http.StatusConflict {
+		t.Errorf("POST taken nickname = %d, want 409", code)
+	}
+	if code := do(t, h, http.MethodPost, base, `{"game_nick":"Кот"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("POST without user = %d, want 400", code)
+	}
+	if code := do(t, h, http.MethodPost, base, `{"tg_user_id":2,"game_nick":"Кот","gender":"x"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("POST with bad gender = %d, want 400", code)
+	}
+	if code := do(t, h, http.MethodPost, base, `{"tg_user_id":3,"game_nick":"Пёс","shared":true}`, nil); code != http.StatusCreated {
+		t.Errorf("POST shared nickname = %d", code)
+	}
+
+	if code := do(t, h, http.MethodPatch, base+"/"+url.PathEscape("Секртис"), `{"game_nick":"Мадам Жу","gender":""}`, nil); code != http.StatusNoContent {
+		t.Errorf("PATCH nickname = %d", code)
+	}
+	if code := do(t, h, http.MethodPatch, base+"/"+url.PathEscape("Нет"), `{"gender":"male"}`, nil); code != http.StatusNotFound {
+		t.Errorf("PATCH missing nickname = %d, want 404", code)
+	}
+	if code := do(t, h, http.MethodPatch, base+"/"+url.PathEscape("Пёс"), `{"gender":"male"}`, nil); code != http.StatusNoContent {
+		t.Errorf("PATCH shared nickname = %d", code)
+	}
+
+	var list []nicknameJSON
+	if code := do(t, h, http.MethodGet, base, "", &list); code != http.StatusOK {
+		t.Fatalf("GET nicknames = %d", code)
+	}
+	want := map[string]nicknameJSON{
+		"Мадам Жу": {GameNick: "Мадам Жу", TgUsername: "alice"},
+		"Пёс":      {GameNick: "Пёс", Gender: "male", TgUserID: 3, Shared: true},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("nicknames = %+v", list)
+	}
+	for _, n := range list {
+		if n != want[n.GameNick] {
+			t.Errorf("nickname %q = %+v, want %+v", n.GameNick, n, want[n.GameNick])
+		}
+	}
+
+	if code := do(t, h, http.MethodDelete, base+"/"+url.PathEscape("Мадам Жу"), "", nil); code != http.StatusNoContent {
+		t.Errorf("DELETE nickname = %d", code)
+	}
+	if code := do(t, h, http.MethodDelete, base+"/"+url.PathEscape("Мадам Жу"), "", nil); code != http.StatusNotFound {
+		t.Errorf("DELETE deleted nickname = %d, want 404", code)
+	}
+	if code := do(t, h, http.MethodDelete, base+"/"+url.PathEscape("Пёс"), "", nil); code != http.StatusNoContent {
+		t.Errorf("DELETE shared nickname = %d", code)
+	}
+
+	wantRefreshed := []poll.Club{poll.ClubVanmo, poll.SharedNicknames, poll.ClubVanmo, poll.SharedNicknames, poll.ClubVanmo, poll.SharedNicknames}
+	if len(chats.refreshed) != len(wantRefreshed) {
+		t.Fatalf("refreshed = %v, want %v", chats.refreshed, wantRefreshed)
+	}
+	for i := range wantRefreshed {
+		if chats.refreshed[i] != wantRefreshed[i] {
+			t.Errorf("refreshed = %v, want %v", chats.refreshed, wantRefreshed)
+			break
+		}
+	}
+}
+
+func ptr(s string) *string { return &s }
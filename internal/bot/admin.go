@@ -6,6 +6,8 @@ import (
 
 	"golang.org/x/time/rate"
 	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/metrics"
 )
 
 // Rate limiting constants
@@ -45,6 +47,7 @@ func (b *Bot) RateLimit() tele.MiddlewareFunc {
 		return func(c tele.Context) error {
 			limiter := b.rateLimiter.getLimiter(c.Sender().ID)
 			if !limiter.Allow() {
+				metrics.RateLimited.Inc()
 				b.logger.Warn("rate limit exceeded",
 					"user_id", c.Sender().ID,
 					"username", c.Sender().Username,
@@ -101,6 +104,14 @@ func extractCommand(text string) string {
 	return firstWord
 }
 
+// commandName names a request for metrics: the command for messages, the button for callbacks.
+func commandName(c tele.Context) string {
+	if cb := c.Callback(); cb != nil {
+		return cb.Unique
+	}
+	return extractCommand(c.Text())
+}
+
 // indexAny returns the index of the first occurrence of any character in chars, or -1 if not found.
 func indexAny(s, chars string) int {
 	for i, c := range s {
@@ -134,26 +145,42 @@ func (b *Bot) LogCommand() tele.MiddlewareFunc {
 // - UserError: sends the user-friendly message (temporarily), logs if there's an underlying cause
 // - Other errors: sends a generic error message (temporarily), logs the full error
 // Error messages are automatically deleted after the configured TempMessageDelay.
+// For button callbacks the message is shown as an alert instead, which also stops the button's spinner.
+// Every request is counted in the commands metric by its outcome.
 func (b *Bot) HandleErrors() tele.MiddlewareFunc {
 	return func(next tele.HandlerFunc) tele.HandlerFunc {
 		return func(c tele.Context) error {
 			err := next(c)
 			if err == nil {
+				metrics.Commands.WithLabelValues(commandName(c), metrics.OutcomeOK).Inc()
 				return nil
 			}
 
 			// Log the error if needed
 			if ShouldLog(err) {
+				metrics.Commands.WithLabelValues(commandName(c), metrics.OutcomeError).Inc()
 				b.logger.Error("command error",
 					"error", GetLogError(err),
 					"chat_id", c.Chat().ID,
 					"user_id", c.Sender().ID,
 					"command", c.Text(),
 				)
+			} else {
+				metrics.Commands.WithLabelValues(commandName(c), metrics.OutcomeUserError).Inc()
 			}
 
-			// Send user-friendly message (temporary, silent to avoid disturbing chat members)
 			userMsg := GetUserMessage(err)
+			if c.Callback() != nil {
+				if respErr := c.Respond(&tele.CallbackResponse{Text: userMsg, ShowAlert: true}); respErr != nil {
+					b.logger.Error("failed to answer callback with error",
+						"error", respErr,
+						"original_error", err,
+					)
+				}
+				return nil
+			}
+
+			// Send user-friendly message (temporary, silent to avoid disturbing chat members)
 			msg, sendErr := b.SendWithRetry(c.Chat(), userMsg, tele.Silent)
 			if sendErr != nil {
 				b.logger.Error("failed to send error message to user",
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"slices"
+
+	"nuclight.org/consigliere/internal/api"
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// The bot keeps club chats in sync with changes made through the admin API.
+var _ api.Chats = (*Bot)(nil)
+
+// Clubs lists the configured clubs with their registered chats.
+func (b *Bot) Clubs() []api.Club {
+	clubs := make([]api.Club, len(clubConfigs))
+	for i, config := range clubConfigs {
+		clubs[i] = api.Club{Club: config.Club, Name: config.Name, Chats: []int64{}}
+		for chatID, chatConfig := range chatRegistry {
+			if chatConfig == config {
+				clubs[i].Chats = append(clubs[i].Chats, chatID)
+			}
+		}
+		slices.Sort(clubs[i].Chats)
+	}
+	return clubs
+}
+
+// PollCancelled posts the cancellation like /cancel does.
+func (b *Bot) PollCancelled(p *poll.Poll) {
+	config := chatRegistry[p.TgChatID]
+	if config == nil {
+		b.logger.Warn("no club config for chat, skipping cancellation", "chat_id", p.TgChatID)
+		return
+	}
+	if err := b.announceCancellation(p, config); err != nil {
+		b.logger.Warn("failed to announce cancellation", "error", err, "poll_id", p.ID)
+	}
+}
+
+// PollRestored posts the restore like /restore does.
+func (b *Bot) PollRestored(p *poll.Poll) {
+	config := chatRegistry[p.TgChatID]
+	if config == nil {
+		b.logger.Warn("no club config for chat, skipping restore", "chat_id", p.TgChatID)
+		return
+	}
+	if err := b.announceRestore(p, config); err != nil {
+		b.logger.Warn("failed to announce restore", "error", err, "poll_id", p.ID)
+	}
+}
+
+// NicknamesChanged refreshes the active poll messages in the club's chats,
+// or in every chat for shared nicknames.
+func (b *Bot) NicknamesChanged(club poll.Club) {
+	for chatID, config := range chatRegistry {
+		if club == poll.SharedNicknames || config.Club == club {
+			b.refreshPollMessages(chatID, config)
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package bot
 
 import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
 	"time"
 
 	tele "gopkg.in/telebot.v4"
 
 	"nuclight.org/consigliere/internal/config"
+	"nuclight.org/consigliere/internal/metrics"
 	"nuclight.org/consigliere/internal/poll"
 )
 
@@ -22,13 +31,25 @@ type Bot struct {
 	pollService      *poll.Service
 	logger           *slog.Logger
 	rateLimiter      *rateLimiter
+	pendingVotes     *pendingVotes
 	tempMessageDelay time.Duration
+	updates          *updatesTracker
+	watchdogTimeout  time.Duration
+	watchdogExit     bool
+	webhook          bool         // updates come with a webhook instead of long polling
+	webhookServer    *http.Server // serves the webhook on webhookListener (nil with long polling)
+	webhookListener  net.Listener
+	stop             chan struct{}
 }
 
+// New creates the bot. It receives updates with a webhook if cfg.WebhookURL is set
+// and with long polling otherwise; both run the same handlers and middleware.
 func New(cfg *config.Config, pollService *poll.Service, logger *slog.Logger) (*Bot, error) {
+	updates := newUpdatesTracker(http.DefaultTransport)
 	pref := tele.Settings{
 		Token:  cfg.TelegramToken,
-		Poller: &tele.LongPoller{Timeout: cfg.PollingTimeout},
+		Poller: newPoller(cfg),
+		Client: &http.Client{Timeout: time.Minute, Transport: updates}, // telebot's default timeout
 	}
 
 	b, err := tele.NewBot(pref)
@@ -36,21 +57,126 @@ func New(cfg *config.Config, pollService *poll.Service, logger *slog.Logger) (*B
 		return nil, err
 	}
 
-	return &Bot{
+	bot := &Bot{
 		bot:              b,
 		pollService:      pollService,
 		logger:           logger,
 		rateLimiter:      newRateLimiter(),
+		pendingVotes:     newPendingVotes(),
 		tempMessageDelay: cfg.TempMessageDelay,
-	}, nil
+		updates:          updates,
+		watchdogTimeout:  cfg.WatchdogTimeout,
+		watchdogExit:     cfg.WatchdogExit,
+		stop:             make(chan struct{}),
+	}
+
+	// Bind the webhook listener and register the webhook here rather than in the poller,
+	// so a taken port, a bad certificate or a refused URL stops the startup.
+	// Long polling needs the webhook removed, or getUpdates is refused while it's set.
+	webhook, isWebhook := pref.Poller.(*tele.Webhook)
+	if !isWebhook {
+		if err := b.RemoveWebhook(); err != nil {
+			return nil, fmt.Errorf("remove webhook: %w", err)
+		}
+		return bot, nil
+	}
+	listener, err := listenWebhook(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.SetWebhook(webhook); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("set webhook: %w", err)
+	}
+	bot.webhook = true
+	bot.webhookListener = listener
+	bot.webhookServer = &http.Server{
+		Handler:           bot.webhookHandler(cfg.WebhookSecret),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return bot, nil
+}
+
+// newPoller returns the webhook configured in cfg, or a long poller without one.
+// The webhook's poller only waits to be stopped: the bot serves the webhook itself.
+func newPoller(cfg *config.Config) tele.Poller {
+	if cfg.WebhookURL == "" {
+		return &tele.LongPoller{Timeout: cfg.PollingTimeout}
+	}
+	return &tele.Webhook{
+		SecretToken:      cfg.WebhookSecret,
+		IgnoreSetWebhook: true, // set by New
+		Endpoint:         &tele.WebhookEndpoint{PublicURL: cfg.WebhookURL},
+	}
+}
+
+// listenWebhook binds the webhook listen address, over TLS if cfg has a certificate.
+func listenWebhook(cfg *config.Config) (net.Listener, error) {
+	var tlsConfig *tls.Config
+	if cfg.WebhookTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.WebhookTLSCert, cfg.WebhookTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("load webhook certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+
+	listener, err := net.Listen("tcp", cfg.WebhookListen)
+	if err != nil {
+		return nil, fmt.Errorf("listen for webhook: %w", err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	return listener, nil
+}
+
+// webhookHandler accepts the updates Telegram posts to the webhook and queues them
+// for the handlers, as telebot's own webhook server does.
+func (b *Bot) webhookHandler(secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var update tele.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			b.logger.Warn("failed to decode webhook update", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b.bot.Updates <- update
+	})
 }
 
 func (b *Bot) Start() {
-	b.logger.Info("bot started")
+	b.logger.Info("bot started", "webhook", b.webhook)
+	b.updates.touch(time.Now())
+	go b.runMonthlyReports()
+	if !b.webhook {
+		go b.runWatchdog()
+	}
+	if b.webhookServer != nil {
+		go func() {
+			if err := b.webhookServer.Serve(b.webhookListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				b.logger.Error("webhook server failed", "error", err)
+			}
+		}()
+	}
 	b.bot.Start()
 }
 
 func (b *Bot) Stop() {
+	close(b.stop)
+	if b.webhookServer != nil {
+		// Finish the updates being received while the bot still handles them
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := b.webhookServer.Shutdown(ctx); err != nil {
+			b.logger.Warn("failed to shut down webhook server", "error", err)
+		}
+	}
 	b.bot.Stop()
 }
 
@@ -69,6 +195,13 @@ func isNotModifiedErr(err error) bool {
 	return errors.Is(err, tele.ErrSameMessageContent) || errors.Is(err, tele.ErrMessageNotModified)
 }
 
+// isForbiddenErr returns true if Telegram refuses to message a user privately:
+// the user has not started the bot, blocked it or was deactivated. Retrying won't help.
+func isForbiddenErr(err error) bool {
+	var tgErr *tele.Error
+	return errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden
+}
+
 func (b *Bot) Bot() *tele.Bot {
 	return b.bot
 }
@@ -88,11 +221,14 @@ func (b *Bot) SendWithRetry(to tele.Recipient, what any, opts ...any) (*tele.Mes
 	delay := InitialRetryDelay
 
 	for attempt := 0; attempt <= MaxRetries; attempt++ {
+		start := time.Now()
 		msg, err = b.bot.Send(to, what, opts...)
+		metrics.TelegramSendDuration.Observe(time.Since(start).Seconds())
 		if err == nil {
 			return msg, nil
 		}
 
+		metrics.TelegramSendErrors.WithLabelValues(strconv.FormatBool(attempt == MaxRetries)).Inc()
 		if attempt < MaxRetries {
 			b.logger.Warn("telegram send failed, retrying",
 				"error", err,
@@ -181,12 +317,17 @@ func (b *Bot) GetActivePollForAction(chatID int64) (*poll.Poll, error) {
 // Returns the sent message (for cases that need the message ID) and any error.
 // Errors are wrapped with appropriate user-facing messages.
 func (b *Bot) RenderAndSend(c tele.Context, renderFunc func() (string, error), renderErrMsg, sendErrMsg string) (*tele.Message, error) {
+	return b.renderAndSendTo(c.Chat(), renderFunc, renderErrMsg, sendErrMsg)
+}
+
+// renderAndSendTo is RenderAndSend for a recipient other than the command's chat.
+func (b *Bot) renderAndSendTo(to tele.Recipient, renderFunc func() (string, error), renderErrMsg, sendErrMsg string) (*tele.Message, error) {
 	html, err := renderFunc()
 	if err != nil {
 		return nil, WrapUserError(renderErrMsg, err)
 	}
 
-	msg, err := b.SendWithRetry(c.Chat(), html, tele.ModeHTML)
+	msg, err := b.SendWithRetry(to, html, tele.ModeHTML)
 	if err != nil {
 		return nil, WrapUserError(sendErrMsg, err)
 	}
@@ -220,13 +361,19 @@ func (b *Bot) UpdateInvitationMessage(p *poll.Poll, isCancelledOverride *bool) b
 		return false
 	}
 
-	html, err := b.RenderInvitationMessageWithNicks(clubConfig.templates, results)
+	html, err := b.RenderInvitationMessageWithNicks(clubConfig.Club, clubConfig.templates, results)
 	if err != nil {
 		b.logger.Warn("failed to render invitation", "error", err, "poll_id", p.ID)
 		return false
 	}
 
-	if _, err = b.bot.Edit(MessageRef(p.TgChatID, p.TgInvitationMessageID), html, tele.ModeHTML); err != nil {
+	// Editing without a keyboard removes it, which is what a cancelled poll needs
+	opts := []any{tele.ModeHTML}
+	if p.HasInlineVoting() && !results.IsCancelled {
+		opts = append(opts, voteKeyboard(p))
+	}
+
+	if _, err = b.bot.Edit(MessageRef(p.TgChatID, p.TgInvitationMessageID), html, opts...); err != nil {
 		if isNotModifiedErr(err) {
 			return true
 		}
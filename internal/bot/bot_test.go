@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/config"
+)
+
+func TestListenWebhook(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer taken.Close()
+
+	if _, err := listenWebhook(&config.Config{WebhookListen: taken.Addr().String()}); err == nil {
+		t.Error("expected an error for a taken port")
+	}
+	bad := &config.Config{WebhookListen: "127.0.0.1:0", WebhookTLSCert: "missing.pem", WebhookTLSKey: "missing.key"}
+	if _, err := listenWebhook(bad); err == nil {
+		t.Error("expected an error for a missing certificate")
+	}
+
+	listener, err := listenWebhook(&config.Config{WebhookListen: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("listenWebhook failed: %v", err)
+	}
+	listener.Close()
+}
+
+func TestBot_WebhookHandler(t *testing.T) {
+	b := &Bot{
+		bot:    &tele.Bot{Updates: make(chan tele.Update, 1)},
+		logger: slog.New(slog.DiscardHandler),
+	}
+	handler := b.webhookHandler("secret")
+
+	post := func(token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(body))
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("wrong", `{"update_id": 1}`); code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code := post("secret", `{`); code != http.StatusBadRequest {
+		t.Errorf("bad body: status %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := post("secret", `{"update_id": 7}`); code != http.StatusOK {
+		t.Errorf("update: status %d, want %d", code, http.StatusOK)
+	}
+	select {
+	case u := <-b.bot.Updates:
+		if u.ID != 7 {
+			t.Errorf("queued update %d, want 7", u.ID)
+		}
+	default:
+		t.Error("update was not queued")
+	}
+}
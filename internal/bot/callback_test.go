@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+// apiCall is a Bot API request received by the fake Telegram server.
+type apiCall struct {
+	Method string
+	Params map[string]any
+}
+
+// newOfflineBot returns a bot whose Telegram API calls go to a fake server that records them.
+func newOfflineBot(t *testing.T) (*Bot, func() []apiCall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []apiCall
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := apiCall{Method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]}
+		_ = json.NewDecoder(r.Body).Decode(&call.Params)
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+		_, _ = io.WriteString(w, `{"ok":true,"result":true}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	tb, err := tele.NewBot(tele.Settings{Token: "123:test", URL: srv.URL, Offline: true})
+	if err != nil {
+		t.Fatalf("NewBot failed: %v", err)
+	}
+	b := &Bot{bot: tb, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	return b, func() []apiCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]apiCall(nil), calls...)
+	}
+}
+
+func callbackContext(b *Bot, userID int64) tele.Context {
+	return b.bot.NewContext(tele.Update{Callback: &tele.Callback{
+		ID:      "cb1",
+		Sender:  &tele.User{ID: userID},
+		Message: &tele.Message{ID: 10, Chat: &tele.Chat{ID: ChatVanmo}},
+	}})
+}
+
+// assertAlert checks that the only API call answered the callback with an alert showing text.
+func assertAlert(t *testing.T, calls []apiCall, text string) {
+	t.Helper()
+	if len(calls) != 1 || calls[0].Method != "answerCallbackQuery" {
+		t.Fatalf("API calls = %+v, want one answerCallbackQuery", calls)
+	}
+	if calls[0].Params["text"] != text || calls[0].Params["show_alert"] != true {
+		t.Errorf("callback answer = %+v, want alert %q", calls[0].Params, text)
+	}
+}
+
+func TestHandleErrors_AnswersCallback(t *testing.T) {
+	b, calls := newOfflineBot(t)
+	handler := b.HandleErrors()(func(tele.Context) error { return UserErrorf(MsgNoActivePoll) })
+
+	if err := handler(callbackContext(b, 1)); err != nil {
+		t.Fatalf("HandleErrors returned %v", err)
+	}
+	assertAlert(t, calls(), MsgNoActivePoll)
+}
+
+func TestClubAdminOnly_AnswersCallbackOfNonAdmin(t *testing.T) {
+	b, calls := newOfflineBot(t)
+	called := false
+	handler := b.ClubAdminOnly()(func(tele.Context) error { called = true; return nil })
+
+	c := callbackContext(b, 1)
+	c.Set("club", vanmoConfig)
+	if err := handler(c); err != nil {
+		t.Fatalf("ClubAdminOnly returned %v", err)
+	}
+	if called {
+		t.Error("handler called for a non-admin")
+	}
+	assertAlert(t, calls(), MsgAdminsOnly)
+}
@@ -1,8 +1,10 @@
 package bot
 
 import (
-	"slices"
+	"context"
+	"fmt"
 	"html/template"
+	"slices"
 	"time"
 
 	tele "gopkg.in/telebot.v4"
@@ -21,7 +23,11 @@ const ChatAntispamTest = -1002544962928
 const ChatConsigliereTestTbilissimo = -1003733477508
 
 // FeatureFlags holds per-club feature toggles.
-type FeatureFlags struct{}
+type FeatureFlags struct {
+	BalanceTablesByRating bool // deal players to tables by /rating instead of vote order only
+	InlineVoting          bool // vote with buttons on the invitation message instead of a native poll
+	TextVoting            bool // record chat messages like "+", "-" or "буду в 21" as votes
+}
 
 // ClubConfig holds configuration for a club's chat groups.
 type ClubConfig struct {
@@ -29,7 +35,11 @@ type ClubConfig struct {
 	Name            string
 	DefaultWeekDays []time.Weekday
 	Admins          []int64
-	MediaDir        string // subdirectory under media/ for event videos (empty = no video)
+	JudgePool       []int64 // candidates for /judge suggestions (empty = club admins)
+	MediaDir        string  // subdirectory under media/ for event videos (empty = no video)
+	ReportChatID    int64   // chat for the scheduled monthly report (0 = disabled)
+	TableSize       int     // players per table in the collected message (0 = single list)
+	DefaultPrice    int     // price per player in ₾ for new polls (0 = not shown)
 	FeatureFlags    FeatureFlags
 	templates       *template.Template // unexported, accessed within bot package only
 }
@@ -42,7 +52,8 @@ var vanmoConfig = &ClubConfig{
 		UserSectris,
 		UserFrancuz,
 	},
-	MediaDir: "vanmo",
+	MediaDir:     "vanmo",
+	DefaultPrice: 20,
 }
 
 var tbilissimoConfig = &ClubConfig{
@@ -54,17 +65,40 @@ var tbilissimoConfig = &ClubConfig{
 		UserMamaLama,
 		UserKezlev,
 	},
+	DefaultPrice: 20,
 }
 
+// clubConfigs lists all club configurations.
+var clubConfigs = []*ClubConfig{vanmoConfig, tbilissimoConfig}
+
 // chatRegistry maps Telegram chat IDs to their club configuration.
 var chatRegistry = map[int64]*ClubConfig{
 	// todo: replace with real chat IDs
-	ChatVanmo: vanmoConfig,
-	ChatTbilissimo: tbilissimoConfig,
-	ChatAntispamTest: vanmoConfig,
+	ChatVanmo:                     vanmoConfig,
+	ChatTbilissimo:                tbilissimoConfig,
+	ChatAntispamTest:              vanmoConfig,
 	ChatConsigliereTestTbilissimo: tbilissimoConfig,
 }
 
+// judgePool returns the club's judge candidates, falling back to admins.
+func (c *ClubConfig) judgePool() []int64 {
+	if len(c.JudgePool) > 0 {
+		return c.JudgePool
+	}
+	return c.Admins
+}
+
+// isAllClubsAdmin reports whether the user administers every club, as changing
+// a shared nickname affects all of them.
+func isAllClubsAdmin(userID int64) bool {
+	for _, config := range clubConfigs {
+		if !slices.Contains(config.Admins, userID) {
+			return false
+		}
+	}
+	return true
+}
+
 // InitClubTemplates parses templates for all club configs.
 // Must be called at startup before handling any messages.
 func InitClubTemplates() error {
@@ -80,6 +114,22 @@ func InitClubTemplates() error {
 	return nil
 }
 
+// CheckTemplates returns an error if a club's templates were not initialized with InitClubTemplates
+// or lack a template the bot renders.
+func CheckTemplates(ctx context.Context) error {
+	for _, config := range clubConfigs {
+		if config.templates == nil {
+			return fmt.Errorf("templates of club %s are not initialized", config.Club)
+		}
+		for _, name := range clubTemplateNames {
+			if config.templates.Lookup(name) == nil {
+				return fmt.Errorf("template %s of club %s is not defined", name, config.Club)
+			}
+		}
+	}
+	return nil
+}
+
 // getClubConfig retrieves the ClubConfig stored in the telebot context.
 // Must only be called after ResolveClub middleware has run.
 func getClubConfig(c tele.Context) *ClubConfig {
@@ -106,7 +156,8 @@ func (b *Bot) ResolveClub() tele.MiddlewareFunc {
 }
 
 // ClubAdminOnly checks if the sender is in the club's admin list.
-// Non-admins are silently ignored (command is deleted but no error posted).
+// Non-admin commands are silently ignored (command is deleted but no error posted);
+// non-admin button presses get an alert, so the button doesn't keep spinning.
 func (b *Bot) ClubAdminOnly() tele.MiddlewareFunc {
 	return func(next tele.HandlerFunc) tele.HandlerFunc {
 		return func(c tele.Context) error {
@@ -124,6 +175,9 @@ func (b *Bot) ClubAdminOnly() tele.MiddlewareFunc {
 				"club", config.Club,
 				"command", c.Text(),
 			)
+			if c.Callback() != nil {
+				return c.Respond(&tele.CallbackResponse{Text: MsgAdminsOnly, ShowAlert: true})
+			}
 			return nil
 		}
 	}
@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Callback button identifiers for the attendance keyboard
+const (
+	callbackAttendToggle = "attend"
+	callbackAttendDone   = "attend_done"
+)
+
+// handleAttended posts the attendance checklist for the latest event.
+// Everyone who voted to attend is listed as an inline button; the host taps
+// a player to toggle whether they actually showed up.
+func (b *Bot) handleAttended(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.pollService.GetLatestPoll(c.Chat().ID)
+	if err != nil {
+		if errors.Is(err, poll.ErrNoActivePoll) {
+			return UserErrorf(MsgNoPoll)
+		}
+		return WrapUserError(MsgFailedGetPoll, err)
+	}
+
+	if p.IsCancelled() {
+		return UserErrorf(MsgEventCancelled)
+	}
+	if isPollDateInFuture(p.EventDate) {
+		return UserErrorf(MsgEventNotHeldYet)
+	}
+
+	entries, err := b.pollService.StartAttendance(p.ID)
+	if err != nil {
+		return WrapUserError(MsgFailedGetAttendance, err)
+	}
+	if len(entries) == 0 {
+		return UserErrorf(MsgNoAttendingVoters)
+	}
+
+	html, markup, err := b.renderAttendance(config, p, entries, true)
+	if err != nil {
+		return WrapUserError(MsgFailedRenderAttendance, err)
+	}
+
+	if _, err := b.SendWithRetry(c.Chat(), html, tele.ModeHTML, tele.Silent, markup); err != nil {
+		return WrapUserError(MsgFailedSendAttendance, err)
+	}
+	return nil
+}
+
+// handleAttendToggle toggles attendance for a single player from the checklist keyboard.
+// Callback data: <poll ID>|<user ID>
+func (b *Bot) handleAttendToggle(c tele.Context) error {
+	config := getClubConfig(c)
+
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+	userID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return c.Respond()
+	}
+
+	p, err := b.pollFromCallback(c, args[0])
+	if err != nil || p == nil {
+		return err
+	}
+
+	attended, err := b.pollService.ToggleAttendance(p.ID, userID, c.Sender().ID)
+	if errors.Is(err, poll.ErrNotAttendingVoter) {
+		return UserErrorf(MsgNotAttendingVoter)
+	}
+	if err != nil {
+		return WrapUserError(MsgFailedSaveAttendance, err)
+	}
+
+	b.logger.Info("attendance toggled",
+		"actor_user_id", c.Sender().ID,
+		"poll_id", p.ID,
+		"tg_user_id", userID,
+		"attended", attended,
+	)
+
+	if err := b.editAttendance(c, config, p, true); err != nil {
+		return err
+	}
+	return c.Respond()
+}
+
+// handleAttendDone finalizes the checklist by removing the keyboard.
+// Callback data: <poll ID>
+func (b *Bot) handleAttendDone(c tele.Context) error {
+	config := getClubConfig(c)
+
+	args := c.Args()
+	if len(args) != 1 {
+		return c.Respond()
+	}
+
+	p, err := b.pollFromCallback(c, args[0])
+	if err != nil || p == nil {
+		return err
+	}
+
+	if err := b.editAttendance(c, config, p, false); err != nil {
+		return err
+	}
+	return c.Respond()
+}
+
+// pollFromCallback loads the poll referenced by callback data and
+// verifies it belongs to the chat the button was pressed in.
+// Returns nil poll (and responds to the callback) if the poll is unknown.
+func (b *Bot) pollFromCallback(c tele.Context, pollIDArg string) (*poll.Poll, error) {
+	pollID, err := strconv.ParseInt(pollIDArg, 10, 64)
+	if err != nil {
+		return nil, c.Respond()
+	}
+
+	p, err := b.pollService.GetPollByID(pollID)
+	if err != nil {
+		return nil, WrapUserError(MsgFailedGetPoll, err)
+	}
+	if p == nil || p.TgChatID != c.Chat().ID {
+		return nil, c.Respond()
+	}
+	return p, nil
+}
+
+// editAttendance re-renders the checklist message in place.
+// If withKeyboard is false, the inline keyboard is removed.
+func (b *Bot) editAttendance(c tele.Context, config *ClubConfig, p *poll.Poll, withKeyboard bool) error {
+	entries, err := b.pollService.GetAttendanceEntries(p.ID)
+	if err != nil {
+		return WrapUserError(MsgFailedGetAttendance, err)
+	}
+
+	html, markup, err := b.renderAttendance(config, p, entries, withKeyboard)
+	if err != nil {
+		return WrapUserError(MsgFailedRenderAttendance, err)
+	}
+
+	if err := c.Edit(html, tele.ModeHTML, markup); err != nil && !isNotModifiedErr(err) {
+		return WrapUserError(MsgFailedSendAttendance, err)
+	}
+	return nil
+}
+
+// renderAttendance renders the checklist text and, optionally, its toggle keyboard.
+func (b *Bot) renderAttendance(config *ClubConfig, p *poll.Poll, entries []*poll.AttendanceEntry, withKeyboard bool) (string, *tele.ReplyMarkup, error) {
+	votes := make([]*poll.Vote, len(entries))
+	for i, e := range entries {
+		votes[i] = e.Vote
+	}
+	cache, err := b.pollService.NewNicknameCacheFromVotes(config.Club, votes)
+	if err != nil {
+		b.logger.Warn("failed to build nickname cache for attendance", "error", err)
+	}
+	members := b.membersFromVotesWithCache(config.Club, votes, cache)
+
+	data := &AttendanceData{EventDate: p.EventDate}
+	markup := &tele.ReplyMarkup{}
+	pollID := strconv.FormatInt(p.ID, 10)
+	buttons := make([]tele.Btn, 0, len(entries))
+
+	for i, e := range entries {
+		mark := "✅ "
+		if e.Attended {
+			data.Attended = append(data.Attended, members[i])
+		} else {
+			mark = "❌ "
+			data.NoShows = append(data.NoShows, members[i])
+		}
+		userID := strconv.FormatInt(e.Vote.TgUserID, 10)
+		buttons = append(buttons, markup.Data(mark+members[i].DisplayName(), callbackAttendToggle, pollID, userID))
+	}
+
+	html, err := RenderAttendanceMessage(config.templates, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if withKeyboard {
+		rows := markup.Split(2, buttons)
+		rows = append(rows, markup.Row(markup.Data(MsgAttendanceDoneButton, callbackAttendDone, pollID)))
+		markup.Inline(rows...)
+	}
+	return html, markup, nil
+}
@@ -13,23 +13,31 @@ func (b *Bot) handleCancel(c tele.Context) error {
 	config := getClubConfig(c)
 
 	// Get active poll (validates event date hasn't passed)
-	p, err := b.GetActivePollForAction(c.Chat().ID)
-	if err != nil {
+	if _, err := b.GetActivePollForAction(c.Chat().ID); err != nil {
 		return err
 	}
 
 	// Cancel poll via service (marks as inactive)
-	p, err = b.pollService.CancelPoll(c.Chat().ID)
+	p, err := b.pollService.CancelPoll(c.Chat().ID)
 	if err != nil {
 		if errors.Is(err, poll.ErrNoActivePoll) {
 			return UserErrorf(MsgNoActivePoll)
 		}
+		if errors.Is(err, poll.ErrPollDatePassed) {
+			return UserErrorf(MsgPollDatePassed)
+		}
 		return WrapUserError(MsgFailedCancelPoll, err)
 	}
 
+	return b.announceCancellation(p, config)
+}
+
+// announceCancellation updates the chat after a poll was cancelled: unpins the poll,
+// adds the cancellation footer to the invitation and posts a notification with mentions.
+func (b *Bot) announceCancellation(p *poll.Poll, config *ClubConfig) error {
 	// Unpin the poll message if it was pinned
 	if p.TgMessageID != 0 {
-		if err := c.Bot().Unpin(MessageRef(p.TgChatID, 0).Chat, p.TgMessageID); err != nil {
+		if err := b.bot.Unpin(MessageRef(p.TgChatID, 0).Chat, p.TgMessageID); err != nil {
 			b.logger.Warn("failed to unpin poll message", "error", err)
 		}
 	}
@@ -49,7 +57,7 @@ func (b *Bot) handleCancel(c tele.Context) error {
 		cancelData.Members = MembersFromVotes(votes)
 	}
 
-	sentMsg, err := b.RenderAndSend(c, func() (string, error) {
+	sentMsg, err := b.renderAndSendTo(MessageRef(p.TgChatID, 0).Chat, func() (string, error) {
 		return RenderCancelMessage(config.templates, cancelData)
 	}, MsgFailedRenderCancellation, MsgFailedSendCancellation)
 	if err != nil {
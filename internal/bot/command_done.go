@@ -29,7 +29,7 @@ func (b *Bot) uncancelPoll(chatID int64) (*poll.Poll, error) {
 	}
 
 	// Delete cancellation message from chat
-	if p.TgCancelMessageID != 0 {
+	if p.IsCancelled() {
 		if err := b.bot.Delete(MessageRef(p.TgChatID, p.TgCancelMessageID)); err != nil {
 			b.logger.Warn("failed to delete cancellation message", "error", err)
 		}
@@ -132,14 +132,6 @@ func (b *Bot) handleDone(c tele.Context) error {
 		laterVoters = result.ComingLater
 	}
 
-	// Build nickname cache and convert to members
-	cache, err := b.buildNicknameCacheFromVotes(mainVoters, laterVoters)
-	if err != nil {
-		b.logger.Warn("failed to build nickname cache for done message", "error", err)
-	}
-	members := b.membersFromVotesWithCache(mainVoters, cache)
-	comingLater := b.membersFromVotesWithCache(laterVoters, cache)
-
 	// Delete old /done message if exists
 	if p.TgDoneMessageID != 0 {
 		if err := b.bot.Delete(MessageRef(p.TgChatID, p.TgDoneMessageID)); err != nil {
@@ -148,12 +140,7 @@ func (b *Bot) handleDone(c tele.Context) error {
 	}
 
 	// Render collected message
-	html, err := RenderCollectedMessage(config.templates, &CollectedData{
-		EventDate:   p.EventDate,
-		StartTime:   startTime,
-		Members:     members,
-		ComingLater: comingLater,
-	})
+	html, err := RenderCollectedMessage(config.templates, b.buildCollectedData(config, p, startTime, mainVoters, laterVoters))
 	if err != nil {
 		return WrapUserError(MsgFailedRenderCollected, err)
 	}
@@ -187,3 +174,48 @@ func (b *Bot) handleDone(c tele.Context) error {
 
 	return nil
 }
+
+// buildCollectedData prepares collected message data with game nicknames.
+// If the club has a table size configured and enough players came for several tables,
+// main voters are split into tables (see poll.SplitIntoTables).
+func (b *Bot) buildCollectedData(config *ClubConfig, p *poll.Poll, startTime string, mainVoters, comingLater []*poll.Vote) *CollectedData {
+	// Build a single cache for all voters (more efficient than separate caches)
+	cache, err := b.buildNicknameCacheFromVotes(config.Club, mainVoters, comingLater)
+	if err != nil {
+		b.logger.Warn("failed to build nickname cache for collected message", "error", err)
+	}
+
+	data := &CollectedData{
+		EventDate:   p.EventDate,
+		StartTime:   startTime,
+		JudgeName:   p.JudgeName,
+		Members:     b.membersFromVotesWithCache(config.Club, mainVoters, cache),
+		ComingLater: b.membersFromVotesWithCache(config.Club, comingLater, cache),
+	}
+
+	var ratings map[int64]float64
+	if config.FeatureFlags.BalanceTablesByRating {
+		all, err := b.pollService.GetRatings(config.Club, poll.StatsPeriod{})
+		if err != nil {
+			b.logger.Warn("failed to get ratings for table split", "error", err)
+		} else {
+			ratings = make(map[int64]float64, len(all))
+			for _, r := range all {
+				ratings[r.TgUserID] = r.Rating
+			}
+		}
+	}
+
+	tables, reserves := poll.SplitIntoTables(mainVoters, config.TableSize, ratings)
+	if len(tables) > 1 {
+		for i, t := range tables {
+			data.Tables = append(data.Tables, CollectedTable{
+				Number:  i + 1,
+				Members: b.membersFromVotesWithCache(config.Club, t, cache),
+			})
+		}
+		data.Reserves = b.membersFromVotesWithCache(config.Club, reserves, cache)
+	}
+
+	return data
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/export"
+)
+
+// handleExport sends the club's polls, current votes with nicknames and the nickname table
+// to the admin in private messages, so club data is not posted to the group.
+// Usage:
+//
+//	/export                          — everything, as CSV files
+//	/export 2025-01-01 2025-03-31    — events within the dates (inclusive)
+//	/export 2025-01-01 json          — from a date, as a single JSON file
+func (b *Bot) handleExport(c tele.Context) error {
+	config := getClubConfig(c)
+
+	format := export.FormatCSV
+	var dates []string
+	for _, arg := range c.Args() {
+		if f, err := export.ParseFormat(arg); err == nil {
+			format = f
+			continue
+		}
+		dates = append(dates, arg)
+	}
+	if len(dates) > 2 {
+		return UserErrorf(MsgExportUsage)
+	}
+	dates = append(dates, "", "")
+
+	period, err := export.ParsePeriod(dates[0], dates[1])
+	if err != nil {
+		return UserErrorf(MsgExportUsage)
+	}
+
+	data, err := b.pollService.Export(config.Club, period)
+	if err != nil {
+		return WrapUserError(MsgFailedExport, err)
+	}
+	files, err := export.Files(data, format)
+	if err != nil {
+		return WrapUserError(MsgFailedExport, err)
+	}
+
+	if err := b.sendExportFiles(c.Sender(), files, fmt.Sprintf(MsgFmtExportCaption, config.Name, len(data.Polls), len(data.Nicknames))); err != nil {
+		b.logger.Warn("failed to send export", "error", err, "user_id", c.Sender().ID)
+		return UserErrorf(MsgExportNotReachable)
+	}
+
+	b.logger.Info("data exported",
+		"actor_user_id", c.Sender().ID,
+		"club", config.Club,
+		"format", format,
+		"polls", len(data.Polls),
+		"nicknames", len(data.Nicknames),
+	)
+
+	_, err = b.SendTemporary(c.Chat(), MsgExportSent, 0)
+	return err
+}
+
+// sendExportFiles sends export files as documents, grouped into one album if there are several.
+// The caption goes under the last document, which Telegram shows under the whole group.
+func (b *Bot) sendExportFiles(to tele.Recipient, files []export.File, caption string) error {
+	album := make(tele.Album, len(files))
+	for i, f := range files {
+		album[i] = &tele.Document{
+			File:     tele.FromReader(bytes.NewReader(f.Data)),
+			FileName: f.Name,
+			MIME:     f.MIME,
+		}
+	}
+	album[len(album)-1].(*tele.Document).Caption = caption
+
+	// Media groups need at least two items
+	if len(album) == 1 {
+		_, err := b.bot.Send(to, album[0])
+		return err
+	}
+	_, err := b.bot.SendAlbum(to, album)
+	return err
+}
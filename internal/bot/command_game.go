@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// handleGame records the result of a game played on the latest event night.
+// Usage:
+//
+//	/game <город|мафия> <судья|-> <игрок1> ... <игрокN> — record a game
+//	/game rm <номер>                                     — delete a game of the night
+//
+// Players are listed in seat order; a role is given as a suffix: Кот/м, Лиса/д, @ivan/ш.
+func (b *Bot) handleGame(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.gameNightPoll(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	if args := c.Args(); len(args) > 0 && (args[0] == "rm" || args[0] == "удалить") {
+		return b.deleteGame(c, p, args[1:])
+	}
+
+	args, err := ParseGameArgs(strings.Join(c.Args(), " "))
+	if err != nil {
+		return UserErrorf(MsgGameUsage)
+	}
+
+	g := &poll.Game{
+		PollID:      p.ID,
+		Winner:      args.Winner,
+		ActorUserID: c.Sender().ID,
+	}
+
+	if args.Judge != "" {
+		g.JudgeUserID, _, g.JudgeName, err = b.pollService.ResolveVoteIdentifier(config.Club, args.Judge)
+		if err != nil {
+			return WrapUserError(MsgFailedSaveGame, err)
+		}
+	}
+
+	for i, player := range args.Players {
+		userID, username, displayName, err := b.pollService.ResolveVoteIdentifier(config.Club, player.Identifier)
+		if err != nil {
+			return WrapUserError(MsgFailedSaveGame, err)
+		}
+		g.Participants = append(g.Participants, &poll.GameParticipant{
+			Seat:       i + 1,
+			TgUserID:   userID,
+			TgUsername: username,
+			Name:       displayName,
+			Role:       player.Role,
+		})
+	}
+
+	if err := b.pollService.RecordGame(g); err != nil {
+		switch {
+		case errors.Is(err, poll.ErrInvalidPlayerCount):
+			return UserErrorf(MsgInvalidGamePlayerCount)
+		case errors.Is(err, poll.ErrDuplicatePlayer):
+			return UserErrorf(MsgDuplicateGamePlayer)
+		case errors.Is(err, poll.ErrInvalidRoles):
+			return UserErrorf(MsgInvalidGameRoles)
+		case errors.Is(err, poll.ErrJudgeIsPlayer):
+			return UserErrorf(MsgJudgeIsPlayer)
+		}
+		return WrapUserError(MsgFailedSaveGame, err)
+	}
+
+	b.logger.Info("game recorded",
+		"poll_id", p.ID,
+		"game_id", g.ID,
+		"number", g.Number,
+		"winner", g.Winner,
+		"judge_user_id", g.JudgeUserID,
+		"players", len(g.Participants),
+		"actor_user_id", g.ActorUserID,
+	)
+
+	html, err := RenderGameMessage(config.templates, &GameData{EventDate: p.EventDate, Game: g})
+	if err != nil {
+		return WrapUserError(MsgFailedRenderGame, err)
+	}
+
+	if _, err := b.SendWithRetry(c.Chat(), html, tele.ModeHTML, tele.Silent); err != nil {
+		return WrapUserError(MsgFailedSendGame, err)
+	}
+	return nil
+}
+
+// deleteGame handles /game rm <number> for the given event night.
+func (b *Bot) deleteGame(c tele.Context, p *poll.Poll, args []string) error {
+	if len(args) != 1 {
+		return UserErrorf(MsgGameUsage)
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 {
+		return UserErrorf(MsgGameUsage)
+	}
+
+	if err := b.pollService.DeleteGame(p.ID, number); err != nil {
+		if errors.Is(err, poll.ErrGameNotFound) {
+			return UserErrorf(MsgGameNotFound)
+		}
+		return WrapUserError(MsgFailedDeleteGame, err)
+	}
+
+	b.logger.Info("game deleted", "poll_id", p.ID, "number", number, "actor_user_id", c.Sender().ID)
+
+	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtGameDeleted, number), 0)
+	return err
+}
+
+// handleGames lists all games recorded for the latest event night.
+func (b *Bot) handleGames(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.gameNightPoll(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	games, err := b.pollService.GetGames(p.ID)
+	if err != nil {
+		return WrapUserError(MsgFailedGetGames, err)
+	}
+	if len(games) == 0 {
+		return UserErrorf(MsgNoGames)
+	}
+
+	data := &GamesData{}
+	for _, g := range games {
+		data.Games = append(data.Games, &GameData{EventDate: p.EventDate, Game: g})
+	}
+
+	html, err := RenderGamesMessage(config.templates, data)
+	if err != nil {
+		return WrapUserError(MsgFailedRenderGame, err)
+	}
+
+	_, err = b.SendTemporary(c.Chat(), html, 30*time.Second, tele.ModeHTML)
+	return err
+}
+
+// gameNightPoll returns the latest poll of the chat if its event can have games:
+// the event must not be cancelled and must have already started (today or earlier).
+func (b *Bot) gameNightPoll(chatID int64) (*poll.Poll, error) {
+	p, err := b.pollService.GetLatestPoll(chatID)
+	if err != nil {
+		if errors.Is(err, poll.ErrNoActivePoll) {
+			return nil, UserErrorf(MsgNoPoll)
+		}
+		return nil, WrapUserError(MsgFailedGetPoll, err)
+	}
+
+	if p.IsCancelled() {
+		return nil, UserErrorf(MsgEventCancelled)
+	}
+	if isPollDateInFuture(p.EventDate) {
+		return nil, UserErrorf(MsgEventNotHeldYet)
+	}
+	return p, nil
+}
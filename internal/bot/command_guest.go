@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// handleGuest registers guests (friends without Telegram) the sender brings to the active event.
+// Usage:
+//
+//	/guest     — bring one guest
+//	/guest N   — bring N guests
+//	/guest 0   — no guests
+//
+// Guests are shown as "Ник +N" and count towards the number of players.
+// Admins register guests for other players with /vote <name> <option> +N.
+func (b *Bot) handleGuest(c tele.Context) error {
+	config := getClubConfig(c)
+
+	count := 1
+	if args := c.Args(); len(args) > 0 {
+		if len(args) > 1 {
+			return UserErrorf(MsgGuestUsage)
+		}
+		n, err := parseGuestCount(args[0])
+		if err != nil {
+			return UserErrorf(MsgGuestUsage)
+		}
+		count = n
+	}
+
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	sender := c.Sender()
+	name := Member{TgName: sender.FirstName, TgUsername: sender.Username}.DisplayName()
+	if err := b.setGuests(config, p, sender.ID, count, sender.ID); err != nil {
+		return err
+	}
+
+	_, err = b.SendTemporary(c.Chat(), guestsMessage(name, count), 0)
+	return err
+}
+
+// setGuests stores a host's guests and refreshes the invitation and collected messages.
+func (b *Bot) setGuests(config *ClubConfig, p *poll.Poll, hostUserID int64, count int, actorUserID int64) error {
+	if err := b.pollService.SetGuests(p.ID, hostUserID, count, actorUserID); err != nil {
+		switch {
+		case errors.Is(err, poll.ErrInvalidGuestCount):
+			return UserErrorf(MsgInvalidGuestCount)
+		case errors.Is(err, poll.ErrHostNotAttending):
+			return UserErrorf(MsgGuestHostNotAttending)
+		}
+		return WrapUserError(MsgFailedSaveGuests, err)
+	}
+
+	b.logger.Info("guests set",
+		"poll_id", p.ID,
+		"host_user_id", hostUserID,
+		"guests", count,
+		"actor_user_id", actorUserID,
+	)
+
+	b.UpdateInvitationMessage(p, nil)
+	b.UpdateDoneMessage(p, config)
+	return nil
+}
+
+// parseGuestCount parses a guest count argument: "2", "+2", or "-" for no guests.
+func parseGuestCount(arg string) (int, error) {
+	if arg == "-" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(arg, "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid guest count: %s", arg)
+	}
+	return n, nil
+}
+
+// guestsMessage returns the confirmation for a guest change.
+func guestsMessage(name string, count int) string {
+	if count == 0 {
+		return fmt.Sprintf(MsgFmtGuestsRemoved, name)
+	}
+	return fmt.Sprintf(MsgFmtGuestsSet, name, count)
+}
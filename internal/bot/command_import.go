@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/nickimport"
+)
+
+// maxImportFileSize limits nickname import files; a club's nicknames fit in a few kilobytes.
+const maxImportFileSize = 1 << 20
+
+// handleImport creates nicknames from a CSV document. Telegram does not treat captions as commands,
+// so the admin sends the file first and replies to it with /import.
+// Each row holds /nick arguments in columns: @username or user ID, nickname, optional gender
+// and optional shared marker, e.g.
+//
+//	@user1,Секртис,м
+//	123456789;"Мадам Жу";ж;общий
+func (b *Bot) handleImport(c tele.Context) error {
+	config := getClubConfig(c)
+
+	var doc *tele.Document
+	if reply := c.Message().ReplyTo; reply != nil {
+		doc = reply.Document
+	}
+	if doc == nil {
+		return UserErrorf(MsgImportUsage)
+	}
+	if doc.FileSize > maxImportFileSize {
+		return UserErrorf(MsgImportTooLarge)
+	}
+
+	file, err := b.bot.File(&doc.File)
+	if err != nil {
+		return WrapUserError(MsgFailedImport, err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		return WrapUserError(MsgFailedImport, err)
+	}
+	if len(data) > maxImportFileSize {
+		return UserErrorf(MsgImportTooLarge)
+	}
+
+	report, err := nickimport.Import(b.pollService, config.Club, data, c.Sender().ID, ValidateImportRow)
+	if err != nil {
+		return WrapUserError(MsgFailedImport, err)
+	}
+
+	for _, row := range report.Created {
+		b.ensureNickConsistency(c.Chat().ID, &NickArgs{
+			TgUserID:   row.TgUserID,
+			TgUsername: row.TgUsername,
+			Nickname:   row.Nickname,
+			Gender:     GenderFromString(row.Gender),
+			Shared:     row.Shared,
+		})
+	}
+	if len(report.Created) > 0 {
+		b.refreshPollMessages(c.Chat().ID, config)
+	}
+
+	b.logger.Info("nicknames imported",
+		"actor_user_id", c.Sender().ID,
+		"club", config.Club,
+		"file_name", doc.FileName,
+		"created", len(report.Created),
+		"duplicates", len(report.Duplicates),
+		"invalid", len(report.Invalid),
+	)
+
+	// Same lifetime as /nick list, to read the rows to fix
+	_, err = b.SendTemporary(c.Chat(), formatImportReport(report), 30*time.Second)
+	return err
+}
+
+// ValidateImportRow validates the columns of a nickname import row with the /nick argument rules,
+// for nickimport.Parse. A column may hold spaces, so an identifier with spaces is rejected here
+// instead of being split like in /nick.
+func ValidateImportRow(tokens []string) (nickimport.Row, error) {
+	if len(tokens) > 0 && strings.ContainsFunc(tokens[0], unicode.IsSpace) {
+		return nickimport.Row{}, errors.New("invalid identifier: must be @username or numeric ID")
+	}
+	args, err := parseNickTokens(tokens)
+	if err != nil {
+		return nickimport.Row{}, err
+	}
+	return nickimport.Row{
+		TgUserID:   args.TgUserID,
+		TgUsername: args.TgUsername,
+		Nickname:   args.Nickname,
+		Gender:     args.Gender.String(),
+		Shared:     args.Shared,
+	}, nil
+}
+
+// formatImportReport summarizes an import: counts, then taken nicknames and lines with errors.
+func formatImportReport(report *nickimport.Report) string {
+	lines := []string{fmt.Sprintf(MsgFmtNicksImported, len(report.Created), len(report.Duplicates), len(report.Invalid))}
+
+	if len(report.Duplicates) > 0 {
+		nicks := make([]string, len(report.Duplicates))
+		for i, row := range report.Duplicates {
+			nicks[i] = row.Nickname
+		}
+		lines = append(lines, fmt.Sprintf(MsgFmtImportDuplicates, strings.Join(nicks, ", ")))
+	}
+	if len(report.Invalid) > 0 {
+		numbers := make([]string, len(report.Invalid))
+		for i, row := range report.Invalid {
+			numbers[i] = strconv.Itoa(row.Line)
+		}
+		lines = append(lines, fmt.Sprintf(MsgFmtImportInvalid, strings.Join(numbers, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
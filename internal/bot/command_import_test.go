@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"nuclight.org/consigliere/internal/nickimport"
+)
+
+func TestValidateImportRow(t *testing.T) {
+	input := "username,nick,gender\n" +
+		"@user1,Секртис,м\n" +
+		"@user2, \"Мадам Жу\",ж,общий\n" +
+		"123456789,Кринж\n" +
+		"@user3,,м\n" +
+		"@not a user,Ник\n" +
+		"@user4,Кот,x\n" +
+		"@user5,Пёс,,shared\n" +
+		"user6,Лис\n"
+
+	rows, invalid, err := nickimport.Parse([]byte(input), ValidateImportRow)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []struct {
+		line     int
+		identity string
+		nick     string
+		gender   string
+		shared   bool
+	}{
+		{2, "@user1", "Секртис", "male", false},
+		{3, "@user2", "Мадам Жу", "female", true},
+		{4, "123456789", "Кринж", "", false},
+		{8, "@user5", "Пёс", "", true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Line != w.line || r.Identity() != w.identity || r.Nickname != w.nick || r.Gender != w.gender || r.Shared != w.shared {
+			t.Errorf("row %d = {%d %s %s %q %v}, want %+v", i, r.Line, r.Identity(), r.Nickname, r.Gender, r.Shared, w)
+		}
+	}
+
+	// Like /nick: a username needs its @
+	var invalidLines []int
+	for _, e := range invalid {
+		invalidLines = append(invalidLines, e.Line)
+	}
+	if want := []int{5, 6, 7, 9}; !slices.Equal(invalidLines, want) {
+		t.Errorf("invalid lines = %v, want %v", invalidLines, want)
+	}
+}
+
+func TestFormatImportReport(t *testing.T) {
+	username := "user1"
+	report := &nickimport.Report{
+		Created: []nickimport.Row{{Line: 1, TgUsername: &username, Nickname: "Секртис"}},
+		Duplicates: []nickimport.Row{
+			{Line: 2, TgUsername: &username, Nickname: "Кот"},
+			{Line: 3, TgUsername: &username, Nickname: "Секртис"},
+		},
+		Invalid: []nickimport.RowError{{Line: 4, Err: errors.New("not enough columns")}},
+	}
+
+	want := "Импорт ников: создано 1, уже заняты 2, с ошибками 1\nУже заняты: Кот, Секртис\nОшибки в строках: 4"
+	if got := formatImportReport(report); got != want {
+		t.Errorf("formatImportReport() = %q, want %q", got, want)
+	}
+}
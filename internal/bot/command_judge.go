@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// handleJudge assigns the host (judge) of the active event.
+// Usage:
+//
+//	/judge              — suggest the next judge from the club's judge pool
+//	/judge auto         — assign the suggested judge
+//	/judge <@user|nick> — assign a judge
+//	/judge -            — clear the judge
+//
+// The judge is shown in the invitation and collected messages, which are refreshed.
+func (b *Bot) handleJudge(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	arg := strings.TrimSpace(strings.Join(c.Args(), " "))
+
+	var userID int64
+	var name string
+	switch strings.ToLower(arg) {
+	case "":
+		return b.suggestJudge(c, config)
+	case NoJudgeMarker:
+		// Clear the judge
+	case "auto", "авто":
+		suggestion, found, err := b.pollService.SuggestJudge(config.Club, config.judgePool())
+		if err != nil {
+			return WrapUserError(MsgFailedSaveJudge, err)
+		}
+		if !found {
+			return UserErrorf(MsgEmptyJudgePool)
+		}
+		userID = suggestion.UserID
+		name = b.userDisplayName(config.Club, c.Chat(), userID)
+	default:
+		tokens, err := tokenize(arg)
+		if err != nil || len(tokens) != 1 {
+			return UserErrorf(MsgJudgeUsage)
+		}
+		userID, _, name, err = b.pollService.ResolveVoteIdentifier(config.Club, tokens[0])
+		if err != nil {
+			return WrapUserError(MsgFailedSaveJudge, err)
+		}
+	}
+
+	if err := b.pollService.SetJudge(p, userID, name); err != nil {
+		return WrapUserError(MsgFailedSaveJudge, err)
+	}
+
+	b.logger.Info("judge assigned", "poll_id", p.ID, "judge_user_id", userID, "judge_name", name)
+
+	b.UpdateInvitationMessage(p, nil)
+	b.UpdateDoneMessage(p, config)
+
+	msg := MsgJudgeCleared
+	if userID != 0 {
+		msg = fmt.Sprintf(MsgFmtJudgeAssigned, name)
+	}
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// suggestJudge replies with the judge pool member who hosted least recently.
+func (b *Bot) suggestJudge(c tele.Context, config *ClubConfig) error {
+	suggestion, found, err := b.pollService.SuggestJudge(config.Club, config.judgePool())
+	if err != nil {
+		return WrapUserError(MsgFailedSuggestJudge, err)
+	}
+	if !found {
+		return UserErrorf(MsgEmptyJudgePool)
+	}
+
+	lastHosted := MsgNeverHosted
+	if !suggestion.LastHosted.IsZero() {
+		lastHosted = formatDateRussianShort(suggestion.LastHosted)
+	}
+
+	msg := fmt.Sprintf(MsgFmtJudgeSuggestion, b.userDisplayName(config.Club, c.Chat(), suggestion.UserID), lastHosted)
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// userDisplayName returns the best display name for a Telegram user:
+// game nickname if known, otherwise the chat member's first name, otherwise the ID.
+func (b *Bot) userDisplayName(club poll.Club, chat *tele.Chat, userID int64) string {
+	cache, err := b.pollService.NewNicknameCache(club, []poll.NicknameLookupKey{{UserID: userID}})
+	if err != nil {
+		b.logger.Warn("failed to fetch nickname", "error", err, "user_id", userID)
+	} else if nick := cache.GetDisplayNick(userID, ""); nick != "" {
+		return nick
+	}
+
+	member, err := b.bot.ChatMemberOf(chat, &tele.User{ID: userID})
+	if err != nil || member.User == nil {
+		return fmt.Sprintf("ID %d", userID)
+	}
+	return Member{TgName: member.User.FirstName, TgUsername: member.User.Username}.DisplayName()
+}
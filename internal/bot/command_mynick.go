@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Callback button identifiers for reviewing nickname requests
+const (
+	callbackNickApprove = "nick_ok"
+	callbackNickReject  = "nick_no"
+)
+
+// handleMyNick lets a player ask for a game nickname for themselves.
+// Usage:
+//
+//	/mynick gamenick               — request a nickname
+//	/mynick "nick with spaces" ж   — quoted nickname with gender
+//
+// Works in the club chat and in private messages; from private messages the request
+// goes to the club the player last voted in. Club admins approve or reject the request
+// with the buttons of the posted request message.
+func (b *Bot) handleMyNick(c tele.Context) error {
+	input := strings.Join(c.Args(), " ")
+	if input == "" {
+		return UserErrorf(MsgMyNickUsage)
+	}
+
+	// Reuse the /nick parser with the sender as the identifier
+	sender := c.Sender()
+	args, err := ParseNickArgs(fmt.Sprintf("%d %s", sender.ID, input))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid gender") {
+			return UserErrorf(MsgInvalidGender)
+		}
+		return UserErrorf(MsgMyNickUsage)
+	}
+	// Only admins put nicknames into the shared namespace
+	if args.Shared {
+		return UserErrorf(MsgMyNickUsage)
+	}
+
+	chatID := c.Chat().ID
+	if c.Chat().Type == tele.ChatPrivate {
+		latestChatID, found, err := b.pollService.LookupLatestChatID(sender.ID)
+		if err != nil {
+			return WrapUserError(MsgFailedSaveNickRequest, err)
+		}
+		if !found {
+			return UserErrorf(MsgMyNickNoClub)
+		}
+		chatID = latestChatID
+	}
+	config, ok := chatRegistry[chatID]
+	if !ok {
+		return UserErrorf(MsgChatNotPermitted)
+	}
+
+	req := &poll.NicknameRequest{
+		TgChatID:    chatID,
+		TgUserID:    sender.ID,
+		TgUsername:  sender.Username,
+		TgFirstName: sender.FirstName,
+		GameNick:    args.Nickname,
+		Gender:      args.Gender.String(),
+	}
+	if err := b.pollService.RequestNickname(config.Club, req); err != nil {
+		switch {
+		case errors.Is(err, poll.ErrNickTaken):
+			return UserErrorf(MsgNickTaken)
+		case errors.Is(err, poll.ErrHasNickname):
+			return UserErrorf(MsgMyNickExists)
+		case errors.Is(err, poll.ErrNickRequestPending):
+			return UserErrorf(MsgNickRequestPending)
+		}
+		return WrapUserError(MsgFailedSaveNickRequest, err)
+	}
+
+	b.logger.Info("nickname requested",
+		"request_id", req.ID,
+		"chat_id", chatID,
+		"tg_user_id", sender.ID,
+		"game_nick", req.GameNick,
+		"gender", req.Gender,
+	)
+
+	if _, err := b.SendWithRetry(&tele.Chat{ID: chatID}, nickRequestText(MsgFmtNickRequest, req), nickRequestKeyboard(req)); err != nil {
+		return WrapUserError(MsgFailedSendNickRequest, err)
+	}
+
+	// In the club chat the request message itself is the confirmation
+	if c.Chat().Type == tele.ChatPrivate {
+		return c.Send(MsgNickRequestSent)
+	}
+	return nil
+}
+
+// nickRequestKeyboard builds the approve/reject buttons of a request message.
+// Callback data: <request ID>
+func nickRequestKeyboard(req *poll.NicknameRequest) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	id := strconv.FormatInt(req.ID, 10)
+	markup.Inline(markup.Row(
+		markup.Data(MsgNickApproveButton, callbackNickApprove, id),
+		markup.Data(MsgNickRejectButton, callbackNickReject, id),
+	))
+	return markup
+}
+
+// nickRequestText formats a request message line: format takes the player and the nickname.
+func nickRequestText(format string, req *poll.NicknameRequest) string {
+	player := Member{TgName: req.TgFirstName, TgUsername: req.TgUsername}.MentionName()
+	nick := poll.NicknameInfo{Nick: req.GameNick, Gender: req.Gender}.DisplayNick()
+	return fmt.Sprintf(format, player, nick)
+}
+
+// handleNickApprove creates the requested nickname and replaces the buttons with the outcome.
+// Callback data: <request ID>
+func (b *Bot) handleNickApprove(c tele.Context) error {
+	config := getClubConfig(c)
+
+	id, ok := nickRequestIDFromCallback(c)
+	if !ok {
+		return c.Respond()
+	}
+
+	req, created, err := b.pollService.ApproveNicknameRequest(id, c.Chat().ID, config.Club, c.Sender().ID)
+	hasNickname := errors.Is(err, poll.ErrHasNickname)
+	if err != nil && !hasNickname {
+		return b.respondNickRequestError(c, err)
+	}
+
+	b.logger.Info("nickname request approved",
+		"actor_user_id", c.Sender().ID,
+		"request_id", req.ID,
+		"tg_user_id", req.TgUserID,
+		"game_nick", req.GameNick,
+		"created", created,
+	)
+
+	format := MsgFmtNickTaken
+	if hasNickname {
+		format = MsgFmtNickHasNickname
+	}
+	if created {
+		format = MsgFmtNickApproved
+		if err := b.pollService.EnsureUserDataConsistency(req.TgChatID, req.TgUserID, req.TgUsername); err != nil {
+			b.logger.Warn("failed to ensure user data consistency", "error", err)
+		}
+		b.refreshPollMessages(req.TgChatID, config)
+	}
+
+	if _, err := b.bot.Edit(c.Message(), nickRequestText(format, req)); err != nil && !isNotModifiedErr(err) {
+		b.logger.Warn("failed to update nickname request message", "error", err, "request_id", req.ID)
+	}
+	return c.Respond()
+}
+
+// handleNickReject declines the request and replaces the buttons with the outcome.
+// Callback data: <request ID>
+func (b *Bot) handleNickReject(c tele.Context) error {
+	id, ok := nickRequestIDFromCallback(c)
+	if !ok {
+		return c.Respond()
+	}
+
+	req, err := b.pollService.RejectNicknameRequest(id, c.Chat().ID, c.Sender().ID)
+	if err != nil {
+		return b.respondNickRequestError(c, err)
+	}
+
+	b.logger.Info("nickname request rejected",
+		"actor_user_id", c.Sender().ID,
+		"request_id", req.ID,
+		"tg_user_id", req.TgUserID,
+		"game_nick", req.GameNick,
+	)
+
+	if _, err := b.bot.Edit(c.Message(), nickRequestText(MsgFmtNickRejected, req)); err != nil && !isNotModifiedErr(err) {
+		b.logger.Warn("failed to update nickname request message", "error", err, "request_id", req.ID)
+	}
+	return c.Respond()
+}
+
+// nickRequestIDFromCallback parses the request ID from callback data.
+func nickRequestIDFromCallback(c tele.Context) (int64, bool) {
+	args := c.Args()
+	if len(args) != 1 {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	return id, err == nil
+}
+
+// respondNickRequestError tells the admin that the request was already decided,
+// or passes other errors to HandleErrors.
+func (b *Bot) respondNickRequestError(c tele.Context, err error) error {
+	if errors.Is(err, poll.ErrNickRequestNotFound) || errors.Is(err, poll.ErrNickRequestNotPending) {
+		return c.Respond(&tele.CallbackResponse{Text: MsgNickRequestDecided, ShowAlert: true})
+	}
+	return WrapUserError(MsgFailedSaveNick, err)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"html/template"
 	"strings"
+	"time"
 
 	tele "gopkg.in/telebot.v4"
 
@@ -17,10 +18,29 @@ import (
 //	/nick @username gamenick — link by telegram username
 //	/nick @username "nick with spaces" — quoted nickname
 //	/nick @username gamenick м — with gender (м/ж/m/f/д)
+//	/nick @username gamenick м общий — in the shared namespace of all clubs
 //	/nick 123456 gamenick   — link by telegram user ID
+//	/nick list              — list the club's and shared nicknames
+//	/nick rm gamenick       — remove a nickname
+//	/nick rename old new    — rename a nickname
+//	/nick gender gamenick м — set gender (м/ж, - to clear)
 func (b *Bot) handleNick(c tele.Context) error {
 	config := getClubConfig(c)
 
+	// Subcommand keywords never clash with links: those start with @username or a numeric ID
+	if args := c.Args(); len(args) > 0 {
+		switch args[0] {
+		case "list", "список":
+			return b.handleNickList(c, config)
+		case "rm", "удалить":
+			return b.handleNickRemove(c, config, args[1:])
+		case "rename", "переименовать":
+			return b.handleNickRename(c, config, args[1:])
+		case "gender", "пол":
+			return b.handleNickGender(c, config, args[1:])
+		}
+	}
+
 	// Join all args back together to parse with our custom parser
 	// that handles quotes properly
 	input := strings.Join(c.Args(), " ")
@@ -37,19 +57,51 @@ func (b *Bot) handleNick(c tele.Context) error {
 		return UserErrorf(MsgNickUsage)
 	}
 
+	actorUserID := c.Sender().ID
+
 	b.logger.Info("nick parameters",
+		"actor_user_id", actorUserID,
 		"tg_user_id", args.TgUserID,
 		"tg_username", args.TgUsername,
 		"game_nick", args.Nickname,
 		"gender", args.Gender.String(),
+		"shared", args.Shared,
 	)
 
-	// Create the nickname mapping
-	created, err := b.pollService.CreateNickname(args.TgUserID, args.TgUsername, args.Nickname, args.Gender.String())
+	// Create the nickname mapping in the club's namespace unless asked to share it
+	club := config.Club
+	if args.Shared {
+		club = poll.SharedNicknames
+	}
+	created, err := b.pollService.CreateNickname(club, args.TgUserID, args.TgUsername, args.Nickname, args.Gender.String(), actorUserID)
 	if err != nil {
 		return WrapUserError(MsgFailedSaveNick, err)
 	}
 
+	b.ensureNickConsistency(c.Chat().ID, args)
+
+	// Update invitation and done messages if an active poll exists
+	b.refreshPollMessages(c.Chat().ID, config)
+
+	// Send confirmation
+	var msg string
+	if created {
+		if args.TgUsername != nil {
+			msg = fmt.Sprintf(MsgFmtNickCreated, "@"+*args.TgUsername, args.Nickname)
+		} else {
+			msg = fmt.Sprintf(MsgFmtNickCreatedByID, *args.TgUserID, args.Nickname)
+		}
+	} else {
+		msg = MsgNickDuplicate
+	}
+
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// ensureNickConsistency links a new nickname to the player's votes in the chat:
+// updates nickname records with the known user ID and username and consolidates synthetic votes.
+func (b *Bot) ensureNickConsistency(chatID int64, args *NickArgs) {
 	// Resolve user ID for data consistency
 	var resolvedUserID int64
 	var username string
@@ -67,48 +119,136 @@ func (b *Bot) handleNick(c tele.Context) error {
 
 	// Ensure data consistency: update nicknames and consolidate synthetic votes
 	if resolvedUserID > 0 {
-		if err := b.pollService.EnsureUserDataConsistency(c.Chat().ID, resolvedUserID, username); err != nil {
+		if err := b.pollService.EnsureUserDataConsistency(chatID, resolvedUserID, username); err != nil {
 			b.logger.Warn("failed to ensure user data consistency", "error", err)
 		}
 	}
+}
 
-	// Update invitation and done messages if an active poll exists
-	if p, err := b.pollService.GetActivePoll(c.Chat().ID); err == nil {
-		b.UpdateInvitationMessage(p, nil)
-		b.UpdateDoneMessage(p, config)
-	} else if !errors.Is(err, poll.ErrNoActivePoll) {
-		b.logger.Warn("failed to get poll for message refresh", "error", err)
+// handleNickList shows all nickname mappings as a temporary message.
+func (b *Bot) handleNickList(c tele.Context, config *ClubConfig) error {
+	nicknames, err := b.pollService.ListNicknames(config.Club)
+	if err != nil {
+		return WrapUserError(MsgFailedGetNicks, err)
+	}
+	if len(nicknames) == 0 {
+		return UserErrorf(MsgNoNicknames)
 	}
 
-	// Send confirmation
-	var msg string
-	if created {
-		if args.TgUsername != nil {
-			msg = fmt.Sprintf(MsgFmtNickCreated, "@"+*args.TgUsername, args.Nickname)
-		} else {
-			msg = fmt.Sprintf(MsgFmtNickCreatedByID, *args.TgUserID, args.Nickname)
+	html, err := RenderNicknamesMessage(config.templates, &NicknamesData{Nicknames: nicknames})
+	if err != nil {
+		return WrapUserError(MsgFailedRenderNicks, err)
+	}
+
+	// Same lifetime as /results to allow copying IDs
+	_, err = b.SendTemporary(c.Chat(), html, 30*time.Second, tele.ModeHTML)
+	return err
+}
+
+// handleNickRemove deletes a nickname mapping: /nick rm <nick>
+func (b *Bot) handleNickRemove(c tele.Context, config *ClubConfig, args []string) error {
+	tokens, err := tokenize(strings.Join(args, " "))
+	if err != nil || len(tokens) != 1 {
+		return UserErrorf(MsgNickUsage)
+	}
+	nick := tokens[0]
+
+	if _, err := b.pollService.DeleteNickname(config.Club, nick, isAllClubsAdmin(c.Sender().ID)); err != nil {
+		return nickManageError(err)
+	}
+
+	b.logger.Info("nickname deleted", "actor_user_id", c.Sender().ID, "game_nick", nick)
+
+	b.refreshPollMessages(c.Chat().ID, config)
+	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtNickDeleted, nick), 0)
+	return err
+}
+
+// handleNickRename renames a nickname keeping its Telegram link: /nick rename <old> <new>
+func (b *Bot) handleNickRename(c tele.Context, config *ClubConfig, args []string) error {
+	tokens, err := tokenize(strings.Join(args, " "))
+	if err != nil || len(tokens) != 2 || tokens[1] == "" {
+		return UserErrorf(MsgNickUsage)
+	}
+	oldNick, newNick := tokens[0], tokens[1]
+
+	if _, err := b.pollService.RenameNickname(config.Club, oldNick, newNick, isAllClubsAdmin(c.Sender().ID)); err != nil {
+		return nickManageError(err)
+	}
+
+	b.logger.Info("nickname renamed", "actor_user_id", c.Sender().ID, "old_nick", oldNick, "new_nick", newNick)
+
+	b.refreshPollMessages(c.Chat().ID, config)
+	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtNickRenamed, oldNick, newNick), 0)
+	return err
+}
+
+// handleNickGender sets or clears the gender of a nickname: /nick gender <nick> <м|ж|->
+func (b *Bot) handleNickGender(c tele.Context, config *ClubConfig, args []string) error {
+	tokens, err := tokenize(strings.Join(args, " "))
+	if err != nil || len(tokens) != 2 {
+		return UserErrorf(MsgNickUsage)
+	}
+	nick := tokens[0]
+
+	gender := GenderNotSet
+	if tokens[1] != "-" {
+		if gender, err = parseGender(tokens[1]); err != nil {
+			return UserErrorf(MsgInvalidGender)
 		}
-	} else {
-		msg = MsgNickDuplicate
 	}
 
-	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	if _, err := b.pollService.SetNicknameGender(config.Club, nick, gender.String(), isAllClubsAdmin(c.Sender().ID)); err != nil {
+		return nickManageError(err)
+	}
+
+	b.logger.Info("nickname gender set", "actor_user_id", c.Sender().ID, "game_nick", nick, "gender", gender.String())
+
+	b.refreshPollMessages(c.Chat().ID, config)
+	display := poll.NicknameInfo{Nick: nick, Gender: gender.String()}.DisplayNick()
+	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtNickGenderSet, display), 0)
 	return err
 }
 
-// RenderInvitationMessageWithNicks renders invitation with nicknames resolved.
-func (b *Bot) RenderInvitationMessageWithNicks(tmpl *template.Template, data *poll.InvitationData) (string, error) {
+// nickManageError maps nickname management errors to user-facing messages.
+func nickManageError(err error) error {
+	switch {
+	case errors.Is(err, poll.ErrNickNotFound):
+		return UserErrorf(MsgNickNotFound)
+	case errors.Is(err, poll.ErrNickTaken):
+		return UserErrorf(MsgNickTaken)
+	case errors.Is(err, poll.ErrNickShared):
+		return UserErrorf(MsgNickShared)
+	case errors.Is(err, poll.ErrNickHasHistory):
+		return UserErrorf(MsgNickHasHistory)
+	}
+	return WrapUserError(MsgFailedSaveNick, err)
+}
+
+// refreshPollMessages re-renders the invitation and done messages of the chat's active poll, if any,
+// so that nickname changes show up immediately.
+func (b *Bot) refreshPollMessages(chatID int64, config *ClubConfig) {
+	if p, err := b.pollService.GetActivePoll(chatID); err == nil {
+		b.UpdateInvitationMessage(p, nil)
+		b.UpdateDoneMessage(p, config)
+	} else if !errors.Is(err, poll.ErrNoActivePoll) {
+		b.logger.Warn("failed to get poll for message refresh", "error", err)
+	}
+}
+
+// RenderInvitationMessageWithNicks renders invitation with the club's nicknames resolved.
+func (b *Bot) RenderInvitationMessageWithNicks(club poll.Club, tmpl *template.Template, data *poll.InvitationData) (string, error) {
 	// Build a single cache for all vote lists (more efficient than 3 separate caches)
-	cache, err := b.buildNicknameCacheFromVotes(data.Participants, data.ComingLater, data.Undecided)
+	cache, err := b.buildNicknameCacheFromVotes(club, data.Participants, data.ComingLater, data.Undecided)
 	if err != nil {
 		b.logger.Warn("failed to build nickname cache for invitation", "error", err)
 		// Continue without nicknames - enrichVotesWithCache handles nil cache
 	}
 
 	// Enrich votes with nicknames using shared cache
-	b.enrichVotesWithCache(data.Participants, cache)
-	b.enrichVotesWithCache(data.ComingLater, cache)
-	b.enrichVotesWithCache(data.Undecided, cache)
+	b.enrichVotesWithCache(club, data.Participants, cache)
+	b.enrichVotesWithCache(club, data.ComingLater, cache)
+	b.enrichVotesWithCache(club, data.Undecided, cache)
 
 	return RenderInvitationMessage(tmpl, data)
 }
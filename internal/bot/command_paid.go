@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// handlePaid records payments for the latest event.
+// Usage:
+//
+//	/paid <@user|nick> ...    — mark players as paid (the event price)
+//	/paid rm <@user|nick> ... — remove players' payments
+//
+// Payments can be recorded before or after the event, for players who voted in its poll;
+// unpaid players are listed in /results.
+func (b *Bot) handlePaid(c tele.Context) error {
+	args := c.Args()
+	remove := len(args) > 0 && (args[0] == "rm" || args[0] == "удалить")
+	if remove {
+		args = args[1:]
+	}
+
+	identifiers, err := tokenize(strings.Join(args, " "))
+	if err != nil || len(identifiers) == 0 {
+		return UserErrorf(MsgPaidUsage)
+	}
+
+	p, err := b.paymentPoll(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+	if !remove && p.Price <= 0 {
+		return UserErrorf(MsgNoPrice)
+	}
+
+	var done, missing, notVoted []string
+	for _, identifier := range identifiers {
+		userID, username, displayName, err := b.pollService.ResolveVoteIdentifier(p.Club, identifier)
+		if err != nil {
+			return WrapUserError(MsgFailedSavePayment, err)
+		}
+		// Payments are keyed like the votes, so that /results matches them
+		vote, err := b.pollService.FindVote(p.ID, userID, username)
+		if err != nil {
+			return WrapUserError(MsgFailedSavePayment, err)
+		}
+		if vote != nil {
+			userID = vote.TgUserID
+		} else if !remove {
+			notVoted = append(notVoted, displayName)
+			continue
+		}
+
+		if remove {
+			err = b.pollService.RemovePayment(p.ID, userID)
+			if errors.Is(err, poll.ErrPaymentNotFound) {
+				missing = append(missing, displayName)
+				continue
+			}
+		} else {
+			err = b.pollService.RecordPayment(p, userID, c.Sender().ID)
+		}
+		if err != nil {
+			return WrapUserError(MsgFailedSavePayment, err)
+		}
+		done = append(done, displayName)
+	}
+
+	b.logger.Info("payments updated",
+		"poll_id", p.ID,
+		"remove", remove,
+		"players", done,
+		"actor_user_id", c.Sender().ID,
+	)
+
+	var lines []string
+	if len(done) > 0 {
+		if remove {
+			lines = append(lines, fmt.Sprintf(MsgFmtPaymentsRemoved, strings.Join(done, ", ")))
+		} else {
+			lines = append(lines, fmt.Sprintf(MsgFmtPaymentsRecorded, p.Price, strings.Join(done, ", ")))
+		}
+	}
+	if len(missing) > 0 {
+		lines = append(lines, fmt.Sprintf(MsgFmtNoPayment, strings.Join(missing, ", ")))
+	}
+	if len(notVoted) > 0 {
+		lines = append(lines, fmt.Sprintf(MsgFmtNotVoted, strings.Join(notVoted, ", ")))
+	}
+	_, err = b.SendTemporary(c.Chat(), strings.Join(lines, "\n"), 0)
+	return err
+}
+
+// paymentPoll returns the latest non-cancelled poll of the chat, which payments and the price apply to.
+func (b *Bot) paymentPoll(chatID int64) (*poll.Poll, error) {
+	p, err := b.pollService.GetLatestPoll(chatID)
+	if err != nil {
+		if errors.Is(err, poll.ErrNoActivePoll) {
+			return nil, UserErrorf(MsgNoPoll)
+		}
+		return nil, WrapUserError(MsgFailedGetPoll, err)
+	}
+
+	if p.IsCancelled() {
+		return nil, UserErrorf(MsgEventCancelled)
+	}
+	return p, nil
+}
@@ -24,7 +24,7 @@ func (b *Bot) handlePoll(c tele.Context) error {
 	b.logger.Info("poll parameters", "event_date", eventDate.Format("2006-01-02"), "club", config.Club)
 
 	// Create poll in database (service checks for existing poll)
-	result, err := b.pollService.CreatePoll(c.Chat().ID, eventDate, config.Club)
+	result, err := b.pollService.CreatePoll(c.Chat().ID, eventDate, config.Club, config.DefaultPrice)
 	if err != nil {
 		if errors.Is(err, poll.ErrPollExists) {
 			return UserErrorf(MsgPollAlreadyExists)
@@ -65,9 +65,14 @@ func (b *Bot) handlePoll(c tele.Context) error {
 		return WrapUserError(MsgFailedRenderResults, err)
 	}
 
-	invitationMsg, err := b.SendWithRetry(c.Chat(), invitationHTML, &tele.SendOptions{
+	invitationOpts := &tele.SendOptions{
 		ParseMode: tele.ModeHTML,
-	})
+	}
+	if config.FeatureFlags.InlineVoting {
+		invitationOpts.ReplyMarkup = voteKeyboard(p)
+	}
+
+	invitationMsg, err := b.SendWithRetry(c.Chat(), invitationHTML, invitationOpts)
 	if err != nil {
 		rollbackPoll()
 		return WrapUserError(MsgFailedSendResults, err)
@@ -76,6 +81,15 @@ func (b *Bot) handlePoll(c tele.Context) error {
 	// Store invitation message ID
 	p.TgInvitationMessageID = invitationMsg.ID
 
+	// With inline voting the invitation message is the poll, no native poll is sent
+	if config.FeatureFlags.InlineVoting {
+		p.TgMessageID = invitationMsg.ID
+		if err := b.pollService.UpdatePoll(p); err != nil {
+			return WrapUserError(MsgFailedSavePoll, err)
+		}
+		return nil
+	}
+
 	// Render poll title from template
 	pollTitle, err := RenderPollTitleMessage(config.templates, eventDate)
 	if err != nil {
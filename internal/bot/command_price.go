@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+// handlePrice sets the per-player price of the latest event and refreshes the invitation.
+// Usage: /price <amount> — amount in ₾, 0 hides the price and excludes the event from debts.
+func (b *Bot) handlePrice(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return UserErrorf(MsgPriceUsage)
+	}
+	price, err := strconv.Atoi(args[0])
+	if err != nil || price < 0 {
+		return UserErrorf(MsgPriceUsage)
+	}
+
+	p, err := b.paymentPoll(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	if err := b.pollService.SetPrice(p, price); err != nil {
+		return WrapUserError(MsgFailedSavePrice, err)
+	}
+
+	b.logger.Info("price set", "poll_id", p.ID, "price", price)
+
+	b.UpdateInvitationMessage(p, nil)
+
+	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtPriceSet, price), 0)
+	return err
+}
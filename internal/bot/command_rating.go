@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// RatingSize is the number of players shown by /rating
+const RatingSize = 15
+
+// handleRating shows the club's Elo-style player rating computed from recorded games.
+// Usage:
+//
+//	/rating          — all games
+//	/rating <period> — games within a period supported by /stats (month, season, 2025, ...)
+//
+// If the sender is rated but not in the top, their own position is shown as well.
+func (b *Bot) handleRating(c tele.Context) error {
+	config := getClubConfig(c)
+
+	period := statsPeriod{Label: "всё время"}
+	if arg := strings.Join(c.Args(), " "); arg != "" {
+		var err error
+		period, err = parseStatsPeriod(arg, time.Now())
+		if err != nil {
+			return UserErrorf(MsgInvalidStatsPeriod)
+		}
+	}
+
+	ratings, err := b.pollService.GetRatings(config.Club, period.StatsPeriod)
+	if err != nil {
+		return WrapUserError(MsgFailedGetRating, err)
+	}
+
+	// Resolve display names via nickname enrichment
+	votes := make([]*poll.Vote, 0, len(ratings))
+	for _, r := range ratings {
+		votes = append(votes, &poll.Vote{
+			TgUserID:    r.TgUserID,
+			TgUsername:  r.TgUsername,
+			TgFirstName: r.Name,
+		})
+	}
+	members := b.membersFromVotesWithCache(config.Club, votes, nil)
+
+	data := &RatingData{PeriodLabel: period.Label}
+	for i, r := range ratings {
+		row := &RatingRow{Rank: i + 1, Member: members[i], Rating: r}
+		if i < RatingSize {
+			data.Rows = append(data.Rows, row)
+		} else if r.TgUserID == c.Sender().ID {
+			data.Own = row
+		}
+	}
+
+	html, err := RenderRatingMessage(config.templates, data)
+	if err != nil {
+		return WrapUserError(MsgFailedRenderRating, err)
+	}
+
+	_, err = b.SendTemporary(c.Chat(), html, 30*time.Second, tele.ModeHTML)
+	return err
+}
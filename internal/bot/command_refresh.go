@@ -43,18 +43,7 @@ func (b *Bot) UpdateDoneMessage(p *poll.Poll, config *ClubConfig) bool {
 		}
 	}
 
-	// Build a single cache for all voters (more efficient than 2 separate caches)
-	cache, cacheErr := b.buildNicknameCacheFromVotes(mainVoters, comingLater)
-	if cacheErr != nil {
-		b.logger.Warn("failed to build nickname cache for refresh", "error", cacheErr)
-	}
-
-	html, err := RenderCollectedMessage(config.templates, &CollectedData{
-		EventDate:   p.EventDate,
-		StartTime:   startTime,
-		Members:     b.membersFromVotesWithCache(mainVoters, cache),
-		ComingLater: b.membersFromVotesWithCache(comingLater, cache),
-	})
+	html, err := RenderCollectedMessage(config.templates, b.buildCollectedData(config, p, startTime, mainVoters, comingLater))
 	if err != nil {
 		b.logger.Warn("failed to render collected message for refresh", "error", err)
 		return false
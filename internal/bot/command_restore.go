@@ -24,12 +24,18 @@ func (b *Bot) handleRestore(c tele.Context) error {
 		return WrapUserError(MsgFailedRestorePoll, err)
 	}
 
+	return b.announceRestore(p, config)
+}
+
+// announceRestore updates the chat after a poll was restored: removes the cancellation footer
+// and notification and posts a restore message with mentions.
+func (b *Bot) announceRestore(p *poll.Poll, config *ClubConfig) error {
 	// Update invitation message to remove cancellation footer
 	notCancelled := false
 	b.UpdateInvitationMessage(p, &notCancelled)
 
 	// Delete cancellation notification message
-	if p.TgCancelMessageID != 0 {
+	if p.IsCancelled() {
 		if err := b.bot.Delete(MessageRef(p.TgChatID, p.TgCancelMessageID)); err != nil {
 			b.logger.Warn("failed to delete cancellation message", "error", err)
 		}
@@ -43,7 +49,7 @@ func (b *Bot) handleRestore(c tele.Context) error {
 	}
 
 	// Render and send restore message
-	_, err = b.RenderAndSend(c, func() (string, error) {
+	_, err = b.renderAndSendTo(MessageRef(p.TgChatID, 0).Chat, func() (string, error) {
 		return RenderRestoreMessage(config.templates, &RestoreData{
 			EventDate: p.EventDate,
 			Members:   MembersFromVotes(votes),
@@ -33,7 +33,7 @@ func (b *Bot) handleResults(c tele.Context) error {
 	allVotes = append(allVotes, invData.Undecided...)
 
 	// Pre-fetch all nicknames in one batch
-	cache, err := b.pollService.NewNicknameCacheFromVotes(allVotes)
+	cache, err := b.pollService.NewNicknameCacheFromVotes(config.Club, allVotes)
 	if err != nil {
 		b.logger.Warn("failed to batch fetch nicknames for results", "error", err)
 		// Continue without nicknames - cache will be nil
@@ -48,6 +48,28 @@ func (b *Bot) handleResults(c tele.Context) error {
 		Undecided:   b.votesToResultsVotersAllWithCache(invData.Undecided, cache),
 	}
 
+	// Attach no-show counters from past events
+	noShows, err := b.pollService.GetNoShowCounts(config.Club, allVotes)
+	if err != nil {
+		b.logger.Warn("failed to get no-show counts for results", "error", err)
+	} else {
+		applyNoShows(resultsData.At19, noShows)
+		applyNoShows(resultsData.At20, noShows)
+		applyNoShows(resultsData.ComingLater, noShows)
+		applyNoShows(resultsData.Undecided, noShows)
+	}
+
+	// List attending players who have not paid yet
+	if p.Price > 0 {
+		resultsData.Price = p.Price
+		unpaid, err := b.pollService.GetUnpaidVotes(p.ID)
+		if err != nil {
+			b.logger.Warn("failed to get unpaid voters for results", "error", err)
+		} else {
+			resultsData.Unpaid = b.membersFromVotesWithCache(config.Club, unpaid, cache)
+		}
+	}
+
 	// Render results message
 	html, err := RenderResultsMessage(config.templates, resultsData)
 	if err != nil {
@@ -59,3 +81,9 @@ func (b *Bot) handleResults(c tele.Context) error {
 	return err
 }
 
+// applyNoShows sets the no-show counter on each voter from the given counts.
+func applyNoShows(voters []ResultsVoter, counts map[int64]int) {
+	for i := range voters {
+		voters[i].NoShows = counts[voters[i].TgID]
+	}
+}
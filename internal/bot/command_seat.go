@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// handleSeat randomly seats the collected main voters and posts the seating chart.
+// Requires /done to have been called so the start time is known.
+// Up to poll.MaxSeats people (in vote order) get a seat, guests next to their host;
+// the rest are shown as reserves.
+func (b *Bot) handleSeat(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+	if p.StartTime == "" {
+		return UserErrorf(MsgNotCollectedYet)
+	}
+
+	data, err := b.pollService.GetCollectedData(p.ID)
+	if err != nil {
+		return WrapUserError(MsgFailedGetResults, err)
+	}
+	mainVoters, _ := poll.SplitVotersByStartTime(data, p.StartTime)
+	if poll.HeadCount(mainVoters) < poll.MinGamePlayers {
+		return UserErrorf(MsgNotEnoughPlayersToSeat)
+	}
+
+	members := b.membersFromVotesWithCache(config.Club, mainVoters, nil)
+	var players []*poll.GameParticipant
+	var reserves []Member
+	for _, m := range members {
+		if len(reserves) > 0 || len(players)+1+m.Guests > poll.MaxSeats {
+			reserves = append(reserves, m)
+			continue
+		}
+		players = append(players, &poll.GameParticipant{
+			TgUserID:   m.TgID,
+			TgUsername: m.TgUsername,
+			Name:       m.DisplayName(),
+		})
+		// Guests have no Telegram account of their own: the judge tells them their roles
+		for n := 1; n <= m.Guests; n++ {
+			players = append(players, &poll.GameParticipant{Name: fmt.Sprintf(MsgFmtGuestSeat, m.DisplayName(), n)})
+		}
+	}
+
+	seated, err := b.pollService.SeatPlayers(p.ID, players)
+	if err != nil {
+		return WrapUserError(MsgFailedSeat, err)
+	}
+
+	b.logger.Info("players seated", "poll_id", p.ID, "players", len(seated), "reserves", len(reserves))
+
+	_, err = b.RenderAndSend(c, func() (string, error) {
+		return RenderSeatingMessage(config.templates, &SeatingData{
+			EventDate: p.EventDate,
+			Players:   seated,
+			Reserves:  reserves,
+		})
+	}, MsgFailedRenderSeating, MsgFailedSendSeating)
+	return err
+}
+
+// handleDeal randomly deals roles to the seated players and sends each player
+// their role card in a private message. The judge receives the full role list.
+// Usage:
+//
+//	/deal              — the event judge (see /judge) or the sender
+//	/deal <@user|nick> — another judge
+//
+// Players and the judge must have started a private chat with the bot to receive messages.
+func (b *Bot) handleDeal(c tele.Context) error {
+	config := getClubConfig(c)
+
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	judgeID := c.Sender().ID
+	if p.JudgeUserID > 0 {
+		judgeID = p.JudgeUserID
+	}
+	if args := c.Args(); len(args) > 0 {
+		judgeID, _, _, err = b.pollService.ResolveVoteIdentifier(config.Club, strings.Join(args, " "))
+		if err != nil {
+			return WrapUserError(MsgFailedDealRoles, err)
+		}
+		if judgeID <= 0 {
+			return UserErrorf(MsgJudgeNotReachable)
+		}
+	}
+
+	players, err := b.pollService.DealSeatedRoles(p.ID)
+	if err != nil {
+		if errors.Is(err, poll.ErrInvalidPlayerCount) {
+			return UserErrorf(MsgNotEnoughPlayersToSeat)
+		}
+		return WrapUserError(MsgFailedDealRoles, err)
+	}
+	if len(players) == 0 {
+		return UserErrorf(MsgNoSeating)
+	}
+
+	// Send the full list to the judge first: without it the game can't be hosted,
+	// so nobody gets a card if the judge is unreachable.
+	listData := &RoleListData{EventDate: p.EventDate, Players: players, Undelivered: make(map[int]bool)}
+	for _, pl := range players {
+		if pl.TgUserID <= 0 {
+			listData.Undelivered[pl.Seat] = true
+		}
+	}
+	html, err := RenderRoleListMessage(config.templates, listData)
+	if err != nil {
+		return WrapUserError(MsgFailedDealRoles, err)
+	}
+	judgeMsg, err := b.sendPrivate(judgeID, html)
+	if isForbiddenErr(err) {
+		b.logger.Info("judge has not started the bot", "error", err, "judge_user_id", judgeID)
+		return UserErrorf(MsgJudgeNotReachable)
+	}
+	if err != nil {
+		return WrapUserError(MsgFailedDealRoles, err)
+	}
+
+	// Send role cards; players without a real Telegram ID can't be messaged
+	var undelivered []string
+	for _, pl := range players {
+		if pl.TgUserID > 0 {
+			card, err := RenderRoleCardMessage(config.templates, &RoleCardData{EventDate: p.EventDate, Player: pl})
+			if err == nil {
+				_, err = b.sendPrivate(pl.TgUserID, card)
+			}
+			if err == nil {
+				continue
+			}
+			if isForbiddenErr(err) {
+				b.logger.Info("player has not started the bot", "error", err, "user_id", pl.TgUserID, "seat", pl.Seat)
+			} else {
+				b.logger.Warn("failed to send role card", "error", err, "user_id", pl.TgUserID, "seat", pl.Seat)
+			}
+			listData.Undelivered[pl.Seat] = true
+		}
+		undelivered = append(undelivered, pl.Name)
+	}
+
+	// Update the judge's list with delivery failures discovered while sending
+	if len(undelivered) > 0 {
+		if html, err := RenderRoleListMessage(config.templates, listData); err == nil {
+			if _, err := b.bot.Edit(judgeMsg, html, tele.ModeHTML); err != nil && !isNotModifiedErr(err) {
+				b.logger.Warn("failed to update judge role list", "error", err)
+			}
+		}
+	}
+
+	b.logger.Info("roles dealt",
+		"poll_id", p.ID,
+		"judge_user_id", judgeID,
+		"players", len(players),
+		"undelivered", len(undelivered),
+	)
+
+	msg := fmt.Sprintf(MsgFmtRolesDealt, len(players)-len(undelivered), len(players))
+	if len(undelivered) > 0 {
+		msg += "\n" + fmt.Sprintf(MsgFmtRolesNotDelivered, strings.Join(undelivered, ", "))
+	}
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// sendPrivate sends an HTML message to a user's private chat. It does not retry: until the user
+// starts the bot Telegram answers 403, and retries would only delay the other role cards.
+func (b *Bot) sendPrivate(userID int64, html string) (*tele.Message, error) {
+	return b.bot.Send(&tele.User{ID: userID}, html, tele.ModeHTML)
+}
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// statsPeriod is a parsed /stats period with its display label.
+type statsPeriod struct {
+	poll.StatsPeriod
+	Label string
+}
+
+// parseStatsPeriod parses a period argument relative to now.
+// Supports:
+// - Empty, "month", "месяц": current calendar month
+// - "week", "неделя": last 7 days including today
+// - "season", "сезон": current season (winter starts in December)
+// - "year", "год": current calendar year
+// - "all", "все", "всё": no bounds
+// - "YYYY-MM": given month
+// - "YYYY": given year
+func parseStatsPeriod(arg string, now time.Time) (statsPeriod, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(strings.TrimSpace(arg)) {
+	case "", "month", "месяц":
+		return monthPeriod(monthStart), nil
+	case "week", "неделя":
+		return statsPeriod{
+			StatsPeriod: poll.StatsPeriod{From: today.AddDate(0, 0, -6), To: today.AddDate(0, 0, 1)},
+			Label:       "последние 7 дней",
+		}, nil
+	case "season", "сезон":
+		return seasonPeriod(now), nil
+	case "year", "год":
+		return yearPeriod(yearStart), nil
+	case "all", "все", "всё":
+		return statsPeriod{Label: "всё время"}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", arg, now.Location()); err == nil {
+		return monthPeriod(t), nil
+	}
+	if t, err := time.ParseInLocation("2006", arg, now.Location()); err == nil {
+		return yearPeriod(t), nil
+	}
+
+	return statsPeriod{}, fmt.Errorf("invalid period: %s", arg)
+}
+
+// monthPeriod returns the calendar month starting at the given date.
+func monthPeriod(start time.Time) statsPeriod {
+	return statsPeriod{
+		StatsPeriod: poll.StatsPeriod{From: start, To: start.AddDate(0, 1, 0)},
+		Label:       fmt.Sprintf("%s %d", russianMonthsNominative[start.Month()-1], start.Year()),
+	}
+}
+
+// Russian season names, indexed by season number (0 = winter)
+var russianSeasons = []string{"зима", "весна", "лето", "осень"}
+
+// seasonPeriod returns the three-month season containing the given date.
+// Seasons start in December, March, June and September.
+func seasonPeriod(t time.Time) statsPeriod {
+	// Shift months so that December becomes the first month of the winter season
+	shifted := int(t.Month()) % 12 // Dec=0, Jan=1, ... Nov=11
+	season := shifted / 3
+	year := t.Year()
+	if t.Month() == time.December {
+		year++
+	}
+	start := time.Date(year, time.Month(season*3), 1, 0, 0, 0, 0, t.Location()) // month 0 normalizes to December of the previous year
+
+	label := fmt.Sprintf("%s %d", russianSeasons[season], start.Year())
+	if season == 0 {
+		label = fmt.Sprintf("%s %d/%02d", russianSeasons[season], start.Year(), (start.Year()+1)%100)
+	}
+
+	return statsPeriod{
+		StatsPeriod: poll.StatsPeriod{From: start, To: start.AddDate(0, 3, 0)},
+		Label:       label,
+	}
+}
+
+// yearPeriod returns the calendar year starting at the given date.
+func yearPeriod(start time.Time) statsPeriod {
+	return statsPeriod{
+		StatsPeriod: poll.StatsPeriod{From: start, To: start.AddDate(1, 0, 0)},
+		Label:       fmt.Sprintf("%d год", start.Year()),
+	}
+}
+
+// handleStats shows attendance statistics for a player.
+// Usage:
+//
+//	/stats                      — own stats for the current month
+//	/stats @username [period]   — by telegram username
+//	/stats gamenick [period]    — by game nickname (quoted if with spaces)
+//	/stats [period]             — own stats for the given period
+//
+// Period: month/week/season/year/all or YYYY-MM / YYYY.
+func (b *Bot) handleStats(c tele.Context) error {
+	config := getClubConfig(c)
+
+	tokens, err := tokenize(strings.Join(c.Args(), " "))
+	if err != nil || len(tokens) > 2 {
+		return UserErrorf(MsgStatsUsage)
+	}
+
+	// The last token is the period if it parses as one
+	period, _ := parseStatsPeriod("", time.Now())
+	if len(tokens) > 0 {
+		if p, err := parseStatsPeriod(tokens[len(tokens)-1], time.Now()); err == nil {
+			period = p
+			tokens = tokens[:len(tokens)-1]
+		} else if len(tokens) == 2 {
+			return UserErrorf(MsgInvalidStatsPeriod)
+		}
+	}
+
+	// Resolve target player: sender by default
+	userID := c.Sender().ID
+	username := c.Sender().Username
+	displayName := c.Sender().FirstName
+	if len(tokens) == 1 {
+		var found bool
+		userID, username, displayName, found, err = b.pollService.ResolvePlayer(config.Club, tokens[0])
+		if err != nil {
+			return WrapUserError(MsgFailedGetStats, err)
+		}
+		if !found {
+			return UserErrorf(MsgNickNotFound)
+		}
+	}
+
+	stats, err := b.pollService.GetPlayerStats(config.Club, userID, username, period.StatsPeriod)
+	if err != nil {
+		return WrapUserError(MsgFailedGetStats, err)
+	}
+
+	// Prefer game nickname for display
+	cache, err := b.pollService.NewNicknameCache(config.Club, []poll.NicknameLookupKey{{UserID: userID, Username: username}})
+	if err != nil {
+		b.logger.Warn("failed to fetch nickname for stats", "error", err)
+	} else if nick := cache.GetDisplayNick(userID, username); nick != "" {
+		displayName = nick
+	}
+
+	html, err := RenderStatsMessage(config.templates, &StatsData{
+		Name:        displayName,
+		PeriodLabel: period.Label,
+		Stats:       stats,
+	})
+	if err != nil {
+		return WrapUserError(MsgFailedRenderStats, err)
+	}
+
+	_, err = b.SendTemporary(c.Chat(), html, 30*time.Second, tele.ModeHTML)
+	return err
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStatsPeriod(t *testing.T) {
+	now := time.Date(2025, 3, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input     string
+		wantFrom  string
+		wantTo    string
+		wantLabel string
+		wantErr   bool
+	}{
+		{"", "2025-03-01", "2025-04-01", "март 2025", false},
+		{"месяц", "2025-03-01", "2025-04-01", "март 2025", false},
+		{"week", "2025-03-09", "2025-03-16", "последние 7 дней", false},
+		{"сезон", "2025-03-01", "2025-06-01", "весна 2025", false},
+		{"год", "2025-01-01", "2026-01-01", "2025 год", false},
+		{"all", "", "", "всё время", false},
+		{"2024-12", "2024-12-01", "2025-01-01", "декабрь 2024", false},
+		{"2024", "2024-01-01", "2025-01-01", "2024 год", false},
+		{"Кот", "", "", "", true},
+		{"2024-13", "", "", "", true},
+	}
+
+	format := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02")
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseStatsPeriod(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseStatsPeriod(%q) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStatsPeriod(%q) unexpected error: %v", tt.input, err)
+			}
+			if format(got.From) != tt.wantFrom || format(got.To) != tt.wantTo {
+				t.Errorf("parseStatsPeriod(%q) = [%s, %s), want [%s, %s)", tt.input, format(got.From), format(got.To), tt.wantFrom, tt.wantTo)
+			}
+			if got.Label != tt.wantLabel {
+				t.Errorf("parseStatsPeriod(%q) label = %q, want %q", tt.input, got.Label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestSeasonPeriod(t *testing.T) {
+	tests := []struct {
+		date      time.Time
+		wantFrom  string
+		wantLabel string
+	}{
+		{time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), "2024-12-01", "зима 2024/25"},
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), "2024-12-01", "зима 2024/25"},
+		{time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), "2025-03-01", "весна 2025"},
+		{time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), "2025-06-01", "лето 2025"},
+		{time.Date(2025, 11, 30, 0, 0, 0, 0, time.UTC), "2025-09-01", "осень 2025"},
+	}
+
+	for _, tt := range tests {
+		got := seasonPeriod(tt.date)
+		if got.From.Format("2006-01-02") != tt.wantFrom {
+			t.Errorf("seasonPeriod(%s) from = %s, want %s", tt.date.Format("2006-01-02"), got.From.Format("2006-01-02"), tt.wantFrom)
+		}
+		if !got.To.Equal(got.From.AddDate(0, 3, 0)) {
+			t.Errorf("seasonPeriod(%s) to = %s, want 3 months after from", tt.date.Format("2006-01-02"), got.To.Format("2006-01-02"))
+		}
+		if got.Label != tt.wantLabel {
+			t.Errorf("seasonPeriod(%s) label = %q, want %q", tt.date.Format("2006-01-02"), got.Label, tt.wantLabel)
+		}
+	}
+}
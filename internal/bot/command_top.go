@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// LeaderboardSize is the number of players shown by /top
+const LeaderboardSize = 10
+
+// handleTop shows the club leaderboard ranked by events played.
+// Usage:
+//
+//	/top          — current month
+//	/top season   — current season
+//	/top <period> — any period supported by /stats
+func (b *Bot) handleTop(c tele.Context) error {
+	config := getClubConfig(c)
+
+	period, err := parseStatsPeriod(strings.Join(c.Args(), " "), time.Now())
+	if err != nil {
+		return UserErrorf(MsgInvalidStatsPeriod)
+	}
+
+	rows, err := b.leaderboardRows(config.Club, period.StatsPeriod, LeaderboardSize)
+	if err != nil {
+		return WrapUserError(MsgFailedGetStats, err)
+	}
+
+	html, err := RenderTopMessage(config.templates, &TopData{
+		PeriodLabel: period.Label,
+		Rows:        rows,
+	})
+	if err != nil {
+		return WrapUserError(MsgFailedRenderStats, err)
+	}
+
+	_, err = b.SendTemporary(c.Chat(), html, 30*time.Second, tele.ModeHTML)
+	return err
+}
+
+// leaderboardRows fetches the club leaderboard and resolves player display names.
+func (b *Bot) leaderboardRows(club poll.Club, period poll.StatsPeriod, limit int) ([]LeaderboardRow, error) {
+	entries, err := b.pollService.GetLeaderboard(club, period, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse vote-based nickname enrichment for display names
+	votes := make([]*poll.Vote, 0, len(entries))
+	for _, e := range entries {
+		votes = append(votes, &poll.Vote{
+			TgUserID:    e.TgUserID,
+			TgUsername:  e.TgUsername,
+			TgFirstName: e.TgFirstName,
+		})
+	}
+	members := b.membersFromVotesWithCache(club, votes, nil)
+
+	rows := make([]LeaderboardRow, 0, len(entries))
+	for i, e := range entries {
+		rows = append(rows, LeaderboardRow{
+			Rank:         i + 1,
+			Member:       members[i],
+			EventsPlayed: e.EventsPlayed,
+		})
+	}
+	return rows, nil
+}
@@ -3,83 +3,553 @@ package bot
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	tele "gopkg.in/telebot.v4"
 
+	"nuclight.org/consigliere/internal/metrics"
 	"nuclight.org/consigliere/internal/poll"
 )
 
+// Callback button identifier for confirming a guessed nickname in /vote
+const callbackVoteConfirm = "vote_fix"
+
+// VoteConfirmationTTL is how long a /vote nickname confirmation prompt stays answerable.
+const VoteConfirmationTTL = 10 * time.Minute
+
 // handleVote manually records a vote for a user
 // Usage:
 //
 //	/vote @username <option 1-5> — vote by telegram username
 //	/vote gamenick <option 1-5>  — vote by game nickname (no @ prefix)
+//	/vote gamenick 1 +2          — vote and register the player's guests (+0 removes them)
+//	/vote 1 Кот @ivan "Мадам Жу" — same option for several players at once
+//	/vote 2 [+1]                 — in reply to a player's message: vote for its author
 //
 // Options: 1=19:00, 2=20:00, 3=21:00+, 4=decide later, 5=not coming
+//
+// Game nicknames match regardless of case. A nickname that is only close to a known one
+// (a typo, or typed in the other alphabet) is not recorded right away: the admin is asked
+// whether the known player was meant before a new synthetic player is created.
 func (b *Bot) handleVote(c tele.Context) error {
-	args := c.Args()
+	config := getClubConfig(c)
+
+	args, err := tokenize(strings.Join(c.Args(), " "))
+	if err != nil || len(args) == 0 {
+		return UserErrorf(MsgVoteUsage)
+	}
+
+	if user := repliedUser(c.Message()); user != nil && isReplyVote(args) {
+		return b.handleReplyVote(c, config, user, args)
+	}
 	if len(args) < 2 {
 		return UserErrorf(MsgVoteUsage)
 	}
 
+	if isBulkVote(args) {
+		return b.handleBulkVote(c, config, args)
+	}
+	if len(args) > 3 {
+		return UserErrorf(MsgVoteUsage)
+	}
+
 	identifier := args[0]
 	if identifier == "" {
 		return UserErrorf(MsgInvalidUsername)
 	}
 
-	// Parse option number (1-5)
-	optionNum, err := strconv.Atoi(args[1])
-	if err != nil || optionNum < 1 || optionNum > 5 {
-		return UserErrorf(MsgInvalidVoteOption)
+	optionIndex, guests, err := parseVoteOptionAndGuests(args[1:])
+	if err != nil {
+		return err
 	}
 
-	// Convert to 0-indexed option
-	optionIndex := optionNum - 1
+	return b.castManualVote(c, config, []string{identifier}, optionIndex, guests)
+}
 
-	// Resolve the identifier to user info
-	userID, username, displayName, err := b.pollService.ResolveVoteIdentifier(identifier)
+// handleReplyVote records a vote for the author of the message the admin replied to,
+// taking their Telegram identity from the message instead of resolving a name.
+func (b *Bot) handleReplyVote(c tele.Context, config *ClubConfig, user *tele.User, args []string) error {
+	optionIndex, guests, err := parseVoteOptionAndGuests(args)
 	if err != nil {
-		return WrapUserError(MsgFailedRecordVote, err)
+		return err
+	}
+
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	name := Member{TgName: user.FirstName, TgUsername: user.Username}.DisplayName()
+	if nick, err := b.pollService.GetDisplayNick(config.Club, user.ID, user.Username); err != nil {
+		b.logger.Warn("failed to fetch nickname", "error", err, "user_id", user.ID)
+	} else if nick != "" {
+		name = nick
 	}
 
 	b.logger.Info("vote parameters",
-		"identifier", identifier,
-		"resolved_user_id", userID,
-		"resolved_username", username,
-		"display_name", displayName,
+		"actor_user_id", c.Sender().ID,
+		"reply_to_user_id", user.ID,
+		"reply_to_username", user.Username,
+		"display_name", name,
 		"option", OptionLabel(poll.OptionKind(optionIndex)),
 	)
 
+	msg, err := b.saveManualVote(c, config, p, &poll.Vote{
+		TgUserID:      user.ID,
+		TgUsername:    user.Username,
+		TgFirstName:   user.FirstName,
+		TgOptionIndex: optionIndex,
+	}, name, guests)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// repliedUser returns the author of the message m replies to, or nil if m is not a reply
+// to a person (no reply, a bot, an anonymous admin or a channel, or the start of a forum topic).
+func repliedUser(m *tele.Message) *tele.User {
+	reply := m.ReplyTo
+	if reply == nil || reply.TopicCreated != nil || reply.SenderChat != nil {
+		return nil
+	}
+	if reply.Sender == nil || reply.Sender.IsBot {
+		return nil
+	}
+	return reply.Sender
+}
+
+// isReplyVote reports whether /vote arguments use the reply form: an option and optional guests.
+func isReplyVote(args []string) bool {
+	if len(args) > 2 {
+		return false
+	}
+	if _, ok := parseVoteOption(args[0]); !ok {
+		return false
+	}
+	return len(args) == 1 || strings.HasPrefix(args[1], "+")
+}
+
+// parseVoteOptionAndGuests parses "<option 1-5> [+guests]" into a 0-indexed option
+// and a guest count, -1 if guests are not specified.
+func parseVoteOptionAndGuests(args []string) (int, int, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return 0, 0, UserErrorf(MsgVoteUsage)
+	}
+
+	guests := -1 // not specified
+	if len(args) == 2 {
+		if !strings.HasPrefix(args[1], "+") {
+			return 0, 0, UserErrorf(MsgVoteUsage)
+		}
+		n, err := parseGuestCount(args[1])
+		if err != nil {
+			return 0, 0, UserErrorf(MsgVoteUsage)
+		}
+		if n > poll.MaxGuests {
+			return 0, 0, UserErrorf(MsgInvalidGuestCount)
+		}
+		guests = n
+	}
+
+	optionIndex, ok := parseVoteOption(args[0])
+	if !ok {
+		return 0, 0, UserErrorf(MsgInvalidVoteOption)
+	}
+	if guests > 0 && !poll.OptionKind(optionIndex).IsAttending() {
+		return 0, 0, UserErrorf(MsgGuestHostNotAttending)
+	}
+	return optionIndex, guests, nil
+}
+
+// handleBulkVote records the same option for every player listed after it.
+// Names in quotes may contain spaces; guests can only be given in the single-player form.
+func (b *Bot) handleBulkVote(c tele.Context, config *ClubConfig, args []string) error {
+	optionIndex, _ := parseVoteOption(args[0])
+
+	identifiers := args[1:]
+	for _, identifier := range identifiers {
+		if identifier == "" || identifier == "@" {
+			return UserErrorf(MsgInvalidUsername)
+		}
+		if strings.HasPrefix(identifier, "+") {
+			return UserErrorf(MsgVoteUsage)
+		}
+	}
+
+	return b.castManualVote(c, config, identifiers, optionIndex, -1)
+}
+
+// isBulkVote reports whether /vote arguments use the bulk form: the option first, then the players.
+// "/vote 2 1" stays a single vote for the player named "2".
+func isBulkVote(args []string) bool {
+	if _, ok := parseVoteOption(args[0]); !ok {
+		return false
+	}
+	_, ok := parseVoteOption(args[1])
+	return !ok
+}
+
+// parseVoteOption parses an option number (1-5) into a 0-indexed option.
+func parseVoteOption(arg string) (int, bool) {
+	optionNum, err := strconv.Atoi(arg)
+	if err != nil || optionNum < 1 || optionNum > 5 {
+		return 0, false
+	}
+	return optionNum - 1, true
+}
+
+// castManualVote matches game nicknames against the club's nicknames and records the votes,
+// or asks the admin to confirm the guessed nicknames first.
+func (b *Bot) castManualVote(c tele.Context, config *ClubConfig, identifiers []string, optionIndex, guests int) error {
 	// Get active poll (validates event date hasn't passed)
 	p, err := b.GetActivePollForAction(c.Chat().ID)
 	if err != nil {
 		return err
 	}
 
+	v := &pendingVote{
+		chatID:      c.Chat().ID,
+		identifiers: make([]string, len(identifiers)),
+		suggestions: make([]string, len(identifiers)),
+		optionIndex: optionIndex,
+		guests:      guests,
+	}
+	for i, identifier := range identifiers {
+		v.identifiers[i] = identifier
+		if identifier[0] == '@' {
+			continue
+		}
+		nick, certain, err := b.pollService.MatchNickname(config.Club, identifier)
+		if err != nil {
+			return WrapUserError(MsgFailedRecordVote, err)
+		}
+		if certain {
+			v.identifiers[i] = nick
+		} else {
+			v.suggestions[i] = nick
+		}
+	}
+
+	if v.hasSuggestions() {
+		return b.askVoteConfirmation(c, v)
+	}
+
+	msg, err := b.recordPendingVote(c, config, p, v)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.SendTemporary(c.Chat(), msg, 0)
+	return err
+}
+
+// recordPendingVote records the votes of v and returns the confirmation to show in chat.
+func (b *Bot) recordPendingVote(c tele.Context, config *ClubConfig, p *poll.Poll, v *pendingVote) (string, error) {
+	if len(v.identifiers) == 1 {
+		return b.recordManualVote(c, config, p, v.identifiers[0], v.optionIndex, v.guests)
+	}
+	return b.recordManualVotes(c, config, p, v.identifiers, v.optionIndex)
+}
+
+// recordManualVote records an admin's vote for the player behind identifier.
+// Returns the confirmation to show in chat.
+func (b *Bot) recordManualVote(c tele.Context, config *ClubConfig, p *poll.Poll, identifier string, optionIndex, guests int) (string, error) {
+	// Resolve the identifier to user info
+	userID, username, displayName, err := b.pollService.ResolveVoteIdentifier(config.Club, identifier)
+	if err != nil {
+		return "", WrapUserError(MsgFailedRecordVote, err)
+	}
+
+	b.logger.Info("vote parameters",
+		"actor_user_id", c.Sender().ID,
+		"identifier", identifier,
+		"resolved_user_id", userID,
+		"resolved_username", username,
+		"display_name", displayName,
+		"option", OptionLabel(poll.OptionKind(optionIndex)),
+	)
+
 	// Create vote with resolved user ID
-	v := &poll.Vote{
-		PollID:        p.ID,
+	return b.saveManualVote(c, config, p, &poll.Vote{
 		TgUserID:      userID,
 		TgUsername:    username,
 		TgFirstName:   displayName,
 		TgOptionIndex: optionIndex,
-		IsManual:      true,
-	}
+	}, displayName, guests)
+}
+
+// saveManualVote records v in poll p on behalf of the sender, sets the voter's guests
+// if guests is not negative, and refreshes the poll messages.
+// name is how the voter is called in the returned confirmation.
+func (b *Bot) saveManualVote(c tele.Context, config *ClubConfig, p *poll.Poll, v *poll.Vote, name string, guests int) (string, error) {
+	actorUserID := c.Sender().ID
+	v.PollID = p.ID
+	v.IsManual = true
+	v.ActorUserID = actorUserID
 
 	if err := b.pollService.RecordVote(v); err != nil {
-		return WrapUserError(MsgFailedRecordVote, err)
+		return "", WrapUserError(MsgFailedRecordVote, err)
 	}
+	metrics.Votes.WithLabelValues(metrics.VoteSourceAdmin).Inc()
 
 	// Ensure data consistency if we have a real user ID
-	if userID > 0 {
-		if err := b.pollService.EnsureUserDataConsistency(c.Chat().ID, userID, username); err != nil {
+	if v.TgUserID > 0 {
+		if err := b.pollService.EnsureUserDataConsistency(c.Chat().ID, v.TgUserID, v.TgUsername); err != nil {
 			b.logger.Warn("failed to ensure user data consistency", "error", err)
 		}
 	}
 
-	// Update invitation message if exists
+	msg := fmt.Sprintf(MsgFmtVoteRecorded, name, OptionLabel(poll.OptionKind(v.TgOptionIndex)))
+	if guests >= 0 {
+		// setGuests refreshes the invitation and collected messages
+		if err := b.setGuests(config, p, v.TgUserID, guests, actorUserID); err != nil {
+			return "", err
+		}
+		msg += "\n" + guestsMessage(name, guests)
+	} else {
+		// Update invitation message if exists
+		b.UpdateInvitationMessage(p, nil)
+	}
+
+	return msg, nil
+}
+
+// recordManualVotes records the same option for several players in one transaction and
+// refreshes the invitation once. Players listed twice are counted once.
+// Returns the confirmation listing known players and new synthetic ones.
+func (b *Bot) recordManualVotes(c tele.Context, config *ClubConfig, p *poll.Poll, identifiers []string, optionIndex int) (string, error) {
+	actorUserID := c.Sender().ID
+
+	var votes []*poll.Vote
+	var known, unknown []string
+	seen := make(map[int64]bool)
+	for _, identifier := range identifiers {
+		userID, username, displayName, err := b.pollService.ResolveVoteIdentifier(config.Club, identifier)
+		if err != nil {
+			return "", WrapUserError(MsgFailedRecordVote, err)
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		votes = append(votes, &poll.Vote{
+			PollID:        p.ID,
+			TgUserID:      userID,
+			TgUsername:    username,
+			TgFirstName:   displayName,
+			TgOptionIndex: optionIndex,
+			IsManual:      true,
+			ActorUserID:   actorUserID,
+		})
+		if isKnownVoter(identifier, userID, username) {
+			known = append(known, displayName)
+		} else {
+			unknown = append(unknown, displayName)
+		}
+	}
+
+	if err := b.pollService.RecordVotes(votes); err != nil {
+		return "", WrapUserError(MsgFailedRecordVote, err)
+	}
+	metrics.Votes.WithLabelValues(metrics.VoteSourceAdmin).Add(float64(len(votes)))
+
+	b.logger.Info("bulk votes recorded",
+		"actor_user_id", actorUserID,
+		"poll_id", p.ID,
+		"option", OptionLabel(poll.OptionKind(optionIndex)),
+		"known", known,
+		"new", unknown,
+	)
+
+	for _, v := range votes {
+		if v.TgUserID > 0 {
+			if err := b.pollService.EnsureUserDataConsistency(c.Chat().ID, v.TgUserID, v.TgUsername); err != nil {
+				b.logger.Warn("failed to ensure user data consistency", "error", err)
+			}
+		}
+	}
+
 	b.UpdateInvitationMessage(p, nil)
 
-	_, err = b.SendTemporary(c.Chat(), fmt.Sprintf(MsgFmtVoteRecorded, displayName, OptionLabel(poll.OptionKind(optionIndex))), 0)
+	msg := fmt.Sprintf(MsgFmtVotesRecorded, OptionLabel(poll.OptionKind(optionIndex)), len(votes))
+	if len(known) > 0 {
+		msg += "\n" + fmt.Sprintf(MsgFmtKnownPlayers, strings.Join(known, ", "))
+	}
+	if len(unknown) > 0 {
+		msg += "\n" + fmt.Sprintf(MsgFmtNewPlayers, strings.Join(unknown, ", "))
+	}
+	return msg, nil
+}
+
+// isKnownVoter reports whether a resolved vote identifier belongs to a player the bot already knows:
+// one with a real Telegram ID, or a game nickname linked to a Telegram username.
+// Everyone else gets a new synthetic identity.
+func isKnownVoter(identifier string, userID int64, username string) bool {
+	return userID > 0 || (identifier[0] != '@' && username != "")
+}
+
+// askVoteConfirmation keeps the vote aside and asks the admin whether the guessed nicknames were meant.
+// Callback data: <confirmation ID>|<1 to use the suggestions, 0 for new players>
+func (b *Bot) askVoteConfirmation(c tele.Context, v *pendingVote) error {
+	id := strconv.FormatInt(b.pendingVotes.add(v), 10)
+
+	var text, useLabel, newLabel string
+	if len(v.identifiers) == 1 {
+		text = fmt.Sprintf(MsgFmtVoteDidYouMean, v.identifiers[0], v.suggestions[0])
+		useLabel = fmt.Sprintf(MsgFmtVoteUseNickButton, v.suggestions[0])
+		newLabel = MsgVoteNewPlayerButton
+	} else {
+		var guesses []string
+		for i, suggestion := range v.suggestions {
+			if suggestion != "" {
+				guesses = append(guesses, fmt.Sprintf(MsgFmtVoteGuess, v.identifiers[i], suggestion))
+			}
+		}
+		text = MsgVotePlayersNotFound + "\n" + strings.Join(guesses, "\n")
+		useLabel = MsgVoteUseNicksButton
+		newLabel = MsgVoteNewPlayersButton
+	}
+
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data(useLabel, callbackVoteConfirm, id, "1"),
+		markup.Data(newLabel, callbackVoteConfirm, id, "0"),
+	))
+
+	_, err := b.SendWithRetry(c.Chat(), text, markup)
 	return err
 }
+
+// handleVoteConfirm records a vote kept aside by /vote with the nicknames the admin picked
+// and removes the prompt.
+// Callback data: <confirmation ID>|<1 to use the suggestions, 0 for new players>
+func (b *Bot) handleVoteConfirm(c tele.Context) error {
+	config := getClubConfig(c)
+
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return c.Respond()
+	}
+
+	// Check the poll before taking the vote, so the buttons stay usable if it fails
+	p, err := b.GetActivePollForAction(c.Chat().ID)
+	if err != nil {
+		return err
+	}
+
+	v, ok := b.pendingVotes.take(id, c.Chat().ID)
+	if !ok {
+		b.deletePrompt(c)
+		return c.Respond(&tele.CallbackResponse{Text: MsgVoteConfirmExpired, ShowAlert: true})
+	}
+
+	if args[1] == "1" {
+		v.useSuggestions()
+	}
+
+	// The vote is taken, so the buttons are dead either way
+	msg, err := b.recordPendingVote(c, config, p, v)
+	b.deletePrompt(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.SendTemporary(c.Chat(), msg, 0); err != nil {
+		return err
+	}
+	return c.Respond()
+}
+
+// deletePrompt removes the message with the pressed button.
+func (b *Bot) deletePrompt(c tele.Context) {
+	if err := b.bot.Delete(c.Message()); err != nil {
+		b.logger.Warn("failed to delete prompt message", "error", err, "chat_id", c.Chat().ID)
+	}
+}
+
+// pendingVote is a manual vote for one or more players, kept aside while the admin
+// confirms guessed nicknames.
+type pendingVote struct {
+	chatID      int64
+	identifiers []string // as typed by the admin, or the matched nickname
+	suggestions []string // guessed nickname per identifier, "" if the identifier is used as is
+	optionIndex int
+	guests      int // -1 if not specified
+	created     time.Time
+}
+
+// hasSuggestions reports whether any identifier has a guessed nickname to confirm.
+func (v *pendingVote) hasSuggestions() bool {
+	for _, suggestion := range v.suggestions {
+		if suggestion != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// useSuggestions replaces identifiers with their guessed nicknames.
+func (v *pendingVote) useSuggestions() {
+	for i, suggestion := range v.suggestions {
+		if suggestion != "" {
+			v.identifiers[i] = suggestion
+		}
+	}
+}
+
+// pendingVotes keeps votes awaiting confirmation until answered or VoteConfirmationTTL passes.
+type pendingVotes struct {
+	mu     sync.Mutex
+	nextID int64
+	votes  map[int64]*pendingVote
+}
+
+func newPendingVotes() *pendingVotes {
+	return &pendingVotes{
+		votes: make(map[int64]*pendingVote),
+	}
+}
+
+// add stores the vote and returns its confirmation ID, dropping expired votes on the way.
+func (p *pendingVotes) add(v *pendingVote) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for id, pending := range p.votes {
+		if now.Sub(pending.created) > VoteConfirmationTTL {
+			delete(p.votes, id)
+		}
+	}
+
+	p.nextID++
+	v.created = now
+	p.votes[p.nextID] = v
+	return p.nextID
+}
+
+// take removes and returns the vote with the given ID if it belongs to the chat and has not expired.
+func (p *pendingVotes) take(id, chatID int64) (*pendingVote, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v, ok := p.votes[id]
+	if !ok || v.chatID != chatID {
+		return nil, false
+	}
+	delete(p.votes, id)
+	if time.Since(v.created) > VoteConfirmationTTL {
+		return nil, false
+	}
+	return v, true
+}
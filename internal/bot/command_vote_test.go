@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+func TestPendingVotes(t *testing.T) {
+	votes := newPendingVotes()
+
+	id := votes.add(&pendingVote{chatID: -100, identifiers: []string{"Madam Zhu"}, suggestions: []string{"Мадам Жу"}, guests: -1})
+	if _, ok := votes.take(id, -200); ok {
+		t.Error("expected vote from another chat to be rejected")
+	}
+	v, ok := votes.take(id, -100)
+	if !ok || v.suggestions[0] != "Мадам Жу" {
+		t.Fatalf("take() = %+v, %v, want the stored vote", v, ok)
+	}
+	if _, ok := votes.take(id, -100); ok {
+		t.Error("expected vote to be taken only once")
+	}
+
+	expired := votes.add(&pendingVote{chatID: -100})
+	votes.votes[expired].created = time.Now().Add(-VoteConfirmationTTL - time.Minute)
+	if _, ok := votes.take(expired, -100); ok {
+		t.Error("expected expired vote to be rejected")
+	}
+
+	if next := votes.add(&pendingVote{chatID: -100}); next == id || next == expired {
+		t.Errorf("add() reused confirmation ID %d", next)
+	}
+}
+
+func TestPendingVote_UseSuggestions(t *testing.T) {
+	v := &pendingVote{
+		identifiers: []string{"Кот", "Madam Zhu", "@ivan"},
+		suggestions: []string{"", "Мадам Жу", ""},
+	}
+	if !v.hasSuggestions() {
+		t.Fatal("expected suggestions")
+	}
+	v.useSuggestions()
+	if got := strings.Join(v.identifiers, ","); got != "Кот,Мадам Жу,@ivan" {
+		t.Errorf("identifiers = %s", got)
+	}
+	if (&pendingVote{suggestions: []string{"", ""}}).hasSuggestions() {
+		t.Error("expected no suggestions")
+	}
+}
+
+func TestIsBulkVote(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"Кот", "1"}, false},
+		{[]string{"@ivan", "2", "+1"}, false},
+		{[]string{"2", "1"}, false},
+		{[]string{"1", "Кот"}, true},
+		{[]string{"1", "Кот", "Лиса", "@ivan", "Мадам Жу"}, true},
+		{[]string{"7", "Кот"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := isBulkVote(tt.args); got != tt.want {
+				t.Errorf("isBulkVote(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKnownVoter(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		userID     int64
+		username   string
+		want       bool
+	}{
+		{"real user", "@ivan", 123, "ivan", true},
+		{"unknown username", "@ivan", -1, "ivan", false},
+		{"nickname linked to username", "Кот", -1, "kot", true},
+		{"unknown nickname", "Кот", -1, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKnownVoter(tt.identifier, tt.userID, tt.username); got != tt.want {
+				t.Errorf("isKnownVoter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepliedUser(t *testing.T) {
+	player := &tele.User{ID: 42, FirstName: "Ivan", Username: "ivan"}
+
+	tests := []struct {
+		name  string
+		reply *tele.Message
+		want  *tele.User
+	}{
+		{"no reply", nil, nil},
+		{"player", &tele.Message{Sender: player}, player},
+		{"bot", &tele.Message{Sender: &tele.User{ID: 7, IsBot: true}}, nil},
+		{"anonymous admin", &tele.Message{Sender: player, SenderChat: &tele.Chat{ID: -100}}, nil},
+		{"forum topic start", &tele.Message{Sender: player, TopicCreated: &tele.Topic{Name: "Игры"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repliedUser(&tele.Message{ReplyTo: tt.reply}); got != tt.want {
+				t.Errorf("repliedUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsReplyVote(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"2"}, true},
+		{[]string{"1", "+2"}, true},
+		{[]string{"Кот", "1"}, false},
+		{[]string{"1", "Кот"}, false},
+		{[]string{"1", "+1", "Кот"}, false},
+		{[]string{"9"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if got := isReplyVote(tt.args); got != tt.want {
+				t.Errorf("isReplyVote(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
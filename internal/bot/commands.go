@@ -1,6 +1,11 @@
 package bot
 
-// RegisterCommands sets up all bot commands with admin-only middleware
+import (
+	tele "gopkg.in/telebot.v4"
+)
+
+// RegisterCommands sets up all bot commands: admin commands, player commands and
+// inline keyboard callbacks, each group with its own middleware chain.
 // Middleware order matters: HandleErrors must be outermost to catch all errors,
 // RateLimit should run early to drop excessive requests,
 // DeleteCommand should run before AdminOnly so commands are always deleted,
@@ -24,5 +29,59 @@ func (b *Bot) RegisterCommands() {
 	adminGroup.Handle("/call", b.handleCall)
 	adminGroup.Handle("/done", b.handleDone)
 	adminGroup.Handle("/refresh", b.handleRefresh)
+	adminGroup.Handle("/attended", b.handleAttended)
+	adminGroup.Handle("/game", b.handleGame)
+	adminGroup.Handle("/games", b.handleGames)
+	adminGroup.Handle("/judge", b.handleJudge)
+	adminGroup.Handle("/seat", b.handleSeat)
+	adminGroup.Handle("/deal", b.handleDeal)
+	adminGroup.Handle("/price", b.handlePrice)
+	adminGroup.Handle("/paid", b.handlePaid)
+	adminGroup.Handle("/export", b.handleExport)
+	adminGroup.Handle("/import", b.handleImport)
 	adminGroup.Handle("/help", b.handleHelp)
+
+	// Player commands are available to every member of a registered club chat
+	memberGroup := b.bot.Group()
+	memberGroup.Use(b.HandleErrors())
+	memberGroup.Use(b.RateLimit())
+	memberGroup.Use(b.DeleteCommand())
+	memberGroup.Use(b.ResolveClub())
+	memberGroup.Use(b.LogCommand())
+
+	memberGroup.Handle("/stats", b.handleStats)
+	memberGroup.Handle("/top", b.handleTop)
+	memberGroup.Handle("/rating", b.handleRating)
+	memberGroup.Handle("/guest", b.handleGuest)
+
+	// Nickname requests also work in private messages, so the club is resolved by the handler
+	nickRequestGroup := b.bot.Group()
+	nickRequestGroup.Use(b.HandleErrors())
+	nickRequestGroup.Use(b.RateLimit())
+	nickRequestGroup.Use(b.DeleteCommand())
+	nickRequestGroup.Use(b.LogCommand())
+
+	nickRequestGroup.Handle("/mynick", b.handleMyNick)
+
+	// Inline keyboard callbacks use the same chain minus DeleteCommand and LogCommand,
+	// since the keyboard message must stay and button presses are logged by handlers.
+	callbackGroup := b.bot.Group()
+	callbackGroup.Use(b.HandleErrors())
+	callbackGroup.Use(b.RateLimit())
+	callbackGroup.Use(b.ResolveClub())
+	callbackGroup.Use(b.ClubAdminOnly())
+
+	callbackGroup.Handle(&tele.Btn{Unique: callbackAttendToggle}, b.handleAttendToggle)
+	callbackGroup.Handle(&tele.Btn{Unique: callbackAttendDone}, b.handleAttendDone)
+	callbackGroup.Handle(&tele.Btn{Unique: callbackNickApprove}, b.handleNickApprove)
+	callbackGroup.Handle(&tele.Btn{Unique: callbackNickReject}, b.handleNickReject)
+	callbackGroup.Handle(&tele.Btn{Unique: callbackVoteConfirm}, b.handleVoteConfirm)
+
+	// Inline voting buttons are pressed by any chat member
+	voteCallbackGroup := b.bot.Group()
+	voteCallbackGroup.Use(b.HandleErrors())
+	voteCallbackGroup.Use(b.RateLimit())
+	voteCallbackGroup.Use(b.ResolveClub())
+
+	voteCallbackGroup.Handle(&tele.Btn{Unique: callbackVote}, b.handleVoteButton)
 }
@@ -0,0 +1,17 @@
+package bot
+
+import "nuclight.org/consigliere/internal/dashboard"
+
+// DashboardClubs lists the configured clubs with the admins allowed to see them on the dashboard.
+func (b *Bot) DashboardClubs() []dashboard.Club {
+	clubs := make([]dashboard.Club, len(clubConfigs))
+	for i, config := range clubConfigs {
+		clubs[i] = dashboard.Club{Club: config.Club, Name: config.Name, Admins: config.Admins, TableSize: config.TableSize}
+	}
+	return clubs
+}
+
+// Username returns the bot's Telegram username, without @.
+func (b *Bot) Username() string {
+	return b.bot.Me.Username
+}
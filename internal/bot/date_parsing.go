@@ -77,6 +77,15 @@ func isPollDatePassed(eventDate time.Time) bool {
 	return eventDay.Before(today)
 }
 
+// isPollDateInFuture checks if the poll's event date is after today.
+// Returns true if the event has not happened yet.
+func isPollDateInFuture(eventDate time.Time) bool {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	eventDay := time.Date(eventDate.Year(), eventDate.Month(), eventDate.Day(), 0, 0, 0, 0, eventDate.Location())
+	return eventDay.After(today)
+}
+
 // parseEventDate parses the event date from command arguments.
 // Supports:
 // - No arguments: nearest Monday or Saturday
@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// NoJudgeMarker is used in place of the judge when nobody hosted the game
+const NoJudgeMarker = "-"
+
+// GameArgs holds parsed /game command arguments.
+type GameArgs struct {
+	Winner  poll.Side
+	Judge   string          // judge identifier (@username or game nick), empty if none
+	Players []GamePlayerArg // players in seat order, seat = index + 1
+}
+
+// GamePlayerArg is a single player token of the /game command.
+type GamePlayerArg struct {
+	Identifier string // @username or game nick
+	Role       poll.Role
+}
+
+// ParseGameArgs parses /game command arguments with shell-style quoting.
+// Format: <winner> <judge|-> <player1> <player2> ...
+// A player's role is given as a suffix after a slash: Кот/м, @ivan/ш, "Мадам Жу"/д.
+// Players without a suffix are civilians.
+func ParseGameArgs(input string) (*GameArgs, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) < 3 {
+		return nil, errors.New("not enough arguments")
+	}
+
+	winner, err := parseSide(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GameArgs{Winner: winner}
+	if tokens[1] != NoJudgeMarker {
+		result.Judge = tokens[1]
+	}
+
+	for _, token := range tokens[2:] {
+		player := GamePlayerArg{Identifier: token, Role: poll.RoleCivilian}
+		if i := strings.LastIndex(token, "/"); i > 0 {
+			role, err := parseRole(token[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			player.Identifier = token[:i]
+			player.Role = role
+		}
+		result.Players = append(result.Players, player)
+	}
+
+	return result, nil
+}
+
+// parseSide parses the winning side.
+// Valid values: город/мирные/красные/city, мафия/черные/чёрные/mafia (case-insensitive)
+func parseSide(s string) (poll.Side, error) {
+	switch strings.ToLower(s) {
+	case "город", "мирные", "красные", "city":
+		return poll.SideCity, nil
+	case "мафия", "черные", "чёрные", "mafia":
+		return poll.SideMafia, nil
+	default:
+		return "", errors.New("invalid winner: use город/мафия")
+	}
+}
+
+// parseRole parses a player role suffix.
+// Valid values: м/m (mafia), д/d (don), ш/s (sheriff), ч/c (civilian) or full names (case-insensitive)
+func parseRole(s string) (poll.Role, error) {
+	switch strings.ToLower(s) {
+	case "ч", "c", "мирный", "civilian":
+		return poll.RoleCivilian, nil
+	case "м", "m", "мафия", "mafia":
+		return poll.RoleMafia, nil
+	case "д", "d", "дон", "don":
+		return poll.RoleDon, nil
+	case "ш", "s", "шериф", "sheriff":
+		return poll.RoleSheriff, nil
+	default:
+		return "", errors.New("invalid role: use м/д/ш")
+	}
+}
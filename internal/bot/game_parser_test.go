@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"testing"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestParseGameArgs(t *testing.T) {
+	args, err := ParseGameArgs(`мафия @judge Кот Лиса/д "Мадам Жу"/м @ivan/Ш Енот`)
+	if err != nil {
+		t.Fatalf("ParseGameArgs failed: %v", err)
+	}
+
+	if args.Winner != poll.SideMafia {
+		t.Errorf("Winner = %q, want mafia", args.Winner)
+	}
+	if args.Judge != "@judge" {
+		t.Errorf("Judge = %q, want @judge", args.Judge)
+	}
+
+	want := []GamePlayerArg{
+		{"Кот", poll.RoleCivilian},
+		{"Лиса", poll.RoleDon},
+		{"Мадам Жу", poll.RoleMafia},
+		{"@ivan", poll.RoleSheriff},
+		{"Енот", poll.RoleCivilian},
+	}
+	if len(args.Players) != len(want) {
+		t.Fatalf("got %d players, want %d", len(args.Players), len(want))
+	}
+	for i, p := range args.Players {
+		if p != want[i] {
+			t.Errorf("player %d = %+v, want %+v", i+1, p, want[i])
+		}
+	}
+}
+
+func TestParseGameArgs_NoJudge(t *testing.T) {
+	args, err := ParseGameArgs("город - Кот Лиса/м")
+	if err != nil {
+		t.Fatalf("ParseGameArgs failed: %v", err)
+	}
+	if args.Winner != poll.SideCity || args.Judge != "" {
+		t.Errorf("unexpected args: %+v", args)
+	}
+}
+
+func TestParseGameArgs_Errors(t *testing.T) {
+	inputs := []string{
+		"",
+		"город @judge",
+		"ничья @judge Кот Лиса",
+		"город @judge Кот Лиса/х",
+		`город @judge "Кот`,
+	}
+	for _, input := range inputs {
+		if _, err := ParseGameArgs(input); err == nil {
+			t.Errorf("ParseGameArgs(%q) expected error, got nil", input)
+		}
+	}
+}
@@ -2,14 +2,18 @@ package bot
 
 import (
 	"fmt"
+	"strings"
 
 	tele "gopkg.in/telebot.v4"
+	"gopkg.in/telebot.v4/react"
 
+	"nuclight.org/consigliere/internal/metrics"
 	"nuclight.org/consigliere/internal/poll"
 )
 
 func (b *Bot) RegisterHandlers() {
 	b.bot.Handle(tele.OnPollAnswer, b.handlePollAnswer)
+	b.bot.Handle(tele.OnText, b.handleTextVote)
 }
 
 func (b *Bot) handlePollAnswer(c tele.Context) error {
@@ -34,12 +38,18 @@ func (b *Bot) handlePollAnswer(c tele.Context) error {
 		optionIndex = answer.Options[0]
 	}
 
-	// Record vote
+	return b.recordUserVote(p, answer.Sender, optionIndex)
+}
+
+// recordUserVote records a vote cast by the user themselves (native poll answer or
+// inline voting button), keeps their identity data consistent and refreshes the invitation.
+// optionIndex is -1 if the vote was retracted.
+func (b *Bot) recordUserVote(p *poll.Poll, user *tele.User, optionIndex int) error {
 	v := &poll.Vote{
 		PollID:        p.ID,
-		TgUserID:      answer.Sender.ID,
-		TgUsername:    answer.Sender.Username,
-		TgFirstName:   answer.Sender.FirstName,
+		TgUserID:      user.ID,
+		TgUsername:    user.Username,
+		TgFirstName:   user.FirstName,
 		TgOptionIndex: optionIndex,
 	}
 
@@ -49,8 +59,8 @@ func (b *Bot) handlePollAnswer(c tele.Context) error {
 	}
 
 	b.logger.Info("vote recorded",
-		"user_id", answer.Sender.ID,
-		"username", answer.Sender.Username,
+		"user_id", user.ID,
+		"username", user.Username,
 		"poll_id", p.ID,
 		"option", optionLabel,
 	)
@@ -58,9 +68,10 @@ func (b *Bot) handlePollAnswer(c tele.Context) error {
 	if err := b.pollService.RecordVote(v); err != nil {
 		return fmt.Errorf("record vote: %w", err)
 	}
+	metrics.Votes.WithLabelValues(metrics.VoteSourcePlayer).Inc()
 
 	// Ensure data consistency: update nicknames and consolidate synthetic votes
-	if err := b.pollService.EnsureUserDataConsistency(p.TgChatID, answer.Sender.ID, answer.Sender.Username); err != nil {
+	if err := b.pollService.EnsureUserDataConsistency(p.TgChatID, user.ID, user.Username); err != nil {
 		b.logger.Warn("failed to ensure user data consistency", "error", err)
 	}
 
@@ -69,3 +80,48 @@ func (b *Bot) handlePollAnswer(c tele.Context) error {
 
 	return nil
 }
+
+// handleTextVote records plain chat messages like "+", "+20", "-" or "буду в 21" as votes
+// in clubs with FeatureFlags.TextVoting, and confirms with a reaction on the message.
+// Anything ParseTextVote does not recognize, messages outside an active poll and replies
+// to other messages than the poll's (a "+" agreeing with someone) are ignored.
+func (b *Bot) handleTextVote(c tele.Context) error {
+	msg := c.Message()
+	if msg == nil || msg.Sender == nil || msg.Sender.IsBot || strings.HasPrefix(msg.Text, "/") {
+		return nil
+	}
+
+	config, ok := chatRegistry[c.Chat().ID]
+	if !ok || !config.FeatureFlags.TextVoting {
+		return nil
+	}
+
+	option, ok := ParseTextVote(msg.Text)
+	if !ok {
+		return nil
+	}
+
+	p, err := b.pollService.GetActivePoll(c.Chat().ID)
+	if err != nil || isPollDatePassed(p.EventDate) || !isPollReply(msg, p) {
+		return nil
+	}
+
+	if err := b.recordUserVote(p, msg.Sender, int(option)); err != nil {
+		return err
+	}
+
+	if err := b.bot.React(c.Chat(), msg, react.React(react.ThumbUp)); err != nil {
+		b.logger.Warn("failed to react to text vote", "error", err, "chat_id", c.Chat().ID, "message_id", msg.ID)
+	}
+	return nil
+}
+
+// isPollReply reports whether a message may be a vote in the poll: it replies to nothing
+// (the forum topic it was posted in doesn't count) or to the poll or its invitation.
+func isPollReply(msg *tele.Message, p *poll.Poll) bool {
+	reply := msg.ReplyTo
+	if reply == nil || msg.TopicMessage && reply.ID == msg.ThreadID {
+		return true
+	}
+	return reply.ID == p.TgInvitationMessageID || reply.ID == p.TgMessageID
+}
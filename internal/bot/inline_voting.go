@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Callback button identifier for inline voting on the invitation message
+const callbackVote = "vote"
+
+// voteKeyboard builds the inline voting keyboard of an invitation message:
+// attendance times in the first row, "decide later" and "not coming" in the second.
+// Callback data: <poll ID>|<option index>
+func voteKeyboard(p *poll.Poll) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	pollID := strconv.FormatInt(p.ID, 10)
+	btn := func(o poll.OptionKind) tele.Btn {
+		return markup.Data(optionButtonLabels[o], callbackVote, pollID, strconv.Itoa(int(o)))
+	}
+	markup.Inline(
+		markup.Row(btn(poll.OptionComeAt19), btn(poll.OptionComeAt20), btn(poll.OptionComeAt21OrLater)),
+		markup.Row(btn(poll.OptionDecideLater), btn(poll.OptionNotComing)),
+	)
+	return markup
+}
+
+// handleVoteButton records a vote from the inline keyboard of the invitation message.
+// The invitation is re-rendered with the new vote; the voter gets a short confirmation.
+// Callback data: <poll ID>|<option index>
+func (b *Bot) handleVoteButton(c tele.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return c.Respond()
+	}
+	optionIndex, err := strconv.Atoi(args[1])
+	if err != nil || optionIndex < int(poll.OptionComeAt19) || optionIndex > int(poll.OptionNotComing) {
+		return c.Respond()
+	}
+
+	p, err := b.pollFromCallback(c, args[0])
+	if err != nil || p == nil {
+		return err
+	}
+
+	if !p.IsActive || isPollDatePassed(p.EventDate) {
+		return c.Respond(&tele.CallbackResponse{Text: MsgVotingClosed, ShowAlert: true})
+	}
+
+	if err := b.recordUserVote(p, c.Sender(), optionIndex); err != nil {
+		return WrapUserError(MsgFailedRecordVote, err)
+	}
+
+	return c.Respond(&tele.CallbackResponse{Text: fmt.Sprintf(MsgFmtYourVote, OptionLabel(poll.OptionKind(optionIndex)))})
+}
@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"testing"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestVoteKeyboard(t *testing.T) {
+	markup := voteKeyboard(&poll.Poll{ID: 42})
+
+	if len(markup.InlineKeyboard) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(markup.InlineKeyboard))
+	}
+	if len(markup.InlineKeyboard[0]) != 3 || len(markup.InlineKeyboard[1]) != 2 {
+		t.Fatalf("expected 3+2 buttons, got %d+%d", len(markup.InlineKeyboard[0]), len(markup.InlineKeyboard[1]))
+	}
+
+	first := markup.InlineKeyboard[0][0]
+	if first.Text != "19:00" || first.Unique != callbackVote || first.Data != "42|0" {
+		t.Errorf("unexpected first button: %q %q", first.Text, first.Data)
+	}
+	last := markup.InlineKeyboard[1][1]
+	if last.Text != "Не приду" || last.Data != "42|4" {
+		t.Errorf("unexpected last button: %q %q", last.Text, last.Data)
+	}
+}
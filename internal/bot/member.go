@@ -1,6 +1,10 @@
 package bot
 
-import "nuclight.org/consigliere/internal/poll"
+import (
+	"strconv"
+
+	"nuclight.org/consigliere/internal/poll"
+)
 
 // Member represents a user to be mentioned in templates.
 // At least one field should be set.
@@ -9,6 +13,7 @@ type Member struct {
 	TgName     string // Telegram first name (optional)
 	TgUsername string // Telegram username without @ (optional)
 	Nickname   string // Custom nickname (optional)
+	Guests     int    // Guests the member brings (shown as "+N" in lists)
 }
 
 // DisplayName returns the best available display name for the member.
@@ -35,12 +40,22 @@ func (m Member) MentionName() string {
 	return m.TgName
 }
 
+// GuestSuffix returns the guest marker shown after the member's name (e.g. " +1"),
+// or empty string if the member brings no guests.
+func (m Member) GuestSuffix() string {
+	if m.Guests <= 0 {
+		return ""
+	}
+	return " +" + strconv.Itoa(m.Guests)
+}
+
 // MemberFromVote creates a Member from a Vote.
 func MemberFromVote(v *poll.Vote) Member {
 	return Member{
 		TgID:       v.TgUserID,
 		TgName:     v.TgFirstName,
 		TgUsername: v.TgUsername,
+		Guests:     v.Guests,
 	}
 }
 
@@ -3,26 +3,73 @@ package bot
 // Permission messages
 const (
 	MsgChatNotPermitted = "Этот чат не зарегистрирован для использования бота"
+	MsgAdminsOnly       = "Это доступно только администраторам клуба"
 )
 
 // User error messages (user mistakes, shown directly)
 const (
-	MsgInvalidDateFormat  = "Неверный формат даты. Используйте название дня (например, понедельник, сб) или ГГГГ-ММ-ДД"
-	MsgPollAlreadyExists  = "В этом чате уже есть активный опрос. Сначала отмените его командой /cancel"
-	MsgNoActivePoll       = "Активный опрос не найден"
-	MsgNoPoll             = "Опрос не найден"
-	MsgNoCancelledPoll    = "Нет отменённых опросов"
-	MsgPollDatePassed     = "Нельзя восстановить опрос для прошедшей даты"
-	MsgPollMessageMissing = "Сообщение с опросом не найдено"
-	MsgInvalidUsername    = "Неверное имя пользователя"
-	MsgVoteUsage          = "Использование: /vote @имя <опция 1-5>\nОпции: 1=19:00, 2=20:00, 3=21:00+, 4=решу позже, 5=не приду"
-	MsgInvalidVoteOption  = "Неверная опция. Используйте 1-5:\n1=19:00, 2=20:00, 3=21:00+, 4=решу позже, 5=не приду"
-	MsgNoUndecidedVoters  = "Нет участников, которые ещё не определились"
-	MsgNotEnoughPlayers   = "Недостаточно игроков. Нужно минимум 11 человек на 19:00 и 20:00"
-	MsgInvalidStartTime   = "Неверный формат времени. Используйте: /done 19, /done 20:00, /done 21:30"
-	MsgNickUsage     = "Использование: /nick @username игровой_ник [пол]\nНик в кавычках если с пробелами: /nick @user \"Мадам Жу\"\nПол (опционально): м/ж/m/f/д"
-	MsgNickDuplicate = "Такая связка уже существует"
-	MsgInvalidGender = "Неверный пол. Используйте: м/ж/m/f/д"
+	MsgInvalidDateFormat      = "Неверный формат даты. Используйте название дня (например, понедельник, сб) или ГГГГ-ММ-ДД"
+	MsgPollAlreadyExists      = "В этом чате уже есть активный опрос. Сначала отмените его командой /cancel"
+	MsgNoActivePoll           = "Активный опрос не найден"
+	MsgNoPoll                 = "Опрос не найден"
+	MsgNoCancelledPoll        = "Нет отменённых опросов"
+	MsgPollDatePassed         = "Нельзя восстановить опрос для прошедшей даты"
+	MsgPollMessageMissing     = "Сообщение с опросом не найдено"
+	MsgInvalidUsername        = "Неверное имя пользователя"
+	MsgVoteUsage              = "Использование: /vote @имя <опция 1-5> [+гости]\nНесколько игроков: /vote <опция 1-5> @имя ник \"ник с пробелами\"\nОтветом на сообщение игрока: /vote <опция 1-5> [+гости]\nОпции: 1=19:00, 2=20:00, 3=21:00+, 4=решу позже, 5=не приду\nГости: +1, +2, … или +0, чтобы убрать"
+	MsgInvalidVoteOption      = "Неверная опция. Используйте 1-5:\n1=19:00, 2=20:00, 3=21:00+, 4=решу позже, 5=не приду"
+	MsgNoUndecidedVoters      = "Нет участников, которые ещё не определились"
+	MsgNotEnoughPlayers       = "Недостаточно игроков. Нужно минимум 11 человек на 19:00 и 20:00"
+	MsgInvalidStartTime       = "Неверный формат времени. Используйте: /done 19, /done 20:00, /done 21:30"
+	MsgNickUsage              = "Использование: /nick @username игровой_ник [пол]\nНик в кавычках если с пробелами: /nick @user \"Мадам Жу\"\nПол (опционально): м/ж/m/f/д\nСписок: /nick list\nУдалить: /nick rm <ник>\nПереименовать: /nick rename <старый> <новый>\nПол: /nick gender <ник> <м|ж|->"
+	MsgNickDuplicate          = "Такая связка уже существует"
+	MsgNickTaken              = "Такой ник уже занят"
+	MsgNickNotFound           = "Ник не найден"
+	MsgNickShared             = "Это общий ник всех клубов, изменить его может только администратор всех клубов"
+	MsgNickHasHistory         = "У игрока нет другого ника или @username, его история осталась бы без владельца. Переименуйте ник вместо удаления"
+	MsgNoNicknames            = "Ников пока нет"
+	MsgInvalidGender          = "Неверный пол. Используйте: м/ж/m/f/д"
+	MsgEventCancelled         = "Игровой вечер был отменён"
+	MsgEventNotHeldYet        = "Игровой вечер ещё не состоялся"
+	MsgNoAttendingVoters      = "Никто не голосовал за участие"
+	MsgNotAttendingVoter      = "Этот игрок не голосовал за участие"
+	MsgStatsUsage             = "Использование: /stats [@username|ник] [период]\nПериод: месяц, неделя, сезон, год, всё, ГГГГ-ММ или ГГГГ"
+	MsgInvalidStatsPeriod     = "Неверный период. Используйте: месяц, неделя, сезон, год, всё, ГГГГ-ММ или ГГГГ"
+	MsgGameUsage              = "Использование: /game <город|мафия> <судья|-> <игрок1> ... <игрокN>\nИгроки по порядку мест, роль через слэш: /м мафия, /д дон, /ш шериф\nПример: /game город @judge Кот Лиса/д \"Мадам Жу\"/м Енот/ш ...\nУдалить игру: /game rm <номер>"
+	MsgInvalidGamePlayerCount = "Неверное число игроков: нужно от 6 до 12"
+	MsgDuplicateGamePlayer    = "Игрок указан дважды"
+	MsgInvalidGameRoles       = "Неверный состав ролей: мафии должно быть меньше, чем мирных, не больше одного дона и одного шерифа"
+	MsgJudgeIsPlayer          = "Судья не может быть игроком"
+	MsgGameNotFound           = "Игра не найдена"
+	MsgNoGames                = "Игр пока нет"
+	MsgNotCollectedYet        = "Сначала объявите набор командой /done"
+	MsgNotEnoughPlayersToSeat = "Недостаточно игроков для рассадки: нужно минимум 6"
+	MsgNoSeating              = "Сначала рассадите игроков командой /seat"
+	MsgJudgeUsage             = "Использование: /judge [@username|ник|auto|-]\nБез аргумента — предложить ведущего, auto — назначить предложенного, - — снять ведущего"
+	MsgEmptyJudgePool         = "Список ведущих клуба пуст"
+	MsgNeverHosted            = "ещё не вёл"
+	MsgJudgeCleared           = "Ведущий снят"
+	MsgJudgeNotReachable      = "Не удалось отправить список ролей судье. Судья должен сначала написать боту /start в личные сообщения"
+	MsgPriceUsage             = "Использование: /price <стоимость в лари>\n/price 0 — не показывать стоимость"
+	MsgPaidUsage              = "Использование: /paid <@username|ник> ...\nОтменить оплату: /paid rm <@username|ник> ..."
+	MsgNoPrice                = "Стоимость вечера не указана. Укажите её командой /price"
+	MsgGuestUsage             = "Использование: /guest [число гостей]\n/guest 0 — без гостей"
+	MsgInvalidGuestCount      = "Неверное число гостей: от 0 до 5"
+	MsgGuestHostNotAttending  = "Гостей можно добавить только тому, кто идёт на игру"
+	MsgVotingClosed           = "Голосование закрыто"
+	MsgMyNickUsage            = "Использование: /mynick игровой_ник [пол]\nНик в кавычках если с пробелами: /mynick \"Мадам Жу\" ж\nПол (опционально): м/ж/m/f/д"
+	MsgMyNickNoClub           = "Не удалось определить клуб. Отправьте /mynick в чате клуба"
+	MsgNickRequestSent        = "Запрос отправлен администраторам клуба"
+	MsgMyNickExists           = "У вас уже есть ник в клубе. Сменить его могут администраторы"
+	MsgNickRequestPending     = "Ваш прошлый запрос ника ещё не рассмотрен"
+	MsgNickRequestDecided     = "Запрос уже рассмотрен"
+	MsgVoteConfirmExpired     = "Вопрос устарел, повторите /vote"
+	MsgVotePlayersNotFound    = "Игроки не найдены:"
+	MsgExportUsage            = "Использование: /export [с ГГГГ-ММ-ДД] [по ГГГГ-ММ-ДД] [csv|json]"
+	MsgExportNotReachable     = "Не удалось отправить выгрузку. Сначала напишите боту /start в личные сообщения"
+	MsgExportSent             = "Выгрузка отправлена в личные сообщения"
+	MsgImportUsage            = "Ответьте командой /import на CSV-файл с никами.\nСтолбцы: @username или ID, ник, [пол], [общий]"
+	MsgImportTooLarge         = "Файл слишком большой для импорта"
 )
 
 // System error messages (internal errors, hide details from user)
@@ -54,12 +101,82 @@ const (
 	MsgFailedSendCollected      = "Не удалось отправить сообщение о наборе"
 	MsgFailedSaveNick           = "Не удалось сохранить ник"
 	MsgFailedRefresh            = "Не удалось обновить сообщения"
+	MsgFailedGetAttendance      = "Не удалось получить список присутствующих"
+	MsgFailedRenderAttendance   = "Не удалось сформировать список присутствующих"
+	MsgFailedSendAttendance     = "Не удалось отправить список присутствующих"
+	MsgFailedSaveAttendance     = "Не удалось сохранить отметку о присутствии"
+	MsgFailedGetStats           = "Не удалось получить статистику"
+	MsgFailedRenderStats        = "Не удалось сформировать статистику"
+	MsgFailedSaveGame           = "Не удалось сохранить игру"
+	MsgFailedGetGames           = "Не удалось получить игры"
+	MsgFailedRenderGame         = "Не удалось сформировать результаты игры"
+	MsgFailedSendGame           = "Не удалось отправить результаты игры"
+	MsgFailedDeleteGame         = "Не удалось удалить игру"
+	MsgFailedGetRating          = "Не удалось получить рейтинг"
+	MsgFailedRenderRating       = "Не удалось сформировать рейтинг"
+	MsgFailedSeat               = "Не удалось рассадить игроков"
+	MsgFailedRenderSeating      = "Не удалось сформировать рассадку"
+	MsgFailedSendSeating        = "Не удалось отправить рассадку"
+	MsgFailedDealRoles          = "Не удалось раздать роли"
+	MsgFailedSaveJudge          = "Не удалось назначить ведущего"
+	MsgFailedSuggestJudge       = "Не удалось подобрать ведущего"
+	MsgFailedSavePrice          = "Не удалось сохранить стоимость"
+	MsgFailedSavePayment        = "Не удалось записать оплату"
+	MsgFailedSaveGuests         = "Не удалось сохранить гостей"
+	MsgFailedSaveNickRequest    = "Не удалось сохранить запрос ника"
+	MsgFailedSendNickRequest    = "Не удалось отправить запрос ника"
+	MsgFailedGetNicks           = "Не удалось получить список ников"
+	MsgFailedRenderNicks        = "Не удалось сформировать список ников"
+	MsgFailedExport             = "Не удалось выгрузить данные"
+	MsgFailedImport             = "Не удалось импортировать ники"
+)
+
+// Inline button labels
+const (
+	MsgAttendanceDoneButton = "Готово"
+	MsgNickApproveButton    = "✅ Одобрить"
+	MsgNickRejectButton     = "❌ Отклонить"
+	MsgVoteNewPlayerButton  = "➕ Новый игрок"
+	MsgVoteUseNicksButton   = "✅ Исправить"
+	MsgVoteNewPlayersButton = "➕ Как написано"
 )
 
 // Format strings for dynamic messages
 const (
-	MsgFmtEventCancelled   = "⚠️ Игра %s отменена"
-	MsgFmtVoteRecorded     = "Записан голос за %s: %s"
-	MsgFmtNickCreated      = "Ник сохранён: %s → %s"
-	MsgFmtNickCreatedByID  = "Ник сохранён: ID %d → %s"
+	MsgFmtEventCancelled    = "⚠️ Игра %s отменена"
+	MsgFmtVoteRecorded      = "Записан голос за %s: %s"
+	MsgFmtNickCreated       = "Ник сохранён: %s → %s"
+	MsgFmtNickCreatedByID   = "Ник сохранён: ID %d → %s"
+	MsgFmtGameDeleted       = "Игра №%d удалена"
+	MsgFmtRolesDealt        = "Роли разосланы: %d из %d"
+	MsgFmtRolesNotDelivered = "Не получили роль (нужно написать боту /start): %s"
+	MsgFmtJudgeAssigned     = "Ведущий: %s"
+	MsgFmtJudgeSuggestion   = "Предлагаемый ведущий: %s (последний раз: %s)\nНазначить: /judge auto"
+	MsgFmtPriceSet          = "Стоимость вечера: %d₾"
+	MsgFmtPaymentsRecorded  = "Оплата %d₾ записана: %s"
+	MsgFmtPaymentsRemoved   = "Оплата отменена: %s"
+	MsgFmtNoPayment         = "Не было оплаты: %s"
+	MsgFmtNotVoted          = "Не голосовали: %s"
+	MsgFmtGuestsSet         = "Гости %s: +%d"
+	MsgFmtGuestsRemoved     = "Гости %s убраны"
+	MsgFmtGuestSeat         = "%s, гость %d"
+	MsgFmtYourVote          = "Ваш голос: %s"
+	MsgFmtNickRequest       = "Запрос ника: %s → %s"
+	MsgFmtNickApproved      = "✅ Ник одобрен: %s → %s"
+	MsgFmtNickRejected      = "❌ Ник отклонён: %s → %s"
+	MsgFmtNickTaken         = "❌ Ник уже занят: %s → %s"
+	MsgFmtNickHasNickname   = "❌ У игрока уже есть ник: %s → %s"
+	MsgFmtNickDeleted       = "Ник удалён: %s"
+	MsgFmtNickRenamed       = "Ник переименован: %s → %s"
+	MsgFmtNickGenderSet     = "Пол обновлён: %s"
+	MsgFmtVoteDidYouMean    = "Игрок «%s» не найден. Может быть, %s?"
+	MsgFmtVoteUseNickButton = "✅ %s"
+	MsgFmtVoteGuess         = "«%s» — может быть, %s?"
+	MsgFmtVotesRecorded     = "Записаны голоса за %s: %d"
+	MsgFmtKnownPlayers      = "Известные игроки: %s"
+	MsgFmtNewPlayers        = "Новые игроки: %s"
+	MsgFmtExportCaption     = "Выгрузка %s: опросов %d, ников %d"
+	MsgFmtNicksImported     = "Импорт ников: создано %d, уже заняты %d, с ошибками %d"
+	MsgFmtImportDuplicates  = "Уже заняты: %s"
+	MsgFmtImportInvalid     = "Ошибки в строках: %s"
 )
@@ -57,22 +57,34 @@ type NickArgs struct {
 	TgUsername *string // Telegram username without @ (set if identifier starts with @)
 	Nickname   string  // Game nickname
 	Gender     Gender  // Gender (optional)
+	Shared     bool    // Nickname is visible in every club (trailing "shared"/"общий")
 }
 
 // ParseNickArgs parses /nick command arguments with shell-style quoting.
-// Supports: /nick @user nick, /nick @user "nick with spaces", /nick @user nick m
+// Supports: /nick @user nick, /nick @user "nick with spaces", /nick @user nick m, /nick @user nick m shared
 func ParseNickArgs(input string) (*NickArgs, error) {
 	tokens, err := tokenize(input)
 	if err != nil {
 		return nil, err
 	}
+	return parseNickTokens(tokens)
+}
+
+// parseNickTokens parses already split /nick arguments: identifier, nickname, optional gender
+// and optional shared marker.
+func parseNickTokens(tokens []string) (*NickArgs, error) {
+	result := &NickArgs{}
+
+	// Optional shared namespace marker (last token, after the nickname)
+	if len(tokens) > 2 && isSharedMarker(tokens[len(tokens)-1]) {
+		result.Shared = true
+		tokens = tokens[:len(tokens)-1]
+	}
 
 	if len(tokens) < 2 {
 		return nil, errors.New("not enough arguments")
 	}
 
-	result := &NickArgs{}
-
 	// Parse identifier (first token)
 	identifier := tokens[0]
 	if strings.HasPrefix(identifier, "@") {
@@ -110,6 +122,16 @@ func ParseNickArgs(input string) (*NickArgs, error) {
 	return result, nil
 }
 
+// isSharedMarker reports whether a token puts the nickname into the shared namespace of all clubs.
+func isSharedMarker(s string) bool {
+	switch strings.ToLower(s) {
+	case "shared", "общий":
+		return true
+	default:
+		return false
+	}
+}
+
 // parseGender parses a gender marker.
 // Valid values: m, f, м, д, ж (case-insensitive)
 func parseGender(s string) (Gender, error) {
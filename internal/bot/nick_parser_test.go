@@ -175,6 +175,35 @@ func TestParseNickArgs(t *testing.T) {
 			wantErr:  true,
 			errMatch: "invalid gender",
 		},
+		{
+			name:  "shared namespace with gender",
+			input: "@user1 Кот м общий",
+			want: &NickArgs{
+				TgUsername: strPtr("user1"),
+				Nickname:   "Кот",
+				Gender:     GenderMale,
+				Shared:     true,
+			},
+		},
+		{
+			name:  "shared namespace without gender",
+			input: "@user1 Кот shared",
+			want: &NickArgs{
+				TgUsername: strPtr("user1"),
+				Nickname:   "Кот",
+				Gender:     GenderNotSet,
+				Shared:     true,
+			},
+		},
+		{
+			name:  "nickname equal to the shared marker",
+			input: "@user1 Общий",
+			want: &NickArgs{
+				TgUsername: strPtr("user1"),
+				Nickname:   "Общий",
+				Gender:     GenderNotSet,
+			},
+		},
 		{
 			name:     "too many arguments",
 			input:    "@user1 Nick m extra",
@@ -312,5 +341,5 @@ func nickArgsEqual(a, b *NickArgs) bool {
 		return false
 	}
 
-	return a.Nickname == b.Nickname && a.Gender == b.Gender
+	return a.Nickname == b.Nickname && a.Gender == b.Gender && a.Shared == b.Shared
 }
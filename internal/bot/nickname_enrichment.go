@@ -4,11 +4,11 @@ import (
 	"nuclight.org/consigliere/internal/poll"
 )
 
-// enrichVotesWithCache looks up game nicknames and modifies votes in place.
+// enrichVotesWithCache looks up the club's game nicknames and modifies votes in place.
 // When a game nickname is found, TgFirstName is set to the display nick (with gender prefix)
 // and TgUsername is cleared so Vote.DisplayName() returns the nickname.
 // If cache is nil, creates one internally (less efficient for multiple call sites).
-func (b *Bot) enrichVotesWithCache(votes []*poll.Vote, cache *poll.NicknameCache) {
+func (b *Bot) enrichVotesWithCache(club poll.Club, votes []*poll.Vote, cache *poll.NicknameCache) {
 	if len(votes) == 0 {
 		return
 	}
@@ -16,7 +16,7 @@ func (b *Bot) enrichVotesWithCache(votes []*poll.Vote, cache *poll.NicknameCache
 	// Create cache if not provided
 	if cache == nil {
 		var err error
-		cache, err = b.pollService.NewNicknameCacheFromVotes(votes)
+		cache, err = b.pollService.NewNicknameCacheFromVotes(club, votes)
 		if err != nil {
 			b.logger.Warn("failed to batch fetch nicknames for enrichment", "error", err)
 			return
@@ -35,16 +35,16 @@ func (b *Bot) enrichVotesWithCache(votes []*poll.Vote, cache *poll.NicknameCache
 	}
 }
 
-// membersFromVotesWithCache creates Members from Votes with nicknames looked up.
+// membersFromVotesWithCache creates Members from Votes with the club's nicknames looked up.
 // Preserves both TgUsername and Nickname for display.
 // If cache is nil, creates one internally (less efficient for multiple call sites).
-func (b *Bot) membersFromVotesWithCache(votes []*poll.Vote, cache *poll.NicknameCache) []Member {
+func (b *Bot) membersFromVotesWithCache(club poll.Club, votes []*poll.Vote, cache *poll.NicknameCache) []Member {
 	members := make([]Member, 0, len(votes))
 
 	// Create cache if not provided
 	if cache == nil {
 		var err error
-		cache, err = b.pollService.NewNicknameCacheFromVotes(votes)
+		cache, err = b.pollService.NewNicknameCacheFromVotes(club, votes)
 		if err != nil {
 			b.logger.Warn("failed to batch fetch nicknames", "error", err)
 			// Fallback: return members without nicknames
@@ -53,6 +53,7 @@ func (b *Bot) membersFromVotesWithCache(votes []*poll.Vote, cache *poll.Nickname
 					TgID:       v.TgUserID,
 					TgName:     v.TgFirstName,
 					TgUsername: v.TgUsername,
+					Guests:     v.Guests,
 				})
 			}
 			return members
@@ -65,6 +66,7 @@ func (b *Bot) membersFromVotesWithCache(votes []*poll.Vote, cache *poll.Nickname
 			TgName:     v.TgFirstName,
 			TgUsername: v.TgUsername,
 			Nickname:   cache.GetDisplayNick(v.TgUserID, v.TgUsername),
+			Guests:     v.Guests,
 		}
 		members = append(members, m)
 	}
@@ -103,19 +105,23 @@ func (b *Bot) voteToResultsVoterWithCache(v *poll.Vote, cache *poll.NicknameCach
 		TgID:       v.TgUserID,
 		TgUsername: v.TgUsername,
 		TgName:     v.TgFirstName,
+		ActorID:    v.ActorUserID,
 	}
 
-	// Get nickname from cache (nil-safe)
+	// Get nicknames from cache (nil-safe)
 	if cache != nil {
 		voter.Nickname = cache.Get(v.TgUserID, v.TgUsername)
+		if v.ActorUserID > 0 {
+			voter.ActorNickname = cache.Get(v.ActorUserID, "")
+		}
 	}
 
 	return voter
 }
 
-// buildNicknameCacheFromVotes creates a single cache from multiple vote slices.
+// buildNicknameCacheFromVotes creates a single cache of the club's nicknames from multiple vote slices.
 // This is more efficient than creating separate caches for each slice.
-func (b *Bot) buildNicknameCacheFromVotes(voteLists ...[]*poll.Vote) (*poll.NicknameCache, error) {
+func (b *Bot) buildNicknameCacheFromVotes(club poll.Club, voteLists ...[]*poll.Vote) (*poll.NicknameCache, error) {
 	// Count total votes for pre-allocation
 	total := 0
 	for _, votes := range voteLists {
@@ -128,7 +134,7 @@ func (b *Bot) buildNicknameCacheFromVotes(voteLists ...[]*poll.Vote) (*poll.Nick
 		allVotes = append(allVotes, votes...)
 	}
 
-	return b.pollService.NewNicknameCacheFromVotes(allVotes)
+	return b.pollService.NewNicknameCacheFromVotes(club, allVotes)
 }
 
 // Backwards-compatible wrappers that create cache internally
@@ -136,12 +142,12 @@ func (b *Bot) buildNicknameCacheFromVotes(voteLists ...[]*poll.Vote) (*poll.Nick
 // enrichVotesWithNicknames looks up game nicknames for votes and sets them.
 // Note: This modifies votes in place and clears username for display purposes.
 // Deprecated: Use enrichVotesWithCache with a pre-built cache for better efficiency.
-func (b *Bot) enrichVotesWithNicknames(votes []*poll.Vote) {
-	b.enrichVotesWithCache(votes, nil)
+func (b *Bot) enrichVotesWithNicknames(club poll.Club, votes []*poll.Vote) {
+	b.enrichVotesWithCache(club, votes, nil)
 }
 
 // membersFromVotesWithNicknames creates Members from Votes with nicknames looked up.
 // Deprecated: Use membersFromVotesWithCache with a pre-built cache for better efficiency.
-func (b *Bot) membersFromVotesWithNicknames(votes []*poll.Vote) []Member {
-	return b.membersFromVotesWithCache(votes, nil)
+func (b *Bot) membersFromVotesWithNicknames(club poll.Club, votes []*poll.Vote) []Member {
+	return b.membersFromVotesWithCache(club, votes, nil)
 }
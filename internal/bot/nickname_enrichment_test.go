@@ -3,7 +3,9 @@ package bot
 import (
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"nuclight.org/consigliere/internal/poll"
 )
@@ -12,8 +14,8 @@ import (
 // needed for testing nickname enrichment functions.
 type mockNicknameService struct {
 	*poll.Service
-	nicknames map[int64]poll.NicknameInfo    // by user ID
-	byName    map[string]poll.NicknameInfo   // by username (lowercase)
+	nicknames map[int64]poll.NicknameInfo  // by user ID
+	byName    map[string]poll.NicknameInfo // by username (lowercase)
 }
 
 func newMockNicknameService() *mockNicknameService {
@@ -39,23 +41,31 @@ type mockNicknameRepoWithData struct {
 	byName    map[string]poll.NicknameInfo
 }
 
-func (m *mockNicknameRepoWithData) Create(tgUserID *int64, tgUsername *string, gameNick string, gender string) (bool, error) {
+func (m *mockNicknameRepoWithData) Create(club poll.Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
 	return true, nil
 }
 
-func (m *mockNicknameRepoWithData) FindByGameNick(gameNick string) (*int64, *string, error) {
+func (m *mockNicknameRepoWithData) Exists(club poll.Club, gameNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepoWithData) Get(club poll.Club, gameNick string) (*poll.Nickname, error) {
+	return nil, nil
+}
+
+func (m *mockNicknameRepoWithData) FindByGameNick(club poll.Club, gameNick string) (*int64, *string, error) {
 	return nil, nil, nil
 }
 
-func (m *mockNicknameRepoWithData) FindByTgUsername(username string) (string, *int64, error) {
+func (m *mockNicknameRepoWithData) FindByTgUsername(club poll.Club, username string) (string, *int64, error) {
 	return "", nil, nil
 }
 
-func (m *mockNicknameRepoWithData) FindByTgUserID(userID int64) (string, error) {
+func (m *mockNicknameRepoWithData) FindByTgUserID(club poll.Club, userID int64) (string, error) {
 	return "", nil
 }
 
-func (m *mockNicknameRepoWithData) GetDisplayNick(userID int64, username string) (string, error) {
+func (m *mockNicknameRepoWithData) GetDisplayNick(club poll.Club, userID int64, username string) (string, error) {
 	if info, ok := m.nicknames[userID]; ok {
 		return info.DisplayNick(), nil
 	}
@@ -65,7 +75,7 @@ func (m *mockNicknameRepoWithData) GetDisplayNick(userID int64, username string)
 	return "", nil
 }
 
-func (m *mockNicknameRepoWithData) GetDisplayNicksBatch(keys []poll.NicknameLookupKey) (map[int64]poll.NicknameInfo, map[string]poll.NicknameInfo, error) {
+func (m *mockNicknameRepoWithData) GetDisplayNicksBatch(club poll.Club, keys []poll.NicknameLookupKey) (map[int64]poll.NicknameInfo, map[string]poll.NicknameInfo, error) {
 	byUserID := make(map[int64]poll.NicknameInfo)
 	byUsername := make(map[string]poll.NicknameInfo)
 
@@ -97,22 +107,47 @@ func (m *mockNicknameRepoWithData) GetAllGameNicksForUser(userID int64, username
 	return nil, nil
 }
 
+func (m *mockNicknameRepoWithData) List(club poll.Club) ([]*poll.Nickname, error) {
+	return nil, nil
+}
+
+func (m *mockNicknameRepoWithData) Delete(club poll.Club, gameNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepoWithData) Rename(club poll.Club, oldNick, newNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepoWithData) SetGender(club poll.Club, gameNick string, gender string) (bool, error) {
+	return false, nil
+}
+
 // mockPollRepoForNick implements poll.PollRepository for testing
 type mockPollRepoForNick struct {
 	polls map[int64]*poll.Poll
 }
 
-func (m *mockPollRepoForNick) Create(p *poll.Poll) error                          { return nil }
-func (m *mockPollRepoForNick) GetLatestActive(chatID int64) (*poll.Poll, error)   { return nil, nil }
+func (m *mockPollRepoForNick) Create(p *poll.Poll) error                           { return nil }
+func (m *mockPollRepoForNick) GetLatestActive(chatID int64) (*poll.Poll, error)    { return nil, nil }
 func (m *mockPollRepoForNick) GetLatestCancelled(chatID int64) (*poll.Poll, error) { return nil, nil }
 func (m *mockPollRepoForNick) GetLatest(chatID int64) (*poll.Poll, error)          { return nil, nil }
-func (m *mockPollRepoForNick) GetByTgPollID(tgPollID string) (*poll.Poll, error)  { return nil, nil }
-func (m *mockPollRepoForNick) Update(p *poll.Poll) error                          { return nil }
+func (m *mockPollRepoForNick) GetByID(id int64) (*poll.Poll, error)                { return nil, nil }
+func (m *mockPollRepoForNick) GetByTgPollID(tgPollID string) (*poll.Poll, error)   { return nil, nil }
+func (m *mockPollRepoForNick) Update(p *poll.Poll) error                           { return nil }
+func (m *mockPollRepoForNick) GetJudgeLastHosted(club poll.Club, userIDs []int64) (map[int64]time.Time, error) {
+	return nil, nil
+}
+
+func (m *mockPollRepoForNick) ListByClub(club poll.Club, period poll.StatsPeriod) ([]*poll.Poll, error) {
+	return nil, nil
+}
 
 // mockVoteRepoForNick implements poll.VoteRepository for testing
 type mockVoteRepoForNick struct{}
 
-func (m *mockVoteRepoForNick) Record(v *poll.Vote) error                     { return nil }
+func (m *mockVoteRepoForNick) Record(v *poll.Vote) error                          { return nil }
+func (m *mockVoteRepoForNick) RecordAll(votes []*poll.Vote) error                 { return nil }
 func (m *mockVoteRepoForNick) GetCurrentVotes(pollID int64) ([]*poll.Vote, error) { return nil, nil }
 func (m *mockVoteRepoForNick) LookupUserIDByUsername(username string) (int64, bool, error) {
 	return 0, false, nil
@@ -120,6 +155,9 @@ func (m *mockVoteRepoForNick) LookupUserIDByUsername(username string) (int64, bo
 func (m *mockVoteRepoForNick) LookupUsernameByUserID(userID int64) (string, bool, error) {
 	return "", false, nil
 }
+func (m *mockVoteRepoForNick) LookupLatestChatID(userID int64) (int64, bool, error) {
+	return 0, false, nil
+}
 func (m *mockVoteRepoForNick) UpdateVotesUserID(pollID int64, oldUserID, newUserID int64, tgUsername string) error {
 	return nil
 }
@@ -131,7 +169,7 @@ func (m *mockVoteRepoForNick) ConsolidateSyntheticVotes(pollID int64, realUserID
 func createTestBot(nickRepo *mockNicknameRepoWithData) *Bot {
 	pollRepo := &mockPollRepoForNick{polls: make(map[int64]*poll.Poll)}
 	voteRepo := &mockVoteRepoForNick{}
-	svc := poll.NewService(pollRepo, voteRepo, nickRepo)
+	svc := poll.NewService(poll.Repositories{Polls: pollRepo, Votes: voteRepo, Nicknames: nickRepo})
 
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
@@ -238,7 +276,7 @@ func TestEnrichVotesWithNicknames(t *testing.T) {
 				votes[i] = &copy
 			}
 
-			bot.enrichVotesWithNicknames(votes)
+			bot.enrichVotesWithNicknames(poll.ClubVanmo, votes)
 
 			if len(votes) != len(tt.wantFirstNames) {
 				t.Fatalf("got %d votes, want %d", len(votes), len(tt.wantFirstNames))
@@ -325,7 +363,7 @@ func TestMembersFromVotesWithNicknames(t *testing.T) {
 			}
 			bot := createTestBot(nickRepo)
 
-			members := bot.membersFromVotesWithNicknames(tt.votes)
+			members := bot.membersFromVotesWithNicknames(poll.ClubVanmo, tt.votes)
 
 			if len(members) != len(tt.wantNicknames) {
 				t.Fatalf("got %d members, want %d", len(members), len(tt.wantNicknames))
@@ -399,7 +437,7 @@ func TestVotesToResultsVotersWithCache(t *testing.T) {
 			bot := createTestBot(nickRepo)
 
 			// Create cache from votes
-			cache, err := bot.pollService.NewNicknameCacheFromVotes(tt.votes)
+			cache, err := bot.pollService.NewNicknameCacheFromVotes(poll.ClubVanmo, tt.votes)
 			if err != nil {
 				t.Fatalf("NewNicknameCacheFromVotes failed: %v", err)
 			}
@@ -433,7 +471,7 @@ func TestVotesToResultsVotersAllWithCache(t *testing.T) {
 		{TgUserID: 2, TgFirstName: "Bob", TgUsername: "bob", TgOptionIndex: int(poll.OptionComeAt20)},
 	}
 
-	cache, err := bot.pollService.NewNicknameCacheFromVotes(votes)
+	cache, err := bot.pollService.NewNicknameCacheFromVotes(poll.ClubVanmo, votes)
 	if err != nil {
 		t.Fatalf("NewNicknameCacheFromVotes failed: %v", err)
 	}
@@ -478,3 +516,46 @@ func TestVotesToResultsVotersWithNilCache(t *testing.T) {
 		t.Errorf("result[0].Nickname = %q, want empty (nil cache)", result[0].Nickname)
 	}
 }
+
+func TestVotesToResultsVotersWithActor(t *testing.T) {
+	nickRepo := &mockNicknameRepoWithData{
+		nicknames: map[int64]poll.NicknameInfo{
+			100: {Nick: "Секртис", Gender: "male"},
+		},
+		byName: make(map[string]poll.NicknameInfo),
+	}
+	bot := createTestBot(nickRepo)
+
+	votes := []*poll.Vote{
+		{TgUserID: -5, TgFirstName: "Кот", TgOptionIndex: int(poll.OptionComeAt19), IsManual: true, ActorUserID: 100},
+		{TgUserID: -6, TgFirstName: "Лиса", TgOptionIndex: int(poll.OptionComeAt19), IsManual: true, ActorUserID: 200},
+		{TgUserID: 1, TgFirstName: "Alice", TgOptionIndex: int(poll.OptionComeAt19)},
+	}
+
+	cache, err := bot.pollService.NewNicknameCacheFromVotes(poll.ClubVanmo, votes)
+	if err != nil {
+		t.Fatalf("NewNicknameCacheFromVotes failed: %v", err)
+	}
+
+	result := bot.votesToResultsVotersAllWithCache(votes, cache)
+
+	if result[0].ActorID != 100 || result[0].ActorNickname != "Секртис" {
+		t.Errorf("result[0] actor = (%d, %q), want (100, %q)", result[0].ActorID, result[0].ActorNickname, "Секртис")
+	}
+	if result[1].ActorID != 200 || result[1].ActorNickname != "" {
+		t.Errorf("result[1] actor = (%d, %q), want (200, \"\")", result[1].ActorID, result[1].ActorNickname)
+	}
+	if result[2].ActorID != 0 {
+		t.Errorf("result[2].ActorID = %d, want 0", result[2].ActorID)
+	}
+
+	if got := string(formatResultsVoter(result[0])); !strings.Contains(got, "✍️ Секртис") {
+		t.Errorf("formatResultsVoter = %q, want actor nickname", got)
+	}
+	if got := string(formatResultsVoter(result[1])); !strings.Contains(got, "✍️ <code>200</code>") {
+		t.Errorf("formatResultsVoter = %q, want actor ID", got)
+	}
+	if got := string(formatResultsVoter(result[2])); strings.Contains(got, "✍️") {
+		t.Errorf("formatResultsVoter = %q, want no actor for self-cast vote", got)
+	}
+}
@@ -11,6 +11,15 @@ var optionLabels = map[poll.OptionKind]string{
 	poll.OptionNotComing:      "Не приду",
 }
 
+// optionButtonLabels maps OptionKind to short labels for inline voting buttons
+var optionButtonLabels = map[poll.OptionKind]string{
+	poll.OptionComeAt19:        "19:00",
+	poll.OptionComeAt20:        "20:00",
+	poll.OptionComeAt21OrLater: "21:00+",
+	poll.OptionDecideLater:     "Решу позже",
+	poll.OptionNotComing:       "Не приду",
+}
+
 // OptionLabel returns the Russian display label for an option kind
 func OptionLabel(o poll.OptionKind) string {
 	if label, ok := optionLabels[o]; ok {
@@ -45,6 +45,22 @@ var russianMonths = []string{
 	"декабря",
 }
 
+// Russian month names in nominative case (for labels like "январь 2025")
+var russianMonthsNominative = []string{
+	"январь",
+	"февраль",
+	"март",
+	"апрель",
+	"май",
+	"июнь",
+	"июль",
+	"август",
+	"сентябрь",
+	"октябрь",
+	"ноябрь",
+	"декабрь",
+}
+
 // FormatDateRussian formats a date in Russian locale
 // Example: "понедельник, 15 января"
 func FormatDateRussian(t time.Time) string {
@@ -108,7 +124,7 @@ func formatCollectedMembers(members []Member) string {
 			line = m.TgName
 		}
 		if line != "" {
-			lines = append(lines, "— "+line)
+			lines = append(lines, "— "+line+m.GuestSuffix())
 		}
 	}
 	return strings.Join(lines, "\n")
@@ -130,14 +146,16 @@ func formatNickList(members []Member) string {
 			name = "@" + m.TgUsername
 		}
 		if name != "" {
-			names = append(names, name)
+			names = append(names, name+m.GuestSuffix())
 		}
 	}
 	return strings.Join(names, ", ")
 }
 
 // formatResultsVoter formats a single ResultsVoter for the /results admin display.
-// Output format: • <code>TgID</code> @username Name → Nickname
+// Output format: • <code>TgID</code> @username Name → Nickname 🚫N ✍️ Actor
+// The no-show counter is only shown if the player missed events,
+// the actor suffix only for manual votes entered by an admin.
 // Returns template.HTML so the <code> tags are not escaped.
 func formatResultsVoter(v ResultsVoter) template.HTML {
 	var b strings.Builder
@@ -154,17 +172,76 @@ func formatResultsVoter(v ResultsVoter) template.HTML {
 		b.WriteString(" → ")
 		b.WriteString(template.HTMLEscapeString(v.Nickname))
 	}
+	if v.NoShows > 0 {
+		b.WriteString(fmt.Sprintf(" 🚫%d", v.NoShows))
+	}
+	if v.ActorID != 0 {
+		b.WriteString(" ✍️ ")
+		if v.ActorNickname != "" {
+			b.WriteString(template.HTMLEscapeString(v.ActorNickname))
+		} else {
+			b.WriteString(fmt.Sprintf("<code>%d</code>", v.ActorID))
+		}
+	}
 	return template.HTML(b.String())
 }
 
+// formatSide formats a game's winning side
+func formatSide(s poll.Side) string {
+	if s == poll.SideMafia {
+		return "🕶 Победа мафии"
+	}
+	return "🏙 Победа города"
+}
+
+// formatRole formats a player's role marker (empty for civilians)
+func formatRole(r poll.Role) string {
+	switch r {
+	case poll.RoleMafia:
+		return "⚫ мафия"
+	case poll.RoleDon:
+		return "🎩 дон"
+	case poll.RoleSheriff:
+		return "⭐ шериф"
+	default:
+		return ""
+	}
+}
+
+// formatRoleName formats a role name for role cards (including civilians)
+func formatRoleName(r poll.Role) string {
+	switch r {
+	case poll.RoleMafia:
+		return "⚫ Мафия"
+	case poll.RoleDon:
+		return "🎩 Дон мафии"
+	case poll.RoleSheriff:
+		return "⭐ Шериф"
+	default:
+		return "🔴 Мирный житель"
+	}
+}
+
 var templateFuncs = template.FuncMap{
 	"ruDate":                 FormatDateRussian,
 	"ruDateShort":            formatDateRussianShort,
+	"headCount":              poll.HeadCount,
 	"formatMembers":          formatMembers,
 	"formatMentions":         formatMentions,
 	"formatCollectedMembers": formatCollectedMembers,
 	"formatNickList":         formatNickList,
 	"formatResultsVoter":     formatResultsVoter,
+	"formatSide":             formatSide,
+	"formatRole":             formatRole,
+	"formatRoleName":         formatRoleName,
+}
+
+// clubTemplateNames lists the templates every club must define.
+var clubTemplateNames = []string{
+	"poll_title.txt", "invitation.html", "cancel.html", "restore.html", "call.html", "help.html",
+	"collected.html", "attendance.html", "stats.html", "top.html", "monthly_report.html",
+	"game.html", "games.html", "rating.html", "seating.html", "role_card.html", "role_list.html",
+	"results.html", "nicknames.html",
 }
 
 // ParseClubTemplates parses all templates for a club from the embedded FS.
@@ -310,8 +387,17 @@ func HelpMessage(tmpl *template.Template) (string, error) {
 type CollectedData struct {
 	EventDate   time.Time
 	StartTime   string // e.g., "19:00" or "20:00"
+	JudgeName   string // Event host (optional)
 	Members     []Member
-	ComingLater []Member // Players coming at 21:00+
+	ComingLater []Member         // Players coming at 21:00+
+	Tables      []CollectedTable // Members split into tables (empty if everyone fits one table)
+	Reserves    []Member         // Members left over after filling the tables
+}
+
+// CollectedTable is a single game table in the collected message
+type CollectedTable struct {
+	Number  int
+	Members []Member
 }
 
 // RenderCollectedMessage renders the "players collected" notification message.
@@ -323,12 +409,185 @@ func RenderCollectedMessage(tmpl *template.Template, data *CollectedData) (strin
 	return buf.String(), nil
 }
 
+// AttendanceData holds data for the attendance checklist template
+type AttendanceData struct {
+	EventDate time.Time
+	Attended  []Member
+	NoShows   []Member
+}
+
+// RenderAttendanceMessage renders the post-event attendance checklist message.
+func RenderAttendanceMessage(tmpl *template.Template, data *AttendanceData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "attendance.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StatsData holds data for the player statistics template
+type StatsData struct {
+	Name        string
+	PeriodLabel string
+	Stats       *poll.PlayerStats
+}
+
+// RenderStatsMessage renders the player statistics message.
+func RenderStatsMessage(tmpl *template.Template, data *StatsData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "stats.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// LeaderboardRow is a single ranked player in the leaderboard
+type LeaderboardRow struct {
+	Rank         int
+	Member       Member
+	EventsPlayed int
+}
+
+// TopData holds data for the club leaderboard template
+type TopData struct {
+	PeriodLabel string
+	Rows        []LeaderboardRow
+}
+
+// RenderTopMessage renders the club leaderboard message.
+func RenderTopMessage(tmpl *template.Template, data *TopData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "top.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// MonthlyReportData holds data for the monthly club activity report template
+type MonthlyReportData struct {
+	PeriodLabel    string
+	Summary        *poll.ClubSummary
+	BusiestWeekday string // Russian weekday name
+	Rows           []LeaderboardRow
+}
+
+// RenderMonthlyReportMessage renders the monthly club activity report.
+func RenderMonthlyReportMessage(tmpl *template.Template, data *MonthlyReportData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "monthly_report.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// GameData holds data for the game result template
+type GameData struct {
+	EventDate time.Time
+	Game      *poll.Game
+}
+
+// RenderGameMessage renders the result of a single game.
+func RenderGameMessage(tmpl *template.Template, data *GameData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "game.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// GamesData holds data for the event night games list template
+type GamesData struct {
+	Games []*GameData
+}
+
+// RenderGamesMessage renders all games of an event night.
+func RenderGamesMessage(tmpl *template.Template, data *GamesData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "games.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RatingRow is a single ranked player in the rating
+type RatingRow struct {
+	Rank   int
+	Member Member
+	Rating *poll.PlayerRating
+}
+
+// RatingData holds data for the player rating template
+type RatingData struct {
+	PeriodLabel string
+	Rows        []*RatingRow
+	Own         *RatingRow // sender's position if not in Rows (optional)
+}
+
+// RenderRatingMessage renders the player rating message.
+func RenderRatingMessage(tmpl *template.Template, data *RatingData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "rating.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SeatingData holds data for the seating chart template
+type SeatingData struct {
+	EventDate time.Time
+	Players   []*poll.GameParticipant // ordered by seat
+	Reserves  []Member                // main voters who didn't get a seat
+}
+
+// RenderSeatingMessage renders the seating chart posted to the chat.
+func RenderSeatingMessage(tmpl *template.Template, data *SeatingData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "seating.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RoleCardData holds data for the role card sent privately to a player
+type RoleCardData struct {
+	EventDate time.Time
+	Player    *poll.GameParticipant
+}
+
+// RenderRoleCardMessage renders a player's private role card.
+func RenderRoleCardMessage(tmpl *template.Template, data *RoleCardData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "role_card.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RoleListData holds data for the full role list sent privately to the judge
+type RoleListData struct {
+	EventDate   time.Time
+	Players     []*poll.GameParticipant // ordered by seat
+	Undelivered map[int]bool            // seats whose role card could not be delivered
+}
+
+// RenderRoleListMessage renders the judge's full role list.
+func RenderRoleListMessage(tmpl *template.Template, data *RoleListData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "role_list.html", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // ResultsVoter holds voter info for the /results admin display
 type ResultsVoter struct {
-	TgID       int64
-	TgUsername string
-	TgName     string
-	Nickname   string
+	TgID          int64
+	TgUsername    string
+	TgName        string
+	Nickname      string
+	ActorID       int64  // Admin who entered the vote manually (0 if self-cast)
+	ActorNickname string // Admin's game nickname (optional)
+	NoShows       int    // Number of past events missed after voting to attend
 }
 
 // ResultsData holds data for the results admin message template
@@ -338,6 +597,8 @@ type ResultsData struct {
 	At20        []ResultsVoter
 	ComingLater []ResultsVoter
 	Undecided   []ResultsVoter
+	Price       int      // event price in ₾ (0 if not set)
+	Unpaid      []Member // attending players who have not paid (only when a price is set)
 }
 
 // RenderResultsMessage renders the admin results message.
@@ -348,3 +609,31 @@ func RenderResultsMessage(tmpl *template.Template, data *ResultsData) (string, e
 	}
 	return buf.String(), nil
 }
+
+// NicknamesData holds data for the nickname list template
+type NicknamesData struct {
+	Nicknames []*poll.Nickname
+	Hidden    int // nicknames left out to fit the message limit
+}
+
+// Total returns the number of nicknames including hidden ones.
+func (d *NicknamesData) Total() int {
+	return len(d.Nicknames) + d.Hidden
+}
+
+// RenderNicknamesMessage renders the list of all nickname mappings.
+// If the message exceeds Telegram's limit, the list is cut and the rest is counted in Hidden.
+func RenderNicknamesMessage(tmpl *template.Template, data *NicknamesData) (string, error) {
+	shown := &NicknamesData{Nicknames: data.Nicknames, Hidden: data.Hidden}
+	for {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "nicknames.html", shown); err != nil {
+			return "", err
+		}
+		if buf.Len() <= TelegramMaxMessageLength || len(shown.Nicknames) == 0 {
+			return buf.String(), nil
+		}
+		shown.Nicknames = shown.Nicknames[:len(shown.Nicknames)-1]
+		shown.Hidden++
+	}
+}
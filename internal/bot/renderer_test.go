@@ -1,6 +1,9 @@
 package bot
 
 import (
+	"context"
+	"fmt"
+	"html"
 	"html/template"
 	"os"
 	"strings"
@@ -237,3 +240,248 @@ func TestRenderInvitationMessage(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderMonthlyReportMessage(t *testing.T) {
+	data := &MonthlyReportData{
+		PeriodLabel:    "февраль 2025",
+		Summary:        &poll.ClubSummary{EventsHeld: 8, TotalAttendance: 100, Tables: 10, BusiestWeekday: time.Saturday},
+		BusiestWeekday: russianWeekdays[time.Saturday],
+		Rows: []LeaderboardRow{
+			{Rank: 1, Member: Member{Nickname: "Кот"}, EventsPlayed: 8},
+			{Rank: 2, Member: Member{TgUsername: "fox"}, EventsPlayed: 6},
+		},
+	}
+
+	result, err := RenderMonthlyReportMessage(testTemplates, data)
+	if err != nil {
+		t.Fatalf("RenderMonthlyReportMessage failed: %v", err)
+	}
+
+	for _, want := range []string{"февраль 2025", "<b>8</b>", "<b>10.0</b>", "суббота", "1. Кот — 8", "2. @fox — 6"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected result to contain %q, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestRenderGamesMessage(t *testing.T) {
+	eventDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	game := func(number int, winner poll.Side, judge string) *GameData {
+		return &GameData{EventDate: eventDate, Game: &poll.Game{
+			Number:    number,
+			Winner:    winner,
+			JudgeName: judge,
+			Participants: []*poll.GameParticipant{
+				{Seat: 1, Name: "Кот", Role: poll.RoleDon},
+				{Seat: 2, Name: "Лиса", Role: poll.RoleCivilian},
+				{Seat: 3, Name: "Енот", Role: poll.RoleSheriff},
+			},
+		}}
+	}
+
+	result, err := RenderGamesMessage(testTemplates, &GamesData{Games: []*GameData{
+		game(1, poll.SideCity, "Судья"),
+		game(2, poll.SideMafia, ""),
+	}})
+	if err != nil {
+		t.Fatalf("RenderGamesMessage failed: %v", err)
+	}
+
+	for _, want := range []string{"Игра №1", "Игра №2", "Победа города", "Победа мафии", "Судья: Судья", "1. Кот 🎩 дон", "2. Лиса\n", "3. Енот ⭐ шериф"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected result to contain %q, got:\n%s", want, result)
+		}
+	}
+	if strings.Count(result, "Судья:") != 1 {
+		t.Errorf("expected judge only for the first game, got:\n%s", result)
+	}
+}
+
+func TestRenderRoleListMessage(t *testing.T) {
+	data := &RoleListData{
+		EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Players: []*poll.GameParticipant{
+			{Seat: 1, Name: "Кот", Role: poll.RoleDon},
+			{Seat: 2, Name: "Лиса", Role: poll.RoleCivilian},
+		},
+		Undelivered: map[int]bool{2: true},
+	}
+
+	result, err := RenderRoleListMessage(testTemplates, data)
+	if err != nil {
+		t.Fatalf("RenderRoleListMessage failed: %v", err)
+	}
+
+	if !strings.Contains(result, "1. Кот — 🎩 Дон мафии\n") {
+		t.Errorf("expected delivered don line, got:\n%s", result)
+	}
+	if !strings.Contains(result, "2. Лиса — 🔴 Мирный житель ⚠️ не доставлено") {
+		t.Errorf("expected undelivered civilian line, got:\n%s", result)
+	}
+}
+
+func TestRenderCollectedMessage_Tables(t *testing.T) {
+	data := &CollectedData{
+		EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		StartTime: "19:00",
+		Members:   []Member{{Nickname: "Кот"}, {Nickname: "Лиса"}},
+	}
+
+	single, err := RenderCollectedMessage(testTemplates, data)
+	if err != nil {
+		t.Fatalf("RenderCollectedMessage failed: %v", err)
+	}
+	if !strings.Contains(single, "просьба не опаздывать\n\n— Кот\n— Лиса") || strings.Contains(single, "Стол 1") {
+		t.Errorf("expected single list, got:\n%s", single)
+	}
+
+	data.Tables = []CollectedTable{
+		{Number: 1, Members: []Member{{Nickname: "Кот"}}},
+		{Number: 2, Members: []Member{{Nickname: "Лиса"}}},
+	}
+	data.Reserves = []Member{{Nickname: "Енот"}}
+
+	split, err := RenderCollectedMessage(testTemplates, data)
+	if err != nil {
+		t.Fatalf("RenderCollectedMessage failed: %v", err)
+	}
+	for _, want := range []string{"<b>Стол 1:</b>\n— Кот", "<b>Стол 2:</b>\n— Лиса", "<b>Запасные:</b> Енот"} {
+		if !strings.Contains(split, want) {
+			t.Errorf("expected result to contain %q, got:\n%s", want, split)
+		}
+	}
+}
+
+func TestRenderPaymentInfo(t *testing.T) {
+	eventDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("invitation shows poll price", func(t *testing.T) {
+		result, err := RenderInvitationMessage(testTemplates, &poll.InvitationData{
+			Poll:      &poll.Poll{EventDate: eventDate, Price: 25},
+			EventDate: eventDate,
+		})
+		if err != nil {
+			t.Fatalf("RenderInvitationMessage failed: %v", err)
+		}
+		if !strings.Contains(result, "cтоимость 25₾") {
+			t.Errorf("expected price line, got:\n%s", result)
+		}
+	})
+
+	t.Run("results list unpaid players", func(t *testing.T) {
+		result, err := RenderResultsMessage(testTemplates, &ResultsData{
+			EventDate: eventDate,
+			Price:     20,
+			Unpaid:    []Member{{Nickname: "Кот"}, {TgUsername: "fox"}},
+		})
+		if err != nil {
+			t.Fatalf("RenderResultsMessage failed: %v", err)
+		}
+		if !strings.Contains(result, "Не оплатили 20₾ (2):</b>\nКот, @fox") {
+			t.Errorf("expected unpaid list, got:\n%s", result)
+		}
+	})
+
+	t.Run("stats show debt", func(t *testing.T) {
+		result, err := RenderStatsMessage(testTemplates, &StatsData{
+			Name:  "Кот",
+			Stats: &poll.PlayerStats{Debt: 40},
+		})
+		if err != nil {
+			t.Fatalf("RenderStatsMessage failed: %v", err)
+		}
+		if !strings.Contains(result, "Долг: <b>40₾</b>") {
+			t.Errorf("expected debt line, got:\n%s", result)
+		}
+	})
+}
+
+func TestRenderGuests(t *testing.T) {
+	eventDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("invitation counts guests", func(t *testing.T) {
+		result, err := RenderInvitationMessage(testTemplates, &poll.InvitationData{
+			EventDate: eventDate,
+			Participants: []*poll.Vote{
+				{TgFirstName: "Кот", TgOptionIndex: int(poll.OptionComeAt19), Guests: 1},
+				{TgFirstName: "Лиса", TgOptionIndex: int(poll.OptionComeAt20)},
+			},
+		})
+		if err != nil {
+			t.Fatalf("RenderInvitationMessage failed: %v", err)
+		}
+		if !strings.Contains(result, "Участники (3):") {
+			t.Errorf("expected guests in participant total, got:\n%s", result)
+		}
+		// html/template escapes "+" as &#43;, which Telegram decodes
+		if !strings.Contains(html.UnescapeString(result), "— Кот +1 (19:00)") {
+			t.Errorf("expected host with guest marker, got:\n%s", result)
+		}
+	})
+
+	t.Run("collected message marks guests", func(t *testing.T) {
+		result, err := RenderCollectedMessage(testTemplates, &CollectedData{
+			EventDate:   eventDate,
+			StartTime:   "19:00",
+			Members:     []Member{{Nickname: "Кот", Guests: 2}},
+			ComingLater: []Member{{Nickname: "Лиса", Guests: 1}},
+		})
+		if err != nil {
+			t.Fatalf("RenderCollectedMessage failed: %v", err)
+		}
+		text := html.UnescapeString(result)
+		if !strings.Contains(text, "— Кот +2") || !strings.Contains(text, "Лиса +1") {
+			t.Errorf("expected guest markers, got:\n%s", result)
+		}
+	})
+}
+
+func TestRenderNicknamesMessage(t *testing.T) {
+	nicknames := []*poll.Nickname{
+		{Club: poll.ClubVanmo, TgUserID: 123, TgUsername: "kot", NicknameInfo: poll.NicknameInfo{Nick: "Кот", Gender: "male"}},
+		{Club: poll.SharedNicknames, NicknameInfo: poll.NicknameInfo{Nick: "Лиса"}},
+	}
+
+	result, err := RenderNicknamesMessage(testTemplates, &NicknamesData{Nicknames: nicknames})
+	if err != nil {
+		t.Fatalf("RenderNicknamesMessage failed: %v", err)
+	}
+	for _, want := range []string{"Игровые ники (2)", "г-н Кот — @kot <code>123</code>", "Лиса 🌐 — ID неизвестен"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in:\n%s", want, result)
+		}
+	}
+
+	t.Run("long list is cut", func(t *testing.T) {
+		var many []*poll.Nickname
+		for i := range 300 {
+			many = append(many, &poll.Nickname{TgUserID: int64(1000000 + i), TgUsername: fmt.Sprintf("player%d", i), NicknameInfo: poll.NicknameInfo{Nick: fmt.Sprintf("Игрок %d", i)}})
+		}
+		result, err := RenderNicknamesMessage(testTemplates, &NicknamesData{Nicknames: many})
+		if err != nil {
+			t.Fatalf("RenderNicknamesMessage failed: %v", err)
+		}
+		if len(result) > TelegramMaxMessageLength {
+			t.Errorf("message length %d exceeds limit", len(result))
+		}
+		if !strings.Contains(result, "Игровые ники (300)") || !strings.Contains(result, "…и ещё") {
+			t.Errorf("expected total and hidden count, got:\n%s", result)
+		}
+	})
+}
+
+func TestCheckTemplates(t *testing.T) {
+	if err := InitClubTemplates(); err != nil {
+		t.Fatalf("InitClubTemplates failed: %v", err)
+	}
+	if err := CheckTemplates(context.Background()); err != nil {
+		t.Errorf("CheckTemplates after init: %v", err)
+	}
+
+	saved := vanmoConfig.templates
+	defer func() { vanmoConfig.templates = saved }()
+	vanmoConfig.templates = template.Must(template.New("").Parse(`{{define "help.html"}}{{end}}`))
+	if err := CheckTemplates(context.Background()); err == nil {
+		t.Error("expected an error for a club missing templates")
+	}
+}
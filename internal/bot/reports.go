@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"log/slog"
+	"time"
+
+	tele "gopkg.in/telebot.v4"
+)
+
+// Monthly report scheduling
+const (
+	MonthlyReportCheckInterval = time.Hour
+	MonthlyReportHour          = 12 // local hour on the 1st of the month when the report is posted
+	MonthlyReportGraceDays     = 3  // days into the month a missed report is still posted (e.g. after downtime)
+	MonthlyReportTopSize       = 5  // number of most active players in the report
+)
+
+// runMonthlyReports periodically checks whether the previous month's report is due
+// and posts it to every club's report chat. Blocks until the bot is stopped.
+func (b *Bot) runMonthlyReports() {
+	ticker := time.NewTicker(MonthlyReportCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if month, ok := monthlyReportDue(time.Now()); ok {
+			for _, config := range clubConfigs {
+				if config.ReportChatID != 0 {
+					b.postMonthlyReport(config, month)
+				}
+			}
+		}
+
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// monthlyReportDue returns the start of the month to report on and whether a report is due.
+// The report for the previous month is due from MonthlyReportHour on the 1st
+// until the end of the grace period.
+func monthlyReportDue(now time.Time) (time.Time, bool) {
+	if now.Day() > MonthlyReportGraceDays || (now.Day() == 1 && now.Hour() < MonthlyReportHour) {
+		return time.Time{}, false
+	}
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return thisMonth.AddDate(0, -1, 0), true
+}
+
+// postMonthlyReport posts the activity report for the month starting at monthStart.
+// Each report is posted at most once per chat; months without events are skipped.
+// This is a background operation - errors are logged, not returned.
+func (b *Bot) postMonthlyReport(config *ClubConfig, monthStart time.Time) {
+	period := monthPeriod(monthStart)
+	logger := b.logger.With("club", config.Club, "chat_id", config.ReportChatID, "period", period.Label)
+
+	summary, err := b.pollService.GetClubSummary(config.Club, period.StatsPeriod, config.TableSize)
+	if err != nil {
+		logger.Error("failed to get club summary", "error", err)
+		return
+	}
+	if summary.EventsHeld == 0 {
+		return
+	}
+
+	// Mark before sending so a crash or restart never posts the same report twice;
+	// the mark is removed if the report could not be sent, to retry on the next check
+	month := monthStart.Format("2006-01")
+	isNew, err := b.pollService.MarkReportPosted(config.ReportChatID, month)
+	if err != nil {
+		logger.Error("failed to mark monthly report", "error", err)
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	rows, err := b.leaderboardRows(config.Club, period.StatsPeriod, MonthlyReportTopSize)
+	if err != nil {
+		logger.Warn("failed to get leaderboard for monthly report", "error", err)
+	}
+
+	html, err := RenderMonthlyReportMessage(config.templates, &MonthlyReportData{
+		PeriodLabel:    period.Label,
+		Summary:        summary,
+		BusiestWeekday: russianWeekdays[summary.BusiestWeekday],
+		Rows:           rows,
+	})
+	if err != nil {
+		logger.Error("failed to render monthly report", "error", err)
+		b.unmarkMonthlyReport(logger, config.ReportChatID, month)
+		return
+	}
+
+	if _, err := b.SendWithRetry(&tele.Chat{ID: config.ReportChatID}, html, tele.ModeHTML, tele.Silent); err != nil {
+		logger.Error("failed to send monthly report", "error", err)
+		b.unmarkMonthlyReport(logger, config.ReportChatID, month)
+		return
+	}
+
+	logger.Info("monthly report posted", "events_held", summary.EventsHeld)
+}
+
+// unmarkMonthlyReport removes the posted mark of a report that failed, so that it is retried.
+func (b *Bot) unmarkMonthlyReport(logger *slog.Logger, chatID int64, month string) {
+	if err := b.pollService.UnmarkReportPosted(chatID, month); err != nil {
+		logger.Error("failed to unmark monthly report", "error", err)
+	}
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonthlyReportDue(t *testing.T) {
+	tests := []struct {
+		now       time.Time
+		wantMonth string
+		wantDue   bool
+	}{
+		{time.Date(2025, 3, 1, 11, 59, 0, 0, time.UTC), "", false},
+		{time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), "2025-02", true},
+		{time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC), "2025-02", true},
+		{time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC), "", false},
+		{time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC), "2024-12", true},
+	}
+
+	for _, tt := range tests {
+		month, due := monthlyReportDue(tt.now)
+		if due != tt.wantDue {
+			t.Errorf("monthlyReportDue(%s) due = %v, want %v", tt.now, due, tt.wantDue)
+			continue
+		}
+		if due && month.Format("2006-01") != tt.wantMonth {
+			t.Errorf("monthlyReportDue(%s) month = %s, want %s", tt.now, month.Format("2006-01"), tt.wantMonth)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"strconv"
+	"strings"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Words of a chat message that mean the player is coming ("буду в 21") or not ("не приду").
+var (
+	textVoteComingWords    = []string{"буду", "приду", "иду", "будем", "придём", "придем"}
+	textVoteNotComingWords = []string{"-", "—", "−", "минус", "пас", "не буду", "не приду", "не иду", "не смогу"}
+	textVoteTimePrefixes   = []string{"в", "к", "на", "около"}
+)
+
+// Evening hours a player can arrive at; "в 9" means 21:00
+const (
+	textVoteFirstHour = 18
+	textVoteLastHour  = 23
+)
+
+// ParseTextVote recognizes a chat message that answers the poll in words:
+//
+//	"+", "буду"                 — coming at the start (19:00)
+//	"+20", "буду в 21", "к 8"   — coming at the given time (20:30 counts as 21:00+)
+//	"-", "не приду", "пас"      — not coming
+//
+// Text is case-insensitive; trailing "!", "." and ")" are ignored.
+// Returns false for anything else, including ambiguous text like "+2" (guests?) or
+// "буду, но позже", so that only clear answers become votes.
+func ParseTextVote(text string) (poll.OptionKind, bool) {
+	s := strings.ToLower(strings.TrimSpace(text))
+	s = strings.TrimRight(s, "!.) ")
+	if s == "" {
+		return 0, false
+	}
+
+	for _, word := range textVoteNotComingWords {
+		if s == word {
+			return poll.OptionNotComing, true
+		}
+	}
+
+	// "+", "+20", "+ к 20", "буду", "буду в 21"
+	rest, ok := strings.CutPrefix(s, "+")
+	if !ok {
+		for _, word := range textVoteComingWords {
+			if rest, ok = cutWord(s, word); ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return poll.OptionComeAt19, true
+	}
+	for _, prefix := range textVoteTimePrefixes {
+		if after, found := cutWord(rest, prefix); found {
+			rest = strings.TrimSpace(after)
+			break
+		}
+	}
+	return arrivalOption(rest)
+}
+
+// cutWord removes word from the start of s if it is followed by a space or nothing.
+func cutWord(s, word string) (string, bool) {
+	rest, ok := strings.CutPrefix(s, word)
+	if !ok || (rest != "" && rest[0] != ' ') {
+		return s, false
+	}
+	return rest, true
+}
+
+// arrivalOption maps an evening arrival time ("21", "8", "20:30", "19.45") to a poll option:
+// by 19:00, by 20:00, or later.
+func arrivalOption(s string) (poll.OptionKind, bool) {
+	hourText, minuteText, hasMinutes := strings.Cut(strings.ReplaceAll(s, ".", ":"), ":")
+
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0, false
+	}
+	minutes := 0
+	if hasMinutes {
+		if len(minuteText) != 2 {
+			return 0, false
+		}
+		minutes, err = strconv.Atoi(minuteText)
+		if err != nil || minutes < 0 || minutes > 59 {
+			return 0, false
+		}
+	}
+
+	// 12-hour evening times: "к 8" means 20:00
+	if hour >= textVoteFirstHour-12 && hour <= textVoteLastHour-12 {
+		hour += 12
+	}
+	if hour < textVoteFirstHour || hour > textVoteLastHour {
+		return 0, false
+	}
+
+	switch {
+	case hour < 19 || (hour == 19 && minutes == 0):
+		return poll.OptionComeAt19, true
+	case hour < 20 || (hour == 20 && minutes == 0):
+		return poll.OptionComeAt20, true
+	default:
+		return poll.OptionComeAt21OrLater, true
+	}
+}
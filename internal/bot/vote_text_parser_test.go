@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"testing"
+
+	tele "gopkg.in/telebot.v4"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestParseTextVote(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   poll.OptionKind
+		wantOK bool
+	}{
+		{"+", poll.OptionComeAt19, true},
+		{"+!", poll.OptionComeAt19, true},
+		{"Буду", poll.OptionComeAt19, true},
+		{"+19", poll.OptionComeAt19, true},
+		{"+20", poll.OptionComeAt20, true},
+		{"+ 20", poll.OptionComeAt20, true},
+		{"+ к 20", poll.OptionComeAt20, true},
+		{"буду в 21", poll.OptionComeAt21OrLater, true},
+		{"буду к 8", poll.OptionComeAt20, true},
+		{"приду в 19:30", poll.OptionComeAt20, true},
+		{"буду в 20.30", poll.OptionComeAt21OrLater, true},
+		{"иду к 22", poll.OptionComeAt21OrLater, true},
+		{"-", poll.OptionNotComing, true},
+		{"Не приду)", poll.OptionNotComing, true},
+		{"не приду", poll.OptionNotComing, true},
+		{"пас.", poll.OptionNotComing, true},
+		{"+2", 0, false},
+		{"+1", 0, false},
+		{"буду в 3", 0, false},
+		{"буду, но позже", 0, false},
+		{"будущее", 0, false},
+		{"в 21", 0, false},
+		{"+ опоздаю", 0, false},
+		{"20:5", 0, false},
+		{"", 0, false},
+		{")", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseTextVote(tt.text)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("ParseTextVote(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsPollReply(t *testing.T) {
+	p := &poll.Poll{TgMessageID: 10, TgInvitationMessageID: 11}
+	tests := []struct {
+		name string
+		msg  *tele.Message
+		want bool
+	}{
+		{"not a reply", &tele.Message{}, true},
+		{"reply to the poll", &tele.Message{ReplyTo: &tele.Message{ID: 10}}, true},
+		{"reply to the invitation", &tele.Message{ReplyTo: &tele.Message{ID: 11}}, true},
+		{"reply to someone", &tele.Message{ReplyTo: &tele.Message{ID: 12}}, false},
+		{"in a forum topic", &tele.Message{ThreadID: 5, TopicMessage: true, ReplyTo: &tele.Message{ID: 5}}, true},
+		{"reply in a forum topic", &tele.Message{ThreadID: 5, TopicMessage: true, ReplyTo: &tele.Message{ID: 12}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPollReply(tt.msg, p); got != tt.want {
+				t.Errorf("isPollReply = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
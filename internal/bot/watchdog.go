@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// WatchdogCheckInterval is how often the watchdog checks that updates keep flowing.
+const WatchdogCheckInterval = 30 * time.Second
+
+// updatesTracker is the HTTP transport of the bot; it records when getUpdates last succeeded.
+// A long poll succeeds every polling timeout even when nothing happens in the chats,
+// so a long silence means the poller is stuck or Telegram is unreachable.
+type updatesTracker struct {
+	next http.RoundTripper
+	last atomic.Int64 // unix nanoseconds
+}
+
+func newUpdatesTracker(next http.RoundTripper) *updatesTracker {
+	t := &updatesTracker{next: next}
+	t.touch(time.Now())
+	return t
+}
+
+func (t *updatesTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusOK && strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		t.touch(time.Now())
+	}
+	return resp, err
+}
+
+func (t *updatesTracker) touch(now time.Time) {
+	t.last.Store(now.UnixNano())
+}
+
+// lastUpdates returns when getUpdates last succeeded or an update was delivered,
+// or when tracking started if neither happened yet.
+func (t *updatesTracker) lastUpdates() time.Time {
+	return time.Unix(0, t.last.Load())
+}
+
+// CheckUpdates returns an error if no poll succeeded for longer than the watchdog timeout.
+// It makes no requests to Telegram. With a webhook it always passes: Telegram posts only
+// when something happens, so a quiet chat looks the same as a broken webhook.
+func (b *Bot) CheckUpdates(ctx context.Context) error {
+	if b.webhook {
+		return nil
+	}
+	if since := time.Since(b.updates.lastUpdates()); since > b.watchdogTimeout {
+		return fmt.Errorf("no updates from telegram for %s", since.Round(time.Second))
+	}
+	return nil
+}
+
+// runWatchdog logs when updates stop flowing and when they resume, see CheckUpdates.
+// With WatchdogExit the process exits instead, so its supervisor restarts it.
+// It is only started with long polling.
+func (b *Bot) runWatchdog() {
+	ticker := time.NewTicker(WatchdogCheckInterval)
+	defer ticker.Stop()
+
+	stalled := false
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := b.CheckUpdates(context.Background())
+		switch {
+		case err != nil && !stalled:
+			stalled = true
+			b.logger.Error("watchdog: updates stopped flowing", "error", err, "exit", b.watchdogExit)
+			if b.watchdogExit {
+				os.Exit(1)
+			}
+		case err == nil && stalled:
+			stalled = false
+			b.logger.Info("watchdog: updates resumed")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roundTripFunc serves HTTP requests with a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestUpdatesTracker(t *testing.T) {
+	status := http.StatusOK
+	tracker := newUpdatesTracker(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status}, nil
+	}))
+	start := time.Now().Add(-time.Hour)
+	tracker.touch(start)
+
+	send := func(method string) {
+		req, _ := http.NewRequest(http.MethodPost, "https://api.telegram.org/bot123:abc/"+method, nil)
+		if _, err := tracker.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+	}
+
+	send("sendMessage")
+	if !tracker.lastUpdates().Equal(start) {
+		t.Error("sendMessage counted as getUpdates")
+	}
+	status = http.StatusConflict
+	send("getUpdates")
+	if !tracker.lastUpdates().Equal(start) {
+		t.Error("failed getUpdates counted as successful")
+	}
+	status = http.StatusOK
+	send("getUpdates")
+	if time.Since(tracker.lastUpdates()) > time.Minute {
+		t.Error("successful getUpdates not recorded")
+	}
+}
+
+func TestBot_CheckUpdates(t *testing.T) {
+	b := &Bot{updates: newUpdatesTracker(nil), watchdogTimeout: time.Minute}
+	if err := b.CheckUpdates(context.Background()); err != nil {
+		t.Errorf("CheckUpdates right after start: %v", err)
+	}
+
+	b.updates.touch(time.Now().Add(-2 * time.Minute))
+	if err := b.CheckUpdates(context.Background()); err == nil {
+		t.Error("CheckUpdates after 2 minutes without updates: expected error")
+	}
+}
+
+func TestBot_CheckUpdates_QuietWebhook(t *testing.T) {
+	b := &Bot{updates: newUpdatesTracker(nil), watchdogTimeout: time.Minute, webhook: true}
+	// Telegram posts nothing while the chats are quiet
+	b.updates.touch(time.Now().Add(-time.Hour))
+	if err := b.CheckUpdates(context.Background()); err != nil {
+		t.Errorf("CheckUpdates of a quiet webhook bot: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +14,7 @@ import (
 const (
 	DefaultTempMessageDelay = 5 * time.Second
 	DefaultPollingTimeout   = 10 * time.Second
+	DefaultWatchdogTimeout  = 5 * time.Minute
 )
 
 type Config struct {
@@ -22,6 +24,18 @@ type Config struct {
 	DevMode          bool
 	TempMessageDelay time.Duration
 	PollingTimeout   time.Duration
+	APIAddr          string        // listen address of the HTTP admin API (empty = disabled)
+	APIToken         string        // bearer token for the admin API (required with APIAddr)
+	DashboardAddr    string        // listen address of the web dashboard for club admins (empty = disabled)
+	MetricsAddr      string        // listen address of /metrics (empty = disabled)
+	HealthAddr       string        // listen address of /healthz and /readyz (empty = disabled)
+	WatchdogTimeout  time.Duration // how long updates may stop flowing before the watchdog reports it
+	WatchdogExit     bool          // exit when updates stop flowing, so a supervisor restarts the bot
+	WebhookURL       string        // public HTTPS URL Telegram posts updates to (empty = long polling)
+	WebhookListen    string        // listen address of the webhook server
+	WebhookSecret    string        // secret token Telegram sends with every update
+	WebhookTLSCert   string        // certificate file to serve the webhook over TLS (empty = plain HTTP behind a proxy)
+	WebhookTLSKey    string        // key file for WebhookTLSCert
 }
 
 func Load() (*Config, error) {
@@ -55,6 +69,43 @@ func Load() (*Config, error) {
 		}
 	}
 
+	watchdogTimeout := DefaultWatchdogTimeout
+	if val := os.Getenv("WATCHDOG_TIMEOUT_SECONDS"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds > 0 {
+			watchdogTimeout = time.Duration(seconds) * time.Second
+		}
+	}
+	if watchdogTimeout <= pollingTimeout {
+		return nil, fmt.Errorf("WATCHDOG_TIMEOUT_SECONDS must be longer than POLLING_TIMEOUT_SECONDS")
+	}
+
+	webhookURL := os.Getenv("WEBHOOK_URL")
+	webhookListen := os.Getenv("WEBHOOK_LISTEN")
+	webhookSecret := os.Getenv("WEBHOOK_SECRET")
+	webhookTLSCert := os.Getenv("WEBHOOK_TLS_CERT")
+	webhookTLSKey := os.Getenv("WEBHOOK_TLS_KEY")
+	if webhookURL != "" {
+		if !strings.HasPrefix(webhookURL, "https://") {
+			return nil, fmt.Errorf("WEBHOOK_URL must be an https:// URL")
+		}
+		if webhookListen == "" {
+			return nil, fmt.Errorf("WEBHOOK_LISTEN is required with WEBHOOK_URL")
+		}
+		if !validWebhookSecret(webhookSecret) {
+			return nil, fmt.Errorf("WEBHOOK_SECRET is required with WEBHOOK_URL: 1-256 characters A-Z, a-z, 0-9, _ and -")
+		}
+		if (webhookTLSCert == "") != (webhookTLSKey == "") {
+			return nil, fmt.Errorf("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+		}
+	}
+
+	apiAddr := os.Getenv("API_ADDR")
+	apiToken := os.Getenv("API_TOKEN")
+	if apiAddr != "" && apiToken == "" {
+		// A loopback address does not prove the client is local behind a reverse proxy
+		return nil, fmt.Errorf("API_TOKEN is required with API_ADDR")
+	}
+
 	return &Config{
 		TelegramToken:    token,
 		DBPath:           dbPath,
@@ -62,5 +113,42 @@ func Load() (*Config, error) {
 		DevMode:          devMode,
 		TempMessageDelay: tempMessageDelay,
 		PollingTimeout:   pollingTimeout,
+		APIAddr:          apiAddr,
+		APIToken:         apiToken,
+		DashboardAddr:    os.Getenv("DASHBOARD_ADDR"),
+		MetricsAddr:      os.Getenv("METRICS_ADDR"),
+		HealthAddr:       os.Getenv("HEALTH_ADDR"),
+		WatchdogTimeout:  watchdogTimeout,
+		WatchdogExit:     os.Getenv("WATCHDOG_EXIT") == "true",
+		WebhookURL:       webhookURL,
+		WebhookListen:    webhookListen,
+		WebhookSecret:    webhookSecret,
+		WebhookTLSCert:   webhookTLSCert,
+		WebhookTLSKey:    webhookTLSKey,
 	}, nil
 }
+
+// validWebhookSecret reports whether s is a secret token Telegram accepts for a webhook.
+func validWebhookSecret(s string) bool {
+	if len(s) == 0 || len(s) > 256 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadDBPath reads only the database path, for offline subcommands that don't talk to Telegram.
+func LoadDBPath() (string, error) {
+	// Load .env file if it exists (ignore error if not found)
+	_ = godotenv.Load()
+
+	dbPath := os.Getenv("DB_PATH")
+	if dbPath == "" {
+		return "", fmt.Errorf("DB_PATH is required")
+	}
+	return dbPath, nil
+}
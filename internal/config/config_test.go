@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad_AllEnvVarsSet(t *testing.T) {
@@ -38,3 +39,135 @@ func TestLoad_MissingToken(t *testing.T) {
 		t.Fatal("expected error for missing token")
 	}
 }
+
+func TestLoadDBPath(t *testing.T) {
+	os.Unsetenv("TELEGRAM_BOT_API_KEY")
+	os.Setenv("DB_PATH", "/tmp/test.db")
+	defer os.Unsetenv("DB_PATH")
+
+	path, err := LoadDBPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/tmp/test.db" {
+		t.Errorf("LoadDBPath() = %q, want %q", path, "/tmp/test.db")
+	}
+
+	os.Unsetenv("DB_PATH")
+	if _, err := LoadDBPath(); err == nil {
+		t.Error("expected error for missing DB_PATH")
+	}
+}
+
+func TestLoad_APIAddr(t *testing.T) {
+	os.Setenv("TELEGRAM_BOT_API_KEY", "test-token")
+	os.Setenv("DB_PATH", "/tmp/test.db")
+	defer func() {
+		os.Unsetenv("TELEGRAM_BOT_API_KEY")
+		os.Unsetenv("DB_PATH")
+		os.Unsetenv("API_ADDR")
+		os.Unsetenv("API_TOKEN")
+	}()
+
+	tests := []struct {
+		addr    string
+		token   string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"127.0.0.1:8080", "", true},
+		{"localhost:8080", "", true},
+		{":8080", "", true},
+		{"127.0.0.1:8080", "secret", false},
+		{"0.0.0.0:8080", "secret", false},
+	}
+	for _, tt := range tests {
+		os.Setenv("API_ADDR", tt.addr)
+		os.Setenv("API_TOKEN", tt.token)
+
+		cfg, err := Load()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Load() with API_ADDR=%q API_TOKEN=%q error = %v, wantErr %v", tt.addr, tt.token, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (cfg.APIAddr != tt.addr || cfg.APIToken != tt.token) {
+			t.Errorf("APIAddr, APIToken = %q, %q, want %q, %q", cfg.APIAddr, cfg.APIToken, tt.addr, tt.token)
+		}
+	}
+}
+
+func TestLoad_Watchdog(t *testing.T) {
+	os.Setenv("TELEGRAM_BOT_API_KEY", "test-token")
+	os.Setenv("DB_PATH", "/tmp/test.db")
+	defer func() {
+		os.Unsetenv("TELEGRAM_BOT_API_KEY")
+		os.Unsetenv("DB_PATH")
+		os.Unsetenv("WATCHDOG_TIMEOUT_SECONDS")
+		os.Unsetenv("WATCHDOG_EXIT")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.WatchdogTimeout != DefaultWatchdogTimeout || cfg.WatchdogExit {
+		t.Errorf("WatchdogTimeout, WatchdogExit = %v, %v, want defaults", cfg.WatchdogTimeout, cfg.WatchdogExit)
+	}
+
+	os.Setenv("WATCHDOG_TIMEOUT_SECONDS", "120")
+	os.Setenv("WATCHDOG_EXIT", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.WatchdogTimeout != 2*time.Minute || !cfg.WatchdogExit {
+		t.Errorf("WatchdogTimeout, WatchdogExit = %v, %v, want 2m, true", cfg.WatchdogTimeout, cfg.WatchdogExit)
+	}
+
+	os.Setenv("WATCHDOG_TIMEOUT_SECONDS", "5") // shorter than the polling timeout
+	if _, err := Load(); err == nil {
+		t.Error("expected error for a watchdog timeout shorter than the polling timeout")
+	}
+}
+
+func TestLoad_Webhook(t *testing.T) {
+	os.Setenv("TELEGRAM_BOT_API_KEY", "test-token")
+	os.Setenv("DB_PATH", "/tmp/test.db")
+	vars := []string{"WEBHOOK_URL", "WEBHOOK_LISTEN", "WEBHOOK_SECRET", "WEBHOOK_TLS_CERT", "WEBHOOK_TLS_KEY"}
+	defer func() {
+		os.Unsetenv("TELEGRAM_BOT_API_KEY")
+		os.Unsetenv("DB_PATH")
+		for _, v := range vars {
+			os.Unsetenv(v)
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		values  [5]string // in the order of vars
+		wantErr bool
+	}{
+		{"long polling", [5]string{}, false},
+		{"behind proxy", [5]string{"https://bot.example.com/hook", ":8443", "s3cret_token-1", "", ""}, false},
+		{"with tls", [5]string{"https://bot.example.com/hook", ":8443", "s3cret", "cert.pem", "key.pem"}, false},
+		{"plain http url", [5]string{"http://bot.example.com/hook", ":8443", "s3cret", "", ""}, true},
+		{"no listen address", [5]string{"https://bot.example.com/hook", "", "s3cret", "", ""}, true},
+		{"no secret", [5]string{"https://bot.example.com/hook", ":8443", "", "", ""}, true},
+		{"invalid secret", [5]string{"https://bot.example.com/hook", ":8443", "not secret!", "", ""}, true},
+		{"cert without key", [5]string{"https://bot.example.com/hook", ":8443", "s3cret", "cert.pem", ""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, v := range vars {
+				os.Setenv(v, tt.values[i])
+			}
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.WebhookURL != tt.values[0] || cfg.WebhookSecret != tt.values[2] || cfg.WebhookTLSKey != tt.values[4]) {
+				t.Errorf("webhook config = %+v", cfg)
+			}
+		})
+	}
+}
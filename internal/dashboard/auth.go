@@ -0,0 +1,120 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Login data older than this is rejected, so a leaked login link can't be reused later.
+const maxLoginAge = 24 * time.Hour
+
+// sessionLifetime is how long a dashboard login lasts.
+const sessionLifetime = 7 * 24 * time.Hour
+
+var (
+	errLoginHash    = errors.New("login hash mismatch")
+	errLoginExpired = errors.New("login data expired")
+	errBadSession   = errors.New("invalid session")
+)
+
+// LoginUser is a Telegram user logged in with the Login Widget.
+type LoginUser struct {
+	ID        int64
+	FirstName string
+	Username  string
+}
+
+// VerifyLogin checks the data the Telegram Login Widget passes to the auth URL:
+// the hash is an HMAC-SHA256 of the sorted "key=value" lines, keyed with SHA256 of the bot token.
+// See https://core.telegram.org/widgets/login#checking-authorization
+func VerifyLogin(values url.Values, botToken string, now time.Time) (*LoginUser, error) {
+	lines := make([]string, 0, len(values))
+	for key := range values {
+		if key != "hash" {
+			lines = append(lines, key+"="+values.Get(key))
+		}
+	}
+	sort.Strings(lines)
+
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	want := mac.Sum(nil)
+
+	got, err := hex.DecodeString(values.Get("hash"))
+	if err != nil || !hmac.Equal(got, want) {
+		return nil, errLoginHash
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil || now.Sub(time.Unix(authDate, 0)) > maxLoginAge {
+		return nil, errLoginExpired
+	}
+
+	id, err := strconv.ParseInt(values.Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, errLoginHash
+	}
+	return &LoginUser{ID: id, FirstName: values.Get("first_name"), Username: values.Get("username")}, nil
+}
+
+// sessions signs and checks session cookies. A session is "<user id>|<expiry>|<first name>"
+// in base64 followed by its HMAC, so no server-side storage is needed.
+type sessions struct {
+	key []byte
+}
+
+// newSessions derives the signing key from the bot token; it differs from the login widget key.
+func newSessions(botToken string) *sessions {
+	mac := hmac.New(sha256.New, []byte("consigliere dashboard session"))
+	mac.Write([]byte(botToken))
+	return &sessions{key: mac.Sum(nil)}
+}
+
+func (s *sessions) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// encode returns the cookie value for a user session expiring at expires.
+func (s *sessions) encode(user *LoginUser, expires time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(
+		strconv.FormatInt(user.ID, 10) + "|" + strconv.FormatInt(expires.Unix(), 10) + "|" + user.FirstName,
+	))
+	return payload + "." + s.sign(payload)
+}
+
+// decode checks a cookie value and returns its user if the session is valid at now.
+func (s *sessions) decode(value string, now time.Time) (*LoginUser, error) {
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(payload))) {
+		return nil, errBadSession
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errBadSession
+	}
+
+	fields := strings.SplitN(string(data), "|", 3)
+	if len(fields) != 3 {
+		return nil, errBadSession
+	}
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, errBadSession
+	}
+	expires, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || !now.Before(time.Unix(expires, 0)) {
+		return nil, errBadSession
+	}
+	return &LoginUser{ID: id, FirstName: fields[2]}, nil
+}
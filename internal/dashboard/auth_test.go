@@ -0,0 +1,90 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBotToken = "123456:test-token"
+
+// signLogin adds the hash the Telegram Login Widget would compute for the values.
+func signLogin(values url.Values, botToken string) url.Values {
+	var lines []string
+	for key := range values {
+		lines = append(lines, key+"="+values.Get(key))
+	}
+	sort.Strings(lines)
+	secret := sha256.Sum256([]byte(botToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	values.Set("hash", hex.EncodeToString(mac.Sum(nil)))
+	return values
+}
+
+func loginValues(id int64, authDate time.Time) url.Values {
+	return signLogin(url.Values{
+		"id":         {strconv.FormatInt(id, 10)},
+		"first_name": {"Алиса"},
+		"username":   {"alice"},
+		"auth_date":  {strconv.FormatInt(authDate.Unix(), 10)},
+	}, testBotToken)
+}
+
+func TestVerifyLogin(t *testing.T) {
+	now := time.Now()
+
+	user, err := VerifyLogin(loginValues(42, now.Add(-time.Minute)), testBotToken, now)
+	if err != nil {
+		t.Fatalf("VerifyLogin failed: %v", err)
+	}
+	if user.ID != 42 || user.FirstName != "Алиса" || user.Username != "alice" {
+		t.Errorf("user = %+v", user)
+	}
+
+	if _, err := VerifyLogin(loginValues(42, now.Add(-2*maxLoginAge)), testBotToken, now); err != errLoginExpired {
+		t.Errorf("old login error = %v, want %v", err, errLoginExpired)
+	}
+	if _, err := VerifyLogin(loginValues(42, now), "other:token", now); err != errLoginHash {
+		t.Errorf("wrong bot token error = %v, want %v", err, errLoginHash)
+	}
+
+	tampered := loginValues(42, now)
+	tampered.Set("id", "43")
+	if _, err := VerifyLogin(tampered, testBotToken, now); err != errLoginHash {
+		t.Errorf("tampered login error = %v, want %v", err, errLoginHash)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	s := newSessions(testBotToken)
+	now := time.Now()
+	value := s.encode(&LoginUser{ID: 42, FirstName: "Алиса|Боб"}, now.Add(time.Hour))
+
+	user, err := s.decode(value, now)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if user.ID != 42 || user.FirstName != "Алиса|Боб" {
+		t.Errorf("user = %+v", user)
+	}
+
+	if _, err := s.decode(value, now.Add(2*time.Hour)); err != errBadSession {
+		t.Errorf("expired session error = %v, want %v", err, errBadSession)
+	}
+	if _, err := newSessions("other:token").decode(value, now); err != errBadSession {
+		t.Errorf("session signed with another token error = %v, want %v", err, errBadSession)
+	}
+	forged := s.encode(&LoginUser{ID: 1}, now.Add(time.Hour))
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(value, ".")
+	if _, err := s.decode(payload+"."+signature, now); err != errBadSession {
+		t.Errorf("forged session error = %v, want %v", err, errBadSession)
+	}
+}
@@ -0,0 +1,207 @@
+// Package dashboard serves a small server-rendered web dashboard for club admins: upcoming and
+// past events, attendance charts, players with their nicknames and stats. Admins log in with the
+// Telegram Login Widget, verified with the bot token.
+package dashboard
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+const sessionCookie = "consigliere_session"
+
+// Club is a club shown on the dashboard and the Telegram users allowed to see it.
+type Club struct {
+	Club      poll.Club
+	Name      string
+	Admins    []int64
+	TableSize int // players per table, for the summary (0 = a single table)
+}
+
+// Server handles dashboard requests.
+type Server struct {
+	svc         *poll.Service
+	clubs       []Club
+	botToken    string
+	botUsername string
+	sessions    *sessions
+	templates   *template.Template
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// New creates a dashboard for the clubs. botUsername is shown in the login widget,
+// which only works on the domain set for the bot with BotFather's /setdomain.
+func New(svc *poll.Service, clubs []Club, botToken, botUsername string, logger *slog.Logger) (*Server, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		svc:         svc,
+		clubs:       clubs,
+		botToken:    botToken,
+		botUsername: botUsername,
+		sessions:    newSessions(botToken),
+		templates:   tmpl,
+		logger:      logger,
+		now:         time.Now,
+	}, nil
+}
+
+// Handler returns the HTTP handler serving the dashboard.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /login", s.handleLogin)
+	mux.HandleFunc("GET /auth", s.handleAuth)
+	mux.HandleFunc("POST /logout", s.handleLogout)
+	mux.HandleFunc("GET /clubs/{club}", s.handleClub)
+	return mux
+}
+
+// adminClubs returns the clubs the user administers.
+func (s *Server) adminClubs(userID int64) []Club {
+	var clubs []Club
+	for _, c := range s.clubs {
+		if slices.Contains(c.Admins, userID) {
+			clubs = append(clubs, c)
+		}
+	}
+	return clubs
+}
+
+// currentUser returns the logged in user, or nil without a valid session.
+func (s *Server) currentUser(r *http.Request) *LoginUser {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	user, err := s.sessions.decode(cookie.Value, s.now())
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// handleIndex lists the user's clubs, or opens the only one.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	user := s.currentUser(r)
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	clubs := s.adminClubs(user.ID)
+	if len(clubs) == 1 {
+		http.Redirect(w, r, "/clubs/"+string(clubs[0].Club), http.StatusSeeOther)
+		return
+	}
+	s.render(w, http.StatusOK, "index.html", &indexPage{User: user, Clubs: clubs})
+}
+
+// handleLogin shows the Telegram Login Widget, which sends the user to /auth.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if s.currentUser(r) != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	authURL := url.URL{Scheme: requestScheme(r), Host: r.Host, Path: "/auth"}
+	s.render(w, http.StatusOK, "login.html", &loginPage{BotUsername: s.botUsername, AuthURL: authURL.String()})
+}
+
+// handleAuth verifies the login widget data and starts a session for club admins.
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+	user, err := VerifyLogin(r.URL.Query(), s.botToken, s.now())
+	if err != nil {
+		s.logger.Warn("dashboard login rejected", "error", err, "remote_addr", r.RemoteAddr)
+		s.render(w, http.StatusUnauthorized, "error.html", &errorPage{Message: "Не удалось проверить вход через Telegram, попробуйте ещё раз"})
+		return
+	}
+	if len(s.adminClubs(user.ID)) == 0 {
+		s.logger.Warn("dashboard login by non-admin", "user_id", user.ID, "username", user.Username)
+		s.render(w, http.StatusForbidden, "error.html", &errorPage{Message: "Дашборд доступен только администраторам клубов"})
+		return
+	}
+
+	expires := s.now().Add(sessionLifetime)
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    s.sessions.encode(user, expires),
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   requestScheme(r) == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	s.logger.Info("dashboard login", "user_id", user.ID, "username", user.Username)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "", Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// handleClub shows a club's events, attendance and players: /clubs/{club}?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (s *Server) handleClub(w http.ResponseWriter, r *http.Request) {
+	user := s.currentUser(r)
+	if user == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	clubs := s.adminClubs(user.ID)
+	i := slices.IndexFunc(clubs, func(c Club) bool { return string(c.Club) == r.PathValue("club") })
+	if i < 0 {
+		s.render(w, http.StatusNotFound, "error.html", &errorPage{User: user, Message: "Клуб не найден"})
+		return
+	}
+	club := clubs[i]
+
+	period, err := parsePeriod(r.URL.Query().Get("from"), r.URL.Query().Get("to"), s.now())
+	if err != nil {
+		s.render(w, http.StatusBadRequest, "error.html", &errorPage{User: user, Message: "Неверный период, используйте даты ГГГГ-ММ-ДД"})
+		return
+	}
+
+	page, err := s.clubPage(club, period)
+	if err != nil {
+		s.logger.Error("failed to build dashboard page", "error", err, "club", club.Club)
+		s.render(w, http.StatusInternalServerError, "error.html", &errorPage{User: user, Message: "Не удалось загрузить данные"})
+		return
+	}
+	page.User = user
+	s.render(w, http.StatusOK, "club.html", page)
+}
+
+// render executes a page template into a buffer first, so a template error doesn't leave half a page.
+func (s *Server) render(w http.ResponseWriter, status int, name string, data any) {
+	var buf bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		s.logger.Error("failed to render dashboard page", "error", err, "template", name)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = buf.WriteTo(w)
+}
+
+// requestScheme returns the scheme the browser used, trusting a reverse proxy's X-Forwarded-Proto.
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		return "https"
+	}
+	return "http"
+}
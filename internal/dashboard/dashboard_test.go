@@ -0,0 +1,184 @@
+package dashboard
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+	"nuclight.org/consigliere/internal/storage"
+)
+
+const (
+	testAdminID = int64(42)
+	testChatID  = int64(-100123)
+)
+
+func newTestServer(t *testing.T) (*Server, *poll.Service) {
+	t.Helper()
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	svc := poll.NewService(poll.Repositories{
+		Polls:        storage.NewPollRepository(db),
+		Votes:        storage.NewVoteRepository(db),
+		Nicknames:    storage.NewNicknameRepository(db),
+		Attendance:   storage.NewAttendanceRepository(db),
+		Stats:        storage.NewStatsRepository(db),
+		Games:        storage.NewGameRepository(db),
+		Seating:      storage.NewSeatingRepository(db),
+		Payments:     storage.NewPaymentRepository(db),
+		Guests:       storage.NewGuestRepository(db),
+		NickRequests: storage.NewNicknameRequestRepository(db),
+	})
+	clubs := []Club{{Club: poll.ClubVanmo, Name: "VANMO", Admins: []int64{testAdminID}}}
+	srv, err := New(svc, clubs, testBotToken, "test_bot", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return srv, svc
+}
+
+// get sends a GET request with the session cookie, if any.
+func get(h http.Handler, path, session string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if session != "" {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: session})
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLogin(t *testing.T) {
+	srv, _ := newTestServer(t)
+	h := srv.Handler()
+
+	if rec := get(h, "/", ""); rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/login" {
+		t.Errorf("GET / without session = %d %q, want redirect to /login", rec.Code, rec.Header().Get("Location"))
+	}
+	rec := get(h, "/login", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-telegram-login="test_bot"`) {
+		t.Errorf("GET /login = %d, want the login widget", rec.Code)
+	}
+
+	if rec := get(h, "/auth?"+loginValues(7, time.Now()).Encode(), ""); rec.Code != http.StatusForbidden {
+		t.Errorf("auth of non-admin = %d, want 403", rec.Code)
+	}
+	bad := loginValues(testAdminID, time.Now())
+	bad.Set("hash", strings.Repeat("0", 64))
+	if rec := get(h, "/auth?"+bad.Encode(), ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("auth with bad hash = %d, want 401", rec.Code)
+	}
+
+	rec = get(h, "/auth?"+loginValues(testAdminID, time.Now()).Encode(), "")
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("auth of admin = %d, want redirect", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sessionCookie || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %+v", cookies)
+	}
+
+	// The only club opens right away
+	if rec := get(h, "/", cookies[0].Value); rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/clubs/vanmo" {
+		t.Errorf("GET / = %d %q, want redirect to the club", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestClubPage(t *testing.T) {
+	srv, svc := newTestServer(t)
+	h := srv.Handler()
+	session := srv.sessions.encode(&LoginUser{ID: testAdminID, FirstName: "Алиса"}, time.Now().Add(time.Hour))
+
+	// Alice added by nickname for an earlier event counts as the same player
+	earlier, err := svc.CreatePoll(testChatID, time.Now().AddDate(0, 0, -14), poll.ClubVanmo, 20)
+	if err != nil {
+		t.Fatalf("CreatePoll failed: %v", err)
+	}
+	manual := &poll.Vote{PollID: earlier.Poll.ID, TgUserID: poll.ManualUserID("Секртис"), TgFirstName: "Секртис", TgOptionIndex: int(poll.OptionComeAt19), IsManual: true, VotedAt: time.Now()}
+	if err := svc.RecordVote(manual); err != nil {
+		t.Fatalf("RecordVote failed: %v", err)
+	}
+
+	past, err := svc.CreatePoll(testChatID, time.Now().AddDate(0, 0, -7), poll.ClubVanmo, 20)
+	if err != nil {
+		t.Fatalf("CreatePoll failed: %v", err)
+	}
+	votes := []*poll.Vote{
+		{PollID: past.Poll.ID, TgUserID: 1, TgUsername: "alice", TgFirstName: "Alice", TgOptionIndex: int(poll.OptionComeAt19)},
+		{PollID: past.Poll.ID, TgUserID: 2, TgUsername: "bob", TgFirstName: "Bob", TgOptionIndex: int(poll.OptionComeAt21OrLater)},
+		{PollID: past.Poll.ID, TgUserID: 3, TgFirstName: "Carol", TgOptionIndex: int(poll.OptionNotComing)},
+	}
+	for _, v := range votes {
+		v.VotedAt = time.Now()
+		if err := svc.RecordVote(v); err != nil {
+			t.Fatalf("RecordVote failed: %v", err)
+		}
+	}
+	if _, err := svc.CreateNickname(poll.ClubVanmo, nil, ptr("alice"), "Секртис", "female", 0); err != nil {
+		t.Fatalf("CreateNickname failed: %v", err)
+	}
+	if _, err := svc.CreatePoll(testChatID, time.Now().AddDate(0, 0, 3), poll.ClubVanmo, 20); err != nil {
+		t.Fatalf("CreatePoll failed: %v", err)
+	}
+
+	rec := get(h, "/clubs/vanmo", session)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET club = %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"VANMO", "Секртис", "@bob", "<rect", time.Now().AddDate(0, 0, 3).Format("02.01.2006")} {
+		if !strings.Contains(body, want) {
+			t.Errorf("club page doesn't contain %q", want)
+		}
+	}
+
+	page, err := srv.clubPage(srv.clubs[0], poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("clubPage failed: %v", err)
+	}
+	if len(page.Past) != 2 || page.Past[0].Attending != 2 || page.Past[0].Late != 1 || page.Past[0].NotComing != 1 {
+		t.Errorf("past events = %+v", page.Past)
+	}
+	if len(page.Upcoming) != 1 || len(page.Players) != 2 {
+		t.Fatalf("upcoming = %+v, players = %+v", page.Upcoming, page.Players)
+	}
+	if alice := page.Players[0]; !strings.Contains(alice.Name, "Секртис") || alice.Username != "alice" || alice.Stats.EventsPlayed != 2 || alice.Stats.Debt != 40 {
+		t.Errorf("players[0] = %+v, want Alice with both events", alice)
+	}
+
+	if rec := get(h, "/clubs/tbilissimo", session); rec.Code != http.StatusNotFound {
+		t.Errorf("GET club of another admin = %d, want 404", rec.Code)
+	}
+	if rec := get(h, "/clubs/vanmo?from=yesterday", session); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET club with bad period = %d, want 400", rec.Code)
+	}
+}
+
+func TestAttendanceChart(t *testing.T) {
+	c := attendanceChart([]eventRow{
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Attending: 10},
+		{Date: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), Attending: 20, Cancelled: true},
+		{Date: time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC), Attending: 5},
+	})
+	if len(c.Bars) != 2 {
+		t.Fatalf("bars = %+v, want cancelled events skipped", c.Bars)
+	}
+	if c.Bars[0].Height != chartHeight || c.Bars[1].Height != chartHeight/2 || c.Bars[1].Label != "08.03" {
+		t.Errorf("bars = %+v", c.Bars)
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,260 @@
+package dashboard
+
+import (
+	"cmp"
+	"html/template"
+	"slices"
+	"time"
+
+	"nuclight.org/consigliere/internal/export"
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// defaultPeriodMonths is how far back the club page looks without a from date.
+const defaultPeriodMonths = 3
+
+// maxPlayers limits the player table; clubs have far fewer regulars.
+const maxPlayers = 500
+
+var templateFuncs = template.FuncMap{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format("02.01.2006")
+	},
+	"weekday": func(d time.Weekday) string {
+		return weekdays[d]
+	},
+	"gender": func(g string) string {
+		switch g {
+		case "male":
+			return "м"
+		case "female":
+			return "ж"
+		}
+		return ""
+	},
+}
+
+var weekdays = [...]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"}
+
+type loginPage struct {
+	BotUsername string
+	AuthURL     string
+}
+
+type errorPage struct {
+	User    *LoginUser
+	Message string
+}
+
+type indexPage struct {
+	User  *LoginUser
+	Clubs []Club
+}
+
+type clubPage struct {
+	User      *LoginUser
+	Club      Club
+	From, To  string // period form values
+	Summary   *poll.ClubSummary
+	Upcoming  []eventRow
+	Past      []eventRow // newest first
+	Chart     chart
+	Players   []playerRow
+	Nicknames []*poll.Nickname
+}
+
+// eventRow is an event with its vote counts.
+type eventRow struct {
+	ID        int64
+	Date      time.Time
+	StartTime string
+	Price     int
+	Active    bool
+	Cancelled bool
+	Attending int // attending votes plus their guests
+	Late      int // attending at 21:00 or later
+	Undecided int
+	NotComing int
+}
+
+// playerRow is a player who played in the period, with their stats.
+type playerRow struct {
+	Name     string
+	Username string
+	Stats    poll.PlayerStats
+}
+
+// chart is an SVG bar chart of attendance per event.
+type chart struct {
+	Width, Height int
+	Bars          []chartBar
+}
+
+type chartBar struct {
+	X, Y, Width, Height int
+	Label               string
+	Value               int
+}
+
+// Chart geometry in SVG units
+const (
+	chartHeight   = 160
+	chartBarWidth = 22
+	chartBarGap   = 6
+	chartLabels   = 16 // space under the bars for dates
+)
+
+// parsePeriod parses the period form, defaulting to the last few months.
+func parsePeriod(from, to string, now time.Time) (poll.StatsPeriod, error) {
+	if from == "" {
+		from = now.AddDate(0, -defaultPeriodMonths, 0).Format(time.DateOnly)
+	}
+	return export.ParsePeriod(from, to)
+}
+
+// clubPage collects the club's events in the period and upcoming ones, attendance and players.
+func (s *Server) clubPage(club Club, period poll.StatsPeriod) (*clubPage, error) {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	page := &clubPage{Club: club, From: period.From.Format(time.DateOnly)}
+	if !period.To.IsZero() {
+		page.To = period.To.AddDate(0, 0, -1).Format(time.DateOnly) // the form shows the inclusive end
+	}
+
+	var err error
+	if page.Upcoming, err = s.events(club.Club, poll.StatsPeriod{From: today}); err != nil {
+		return nil, err
+	}
+	past, err := s.events(club.Club, period)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range past {
+		if e.Date.Before(today) {
+			page.Past = append(page.Past, e)
+		}
+	}
+	page.Chart = attendanceChart(page.Past)
+	slices.Reverse(page.Past)
+
+	if page.Nicknames, err = s.svc.ListNicknames(club.Club); err != nil {
+		return nil, err
+	}
+	if page.Summary, err = s.svc.GetClubSummary(club.Club, period, club.TableSize); err != nil {
+		return nil, err
+	}
+	if page.Players, err = s.players(club.Club, period); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// events returns the club's events in the period with their vote counts.
+func (s *Server) events(club poll.Club, period poll.StatsPeriod) ([]eventRow, error) {
+	polls, err := s.svc.ListPolls(club, period)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.svc.GetPollVotes(club, period)
+	if err != nil {
+		return nil, err
+	}
+	byPoll := make(map[int64][]*poll.PollVote)
+	for _, v := range votes {
+		byPoll[v.PollID] = append(byPoll[v.PollID], v)
+	}
+
+	rows := make([]eventRow, len(polls))
+	for i, p := range polls {
+		rows[i] = newEventRow(p, byPoll[p.ID])
+	}
+	return rows, nil
+}
+
+func newEventRow(p *poll.Poll, votes []*poll.PollVote) eventRow {
+	row := eventRow{
+		ID:        p.ID,
+		Date:      p.EventDate,
+		StartTime: p.StartTime,
+		Price:     p.Price,
+		Active:    p.IsActive,
+		Cancelled: p.IsCancelled(),
+	}
+	for _, v := range votes {
+		switch kind := poll.OptionKind(v.TgOptionIndex); {
+		case kind.IsAttending():
+			row.Attending += 1 + v.Guests
+			if kind == poll.OptionComeAt21OrLater {
+				row.Late++
+			}
+		case kind == poll.OptionDecideLater:
+			row.Undecided++
+		case kind == poll.OptionNotComing:
+			row.NotComing++
+		}
+	}
+	return row
+}
+
+// attendanceChart draws a bar per held event, oldest first; cancelled events are skipped.
+func attendanceChart(events []eventRow) chart {
+	var held []eventRow
+	for _, e := range events {
+		if !e.Cancelled {
+			held = append(held, e)
+		}
+	}
+
+	c := chart{Width: max(len(held), 1) * (chartBarWidth + chartBarGap), Height: chartHeight + chartLabels}
+	if len(held) == 0 {
+		return c
+	}
+	top := slices.MaxFunc(held, func(a, b eventRow) int { return cmp.Compare(a.Attending, b.Attending) }).Attending
+	for i, e := range held {
+		height := 0
+		if top > 0 {
+			height = e.Attending * chartHeight / top
+		}
+		c.Bars = append(c.Bars, chartBar{
+			X:      i * (chartBarWidth + chartBarGap),
+			Y:      chartHeight - height,
+			Width:  chartBarWidth,
+			Height: height,
+			Label:  e.Date.Format("02.01"),
+			Value:  e.Attending,
+		})
+	}
+	return c
+}
+
+// players returns the players who played in the period, most active first,
+// named by their game nickname where there is one.
+func (s *Server) players(club poll.Club, period poll.StatsPeriod) ([]playerRow, error) {
+	entries, err := s.svc.GetLeaderboard(club, period, maxPlayers)
+	if err != nil {
+		return nil, err
+	}
+
+	votes := make([]*poll.Vote, len(entries))
+	for i, e := range entries {
+		votes[i] = &poll.Vote{TgUserID: e.TgUserID, TgUsername: e.TgUsername}
+	}
+	cache, err := s.svc.NewNicknameCacheFromVotes(club, votes)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]playerRow, len(entries))
+	for i, e := range entries {
+		name := cache.GetDisplayNick(e.TgUserID, e.TgUsername)
+		if name == "" {
+			name = e.TgFirstName
+		}
+		rows[i] = playerRow{Name: name, Username: e.TgUsername, Stats: e.PlayerStats}
+	}
+	return rows, nil
+}
@@ -0,0 +1,264 @@
+// Package export serializes a club's polls, votes and nicknames to CSV or JSON files
+// for analysis outside the bot. It is shared by the /export command and the CLI.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Format is an export file format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a format name, case-insensitively.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatCSV, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown export format: %s", s)
+}
+
+// File is a named export file.
+type File struct {
+	Name string
+	MIME string
+	Data []byte
+}
+
+const dateLayout = "2006-01-02"
+
+// optionNames are stable option names for exported votes.
+var optionNames = map[poll.OptionKind]string{
+	poll.OptionComeAt19:        "19:00",
+	poll.OptionComeAt20:        "20:00",
+	poll.OptionComeAt21OrLater: "21:00+",
+	poll.OptionDecideLater:     "later",
+	poll.OptionNotComing:       "not_coming",
+}
+
+// OptionName returns the stable name of a vote option, e.g. "21:00+" or "not_coming".
+func OptionName(kind poll.OptionKind) string {
+	return optionNames[kind]
+}
+
+// Files serializes the export: one JSON file, or polls, votes and nicknames as three CSV files.
+// File names start with the club, e.g. vanmo-votes.csv.
+func Files(e *poll.Export, format Format) ([]File, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(newJSONExport(e), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal export: %w", err)
+		}
+		return []File{{Name: fileName(e, "export.json"), MIME: "application/json", Data: data}}, nil
+	case FormatCSV:
+		return csvFiles(e)
+	}
+	return nil, fmt.Errorf("unknown export format: %s", format)
+}
+
+func fileName(e *poll.Export, name string) string {
+	return string(e.Club) + "-" + name
+}
+
+// jsonExport is the JSON layout of an export.
+type jsonExport struct {
+	Club      string         `json:"club"`
+	From      string         `json:"from,omitempty"`
+	To        string         `json:"to,omitempty"` // exclusive
+	Polls     []jsonPoll     `json:"polls"`
+	Nicknames []jsonNickname `json:"nicknames"`
+}
+
+type jsonPoll struct {
+	ID          int64      `json:"id"`
+	ChatID      int64      `json:"chat_id"`
+	EventDate   string     `json:"event_date"`
+	IsActive    bool       `json:"is_active"`
+	IsCancelled bool       `json:"is_cancelled"`
+	StartTime   string     `json:"start_time,omitempty"`
+	Price       int        `json:"price,omitempty"`
+	JudgeUserID int64      `json:"judge_user_id,omitempty"`
+	JudgeName   string     `json:"judge_name,omitempty"`
+	Votes       []jsonVote `json:"votes"`
+}
+
+type jsonVote struct {
+	TgUserID    int64     `json:"tg_user_id"`
+	TgUsername  string    `json:"tg_username,omitempty"`
+	TgFirstName string    `json:"tg_first_name"`
+	GameNick    string    `json:"game_nick,omitempty"`
+	Gender      string    `json:"gender,omitempty"`
+	Option      string    `json:"option"`
+	Guests      int       `json:"guests,omitempty"`
+	IsManual    bool      `json:"is_manual"`
+	ActorUserID int64     `json:"actor_user_id,omitempty"`
+	VotedAt     time.Time `json:"voted_at"`
+}
+
+type jsonNickname struct {
+	GameNick   string `json:"game_nick"`
+	Gender     string `json:"gender,omitempty"`
+	TgUserID   int64  `json:"tg_user_id,omitempty"`
+	TgUsername string `json:"tg_username,omitempty"`
+	Shared     bool   `json:"shared"`
+}
+
+func newJSONExport(e *poll.Export) jsonExport {
+	out := jsonExport{
+		Club:      string(e.Club),
+		From:      formatDate(e.Period.From),
+		To:        formatDate(e.Period.To),
+		Polls:     make([]jsonPoll, 0, len(e.Polls)),
+		Nicknames: make([]jsonNickname, 0, len(e.Nicknames)),
+	}
+	for _, p := range e.Polls {
+		jp := jsonPoll{
+			ID:          p.ID,
+			ChatID:      p.TgChatID,
+			EventDate:   p.EventDate.Format(dateLayout),
+			IsActive:    p.IsActive,
+			IsCancelled: p.IsCancelled(),
+			StartTime:   p.StartTime,
+			Price:       p.Price,
+			JudgeUserID: p.JudgeUserID,
+			JudgeName:   p.JudgeName,
+			Votes:       make([]jsonVote, 0, len(p.Votes)),
+		}
+		for _, v := range p.Votes {
+			jp.Votes = append(jp.Votes, jsonVote{
+				TgUserID:    v.TgUserID,
+				TgUsername:  v.TgUsername,
+				TgFirstName: v.TgFirstName,
+				GameNick:    v.Nickname.Nick,
+				Gender:      v.Nickname.Gender,
+				Option:      optionNames[v.OptionKind()],
+				Guests:      v.Guests,
+				IsManual:    v.IsManual,
+				ActorUserID: v.ActorUserID,
+				VotedAt:     v.VotedAt,
+			})
+		}
+		out.Polls = append(out.Polls, jp)
+	}
+	for _, n := range e.Nicknames {
+		out.Nicknames = append(out.Nicknames, jsonNickname{
+			GameNick:   n.Nick,
+			Gender:     n.Gender,
+			TgUserID:   n.TgUserID,
+			TgUsername: n.TgUsername,
+			Shared:     n.Shared(),
+		})
+	}
+	return out
+}
+
+func csvFiles(e *poll.Export) ([]File, error) {
+	polls := [][]string{{"id", "chat_id", "event_date", "is_active", "is_cancelled", "start_time", "price", "judge_user_id", "judge_name", "votes"}}
+	votes := [][]string{{"poll_id", "event_date", "tg_user_id", "tg_username", "tg_first_name", "game_nick", "gender", "option", "guests", "is_manual", "actor_user_id", "voted_at"}}
+	for _, p := range e.Polls {
+		eventDate := p.EventDate.Format(dateLayout)
+		polls = append(polls, []string{
+			formatInt(p.ID), formatInt(p.TgChatID), eventDate,
+			strconv.FormatBool(p.IsActive), strconv.FormatBool(p.IsCancelled()),
+			p.StartTime, optionalInt(int64(p.Price)), optionalInt(p.JudgeUserID), csvText(p.JudgeName),
+			strconv.Itoa(len(p.Votes)),
+		})
+		for _, v := range p.Votes {
+			votes = append(votes, []string{
+				formatInt(p.ID), eventDate, formatInt(v.TgUserID), csvText(v.TgUsername), csvText(v.TgFirstName),
+				csvText(v.Nickname.Nick), v.Nickname.Gender, optionNames[v.OptionKind()], strconv.Itoa(v.Guests),
+				strconv.FormatBool(v.IsManual), optionalInt(v.ActorUserID), v.VotedAt.Format(time.RFC3339),
+			})
+		}
+	}
+
+	nicknames := [][]string{{"game_nick", "gender", "tg_user_id", "tg_username", "shared"}}
+	for _, n := range e.Nicknames {
+		nicknames = append(nicknames, []string{
+			csvText(n.Nick), n.Gender, optionalInt(n.TgUserID), csvText(n.TgUsername), strconv.FormatBool(n.Shared()),
+		})
+	}
+
+	var files []File
+	for _, table := range []struct {
+		name    string
+		records [][]string
+	}{
+		{"polls.csv", polls},
+		{"votes.csv", votes},
+		{"nicknames.csv", nicknames},
+	} {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(table.records); err != nil {
+			return nil, fmt.Errorf("write %s: %w", table.name, err)
+		}
+		files = append(files, File{Name: fileName(e, table.name), MIME: "text/csv", Data: buf.Bytes()})
+	}
+	return files, nil
+}
+
+// csvText escapes text entered by players so that spreadsheets do not evaluate it
+// as a formula: a cell starting with =, +, - or @ is prefixed with an apostrophe.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// optionalInt formats n, leaving zero (not set) empty.
+func optionalInt(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return formatInt(n)
+}
+
+// ParsePeriod parses an export date range given as YYYY-MM-DD dates, both inclusive.
+// Empty strings leave the range open on that side.
+func ParsePeriod(from, to string) (poll.StatsPeriod, error) {
+	var period poll.StatsPeriod
+	var err error
+	if from != "" {
+		if period.From, err = time.Parse(dateLayout, from); err != nil {
+			return poll.StatsPeriod{}, fmt.Errorf("invalid from date: %s", from)
+		}
+	}
+	if to != "" {
+		end, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return poll.StatsPeriod{}, fmt.Errorf("invalid to date: %s", to)
+		}
+		period.To = end.AddDate(0, 0, 1) // StatsPeriod.To is exclusive
+	}
+	if !period.From.IsZero() && !period.To.IsZero() && !period.From.Before(period.To) {
+		return poll.StatsPeriod{}, fmt.Errorf("from date %s is after to date %s", from, to)
+	}
+	return period, nil
+}
@@ -0,0 +1,149 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func testExport() *poll.Export {
+	votedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &poll.Export{
+		Club:   poll.ClubVanmo,
+		Period: poll.StatsPeriod{From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Polls: []*poll.ExportedPoll{{
+			Poll: &poll.Poll{ID: 7, TgChatID: -100, EventDate: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), Price: 20, TgCancelMessageID: 55},
+			Votes: []*poll.ExportedVote{
+				{Vote: &poll.Vote{TgUserID: 1, TgUsername: "ivan", TgFirstName: "Ivan, Jr.", TgOptionIndex: 1, Guests: 2, VotedAt: votedAt},
+					Nickname: poll.NicknameInfo{Nick: "Кот", Gender: "male"}},
+				{Vote: &poll.Vote{TgUserID: -5, TgFirstName: "Лиса", TgOptionIndex: 4, IsManual: true, ActorUserID: 99, VotedAt: votedAt}},
+			},
+		}},
+		Nicknames: []*poll.Nickname{
+			{Club: poll.ClubVanmo, TgUserID: 1, TgUsername: "ivan", NicknameInfo: poll.NicknameInfo{Nick: "Кот", Gender: "male"}},
+			{Club: poll.SharedNicknames, TgUsername: "fox", NicknameInfo: poll.NicknameInfo{Nick: "Лиса"}},
+		},
+	}
+}
+
+func TestFiles_CSV(t *testing.T) {
+	files, err := Files(testExport(), FormatCSV)
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(files))
+	}
+
+	want := map[string]string{
+		"vanmo-polls.csv": "id,chat_id,event_date,is_active,is_cancelled,start_time,price,judge_user_id,judge_name,votes\n" +
+			"7,-100,2025-03-03,false,true,,20,,,2\n",
+		"vanmo-votes.csv": "poll_id,event_date,tg_user_id,tg_username,tg_first_name,game_nick,gender,option,guests,is_manual,actor_user_id,voted_at\n" +
+			"7,2025-03-03,1,ivan,\"Ivan, Jr.\",Кот,male,20:00,2,false,,2025-03-01T12:00:00Z\n" +
+			"7,2025-03-03,-5,,Лиса,,,not_coming,0,true,99,2025-03-01T12:00:00Z\n",
+		"vanmo-nicknames.csv": "game_nick,gender,tg_user_id,tg_username,shared\n" +
+			"Кот,male,1,ivan,false\n" +
+			"Лиса,,,fox,true\n",
+	}
+	for _, f := range files {
+		if f.MIME != "text/csv" {
+			t.Errorf("%s: MIME = %q", f.Name, f.MIME)
+		}
+		if got := string(f.Data); got != want[f.Name] {
+			t.Errorf("%s:\n%s\nwant:\n%s", f.Name, got, want[f.Name])
+		}
+	}
+}
+
+func TestFiles_CSVEscapesFormulas(t *testing.T) {
+	e := &poll.Export{
+		Club: poll.ClubVanmo,
+		Polls: []*poll.ExportedPoll{{
+			Poll: &poll.Poll{ID: 7, TgChatID: -100, EventDate: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), JudgeName: "+Судья"},
+			Votes: []*poll.ExportedVote{
+				{Vote: &poll.Vote{TgUserID: 1, TgUsername: "@ivan", TgFirstName: "=HYPERLINK(\"x\")", TgOptionIndex: 1},
+					Nickname: poll.NicknameInfo{Nick: "-Кот"}},
+			},
+		}},
+		Nicknames: []*poll.Nickname{
+			{Club: poll.ClubVanmo, TgUserID: 1, TgUsername: "@ivan", NicknameInfo: poll.NicknameInfo{Nick: "-Кот"}},
+		},
+	}
+	files, err := Files(e, FormatCSV)
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+
+	want := map[string]string{
+		"vanmo-polls.csv":     "7,-100,2025-03-03,false,false,,,,'+Судья,1\n",
+		"vanmo-votes.csv":     "7,2025-03-03,1,'@ivan,\"'=HYPERLINK(\"\"x\"\")\",'-Кот,,20:00,0,false,,0001-01-01T00:00:00Z\n",
+		"vanmo-nicknames.csv": "'-Кот,,1,'@ivan,false\n",
+	}
+	for _, f := range files {
+		_, row, _ := strings.Cut(string(f.Data), "\n")
+		if row != want[f.Name] {
+			t.Errorf("%s: row = %q, want %q", f.Name, row, want[f.Name])
+		}
+	}
+}
+
+func TestFiles_JSON(t *testing.T) {
+	files, err := Files(testExport(), FormatJSON)
+	if err != nil {
+		t.Fatalf("Files failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "vanmo-export.json" {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+
+	var got jsonExport
+	if err := json.Unmarshal(files[0].Data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Club != "vanmo" || got.From != "2025-03-01" || got.To != "" {
+		t.Errorf("unexpected header: %+v", got)
+	}
+	if len(got.Polls) != 1 || len(got.Polls[0].Votes) != 2 || !got.Polls[0].IsCancelled {
+		t.Fatalf("unexpected polls: %+v", got.Polls)
+	}
+	if v := got.Polls[0].Votes[0]; v.GameNick != "Кот" || v.Option != "20:00" || v.Guests != 2 {
+		t.Errorf("unexpected vote: %+v", v)
+	}
+	if len(got.Nicknames) != 2 || !got.Nicknames[1].Shared {
+		t.Errorf("unexpected nicknames: %+v", got.Nicknames)
+	}
+	if !strings.Contains(string(files[0].Data), `"polls": [`) {
+		t.Error("expected indented JSON")
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	period, err := ParsePeriod("2025-03-01", "2025-03-31")
+	if err != nil {
+		t.Fatalf("ParsePeriod failed: %v", err)
+	}
+	if got := period.To.Format(dateLayout); got != "2025-04-01" {
+		t.Errorf("To = %s, want the day after the inclusive end", got)
+	}
+
+	if period, err := ParsePeriod("", ""); err != nil || !period.From.IsZero() || !period.To.IsZero() {
+		t.Errorf("ParsePeriod of open range = %+v, %v", period, err)
+	}
+	for _, tt := range [][2]string{{"2025-13-01", ""}, {"", "tomorrow"}, {"2025-03-02", "2025-03-01"}} {
+		if _, err := ParsePeriod(tt[0], tt[1]); err == nil {
+			t.Errorf("ParsePeriod(%q, %q) expected error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("JSON"); err != nil || f != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,51 @@
+// Package health serves liveness and readiness endpoints built from named checks.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// checkTimeout bounds a whole round of checks, so a locked database fails the check instead of hanging it.
+const checkTimeout = 5 * time.Second
+
+// Check is a named health check. Run returns nil when healthy.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// report is the JSON response: "ok" or the error for every check.
+type report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Handler runs the checks on every request and responds 200 if all pass, 503 otherwise.
+func Handler(checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		rep := report{Status: "ok", Checks: make(map[string]string, len(checks))}
+		for _, check := range checks {
+			if err := check.Run(ctx); err != nil {
+				rep.Status = "fail"
+				rep.Checks[check.Name] = err.Error()
+			} else {
+				rep.Checks[check.Name] = "ok"
+			}
+		}
+
+		status := http.StatusOK
+		if rep.Status != "ok" {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(rep)
+	})
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	ok := Check{Name: "db", Run: func(context.Context) error { return nil }}
+	failing := Check{Name: "updates", Run: func(context.Context) error { return errors.New("stuck") }}
+
+	tests := []struct {
+		name   string
+		checks []Check
+		want   int
+		report report
+	}{
+		{"healthy", []Check{ok}, http.StatusOK, report{Status: "ok", Checks: map[string]string{"db": "ok"}}},
+		{"failing", []Check{ok, failing}, http.StatusServiceUnavailable, report{Status: "fail", Checks: map[string]string{"db": "ok", "updates": "stuck"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler(tt.checks...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+
+			var got report
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode %q: %v", rec.Body.String(), err)
+			}
+			if got.Status != tt.report.Status || len(got.Checks) != len(tt.report.Checks) {
+				t.Fatalf("report = %+v, want %+v", got, tt.report)
+			}
+			for name, result := range tt.report.Checks {
+				if got.Checks[name] != result {
+					t.Errorf("check %s = %q, want %q", name, got.Checks[name], result)
+				}
+			}
+		})
+	}
+}
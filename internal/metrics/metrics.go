@@ -0,0 +1,90 @@
+// Package metrics defines the bot's Prometheus metrics and serves them for scraping.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "consigliere"
+
+// Command outcomes, as decided by the bot's HandleErrors middleware
+const (
+	OutcomeOK        = "ok"
+	OutcomeUserError = "user_error" // a user mistake, shown to the user and not logged
+	OutcomeError     = "error"      // an internal error, logged
+)
+
+// Vote sources
+const (
+	VoteSourcePlayer = "player" // the player's own vote: native poll, buttons or a chat reply
+	VoteSourceAdmin  = "admin"  // entered by an admin with /vote
+)
+
+// registry holds only the bot's metrics plus the standard Go and process ones,
+// so nothing registered globally by dependencies leaks into /metrics.
+var registry = prometheus.NewRegistry()
+
+var factory = promauto.With(registry)
+
+var (
+	// Commands counts handled commands and callbacks by name and outcome.
+	Commands = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_total",
+		Help:      "Commands and button callbacks handled, by name and outcome.",
+	}, []string{"command", "outcome"})
+
+	// Votes counts recorded votes by source.
+	Votes = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_total",
+		Help:      "Votes recorded, by source.",
+	}, []string{"source"})
+
+	// TelegramSendDuration observes every Telegram send attempt in SendWithRetry.
+	TelegramSendDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "telegram_send_duration_seconds",
+		Help:      "Latency of Telegram send attempts.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// TelegramSendErrors counts failed send attempts; final is "true" when retries ran out.
+	TelegramSendErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_send_errors_total",
+		Help:      "Failed Telegram send attempts; final=\"true\" when the message was given up on.",
+	}, []string{"final"})
+
+	// RateLimited counts requests dropped by the per-user rate limit.
+	RateLimited = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requests dropped by the per-user rate limit.",
+	})
+
+	// DBQueryDuration observes SQLite statements by operation: exec, query, query_row or commit.
+	DBQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sqlite_query_duration_seconds",
+		Help:      "Latency of SQLite statements, by operation.",
+		Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"op"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
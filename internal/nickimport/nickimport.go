@@ -0,0 +1,179 @@
+// Package nickimport creates nicknames in bulk from CSV files whose rows follow the /nick arguments.
+// It is shared by the /import command and the CLI.
+package nickimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// Row is a valid row of a nickname import file.
+type Row struct {
+	Line       int     // line in the file, for reports
+	TgUserID   *int64  // set if the identifier is numeric
+	TgUsername *string // without @, set if the identifier is a username
+	Nickname   string
+	Gender     string // "male", "female" or empty, as stored in the database
+	Shared     bool   // nickname is visible in every club
+}
+
+// Identity returns the Telegram identity of the row as written in /nick: @username or user ID.
+func (r Row) Identity() string {
+	if r.TgUsername != nil {
+		return "@" + *r.TgUsername
+	}
+	return strconv.FormatInt(*r.TgUserID, 10)
+}
+
+// RowError is a row of a nickname import file that could not be parsed.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Report is the outcome of Import.
+type Report struct {
+	Created    []Row
+	Duplicates []Row // nickname already taken
+	Invalid    []RowError
+}
+
+// NicknameCreator creates a nickname unless it is already taken; *poll.Service implements it.
+type NicknameCreator interface {
+	CreateNickname(club poll.Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error)
+}
+
+// ValidateFunc validates the columns of a row as /nick arguments: identifier, nickname and
+// the optional gender and shared marker. It returns the row without its line. The /nick parser
+// lives in the bot package, which provides it as bot.ValidateImportRow.
+type ValidateFunc func(tokens []string) (Row, error)
+
+// headers are nickname column titles; a first row with one of them is a header.
+var headers = []string{"nick", "nickname", "game_nick", "ник", "игровой ник"}
+
+// Parse parses a nickname import file. Each row is the same as /nick arguments, one per column:
+//
+//	@username or user ID, nickname[, gender][, shared]
+//
+// The gender and shared columns may be empty. Both comma and semicolon (as saved by
+// spreadsheets in many locales) separate columns; an optional header row is skipped.
+// Rows that validate rejects are returned as invalid; the error is only for unreadable files.
+func Parse(data []byte, validate ValidateFunc) ([]Row, []RowError, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // spreadsheets add a UTF-8 BOM
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Contains(firstLine, []byte(";")) && !bytes.Contains(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	var rows []Row
+	var invalid []RowError
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			invalid = append(invalid, RowError{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+
+		if first && isHeader(record) {
+			continue
+		}
+		if isBlank(record) {
+			continue
+		}
+
+		row, err := parseRecord(record, validate)
+		if err != nil {
+			invalid = append(invalid, RowError{Line: line, Err: err})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	return rows, invalid, nil
+}
+
+// parseRecord validates a CSV row with validate: the columns are passed as /nick arguments,
+// one per column, without the empty optional ones.
+func parseRecord(record []string, validate ValidateFunc) (Row, error) {
+	if len(record) < 2 {
+		return Row{}, errors.New("not enough columns")
+	}
+	var tokens []string
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if field == "" && i >= 2 {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return validate(tokens)
+}
+
+func isHeader(record []string) bool {
+	if len(record) < 2 {
+		return false
+	}
+	title := strings.ToLower(strings.TrimSpace(record[1]))
+	for _, header := range headers {
+		if title == header {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Import creates the nicknames of an import file in the club's namespace,
+// or in the shared one for rows marked shared. Taken nicknames are reported as duplicates,
+// so a file can be imported again after a failure. Rows are validated with validate, see Parse.
+func Import(svc NicknameCreator, club poll.Club, data []byte, actorUserID int64, validate ValidateFunc) (*Report, error) {
+	rows, invalid, err := Parse(data, validate)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Invalid: invalid}
+	for _, row := range rows {
+		rowClub := club
+		if row.Shared {
+			rowClub = poll.SharedNicknames
+		}
+		created, err := svc.CreateNickname(rowClub, row.TgUserID, row.TgUsername, row.Nickname, row.Gender, actorUserID)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		if created {
+			report.Created = append(report.Created, row)
+		} else {
+			report.Duplicates = append(report.Duplicates, row)
+		}
+	}
+	return report, nil
+}
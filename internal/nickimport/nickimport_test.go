@@ -0,0 +1,133 @@
+package nickimport
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// validateStub accepts rows of a @username and a nickname, shared if the last column says so.
+func validateStub(tokens []string) (Row, error) {
+	if len(tokens) < 2 || !strings.HasPrefix(tokens[0], "@") || tokens[1] == "" {
+		return Row{}, errors.New("invalid row")
+	}
+	username := strings.TrimPrefix(tokens[0], "@")
+	return Row{TgUsername: &username, Nickname: tokens[1], Shared: tokens[len(tokens)-1] == "shared"}, nil
+}
+
+func TestParse(t *testing.T) {
+	input := "\ufeffusername,nick,gender\n" +
+		"@user1,Секртис,м\n" +
+		"@user2, \"Мадам Жу\",ж,shared\n" +
+		"\n" +
+		"@user3,,м\n" +
+		"@user4\n" +
+		"@user5,Пёс,,shared\n"
+
+	var got [][]string
+	rows, invalid, err := Parse([]byte(input), func(tokens []string) (Row, error) {
+		got = append(got, tokens)
+		return validateStub(tokens)
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// The header and blank rows are skipped, empty optional columns are dropped
+	wantTokens := [][]string{
+		{"@user1", "Секртис", "м"},
+		{"@user2", "Мадам Жу", "ж", "shared"},
+		{"@user3", "", "м"},
+		{"@user5", "Пёс", "shared"},
+	}
+	if !slices.EqualFunc(got, wantTokens, slices.Equal) {
+		t.Errorf("validated %q, want %q", got, wantTokens)
+	}
+
+	want := []struct {
+		line     int
+		identity string
+		nick     string
+		shared   bool
+	}{
+		{2, "@user1", "Секртис", false},
+		{3, "@user2", "Мадам Жу", true},
+		{7, "@user5", "Пёс", true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Line != w.line || r.Identity() != w.identity || r.Nickname != w.nick || r.Shared != w.shared {
+			t.Errorf("row %d = {%d %s %s %v}, want %+v", i, r.Line, r.Identity(), r.Nickname, r.Shared, w)
+		}
+	}
+
+	var invalidLines []int
+	for _, e := range invalid {
+		invalidLines = append(invalidLines, e.Line)
+	}
+	if want := []int{5, 6}; !slices.Equal(invalidLines, want) {
+		t.Errorf("invalid lines = %v, want %v", invalidLines, want)
+	}
+}
+
+func TestParse_Semicolons(t *testing.T) {
+	rows, invalid, err := Parse([]byte("@user1;Секртис;м\r\n@user2;\"Кот; Учёный\"\r\n"), validateStub)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(invalid) != 0 {
+		t.Errorf("unexpected invalid rows: %+v", invalid)
+	}
+	if len(rows) != 2 || rows[0].Nickname != "Секртис" || rows[1].Nickname != "Кот; Учёный" {
+		t.Errorf("rows = %+v", rows)
+	}
+}
+
+// mockCreator creates nicknames unless the name is already taken.
+type mockCreator struct {
+	taken map[string]bool
+	clubs []poll.Club
+}
+
+func (m *mockCreator) CreateNickname(club poll.Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
+	m.clubs = append(m.clubs, club)
+	if m.taken[gameNick] {
+		return false, nil
+	}
+	m.taken[gameNick] = true
+	return true, nil
+}
+
+func TestImport(t *testing.T) {
+	creator := &mockCreator{taken: map[string]bool{"Кот": true}}
+
+	input := strings.Join([]string{
+		"@user1,Секртис",
+		"@user2,Кот",
+		"@user3,Секртис,,shared",
+		"@user4",
+	}, "\n")
+	report, err := Import(creator, poll.ClubVanmo, []byte(input), 42, validateStub)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if len(report.Created) != 1 || report.Created[0].Identity() != "@user1" {
+		t.Errorf("created = %+v, want @user1", report.Created)
+	}
+	if len(report.Duplicates) != 2 || report.Duplicates[0].Line != 2 || report.Duplicates[1].Line != 3 {
+		t.Errorf("duplicates = %+v, want lines 2 and 3", report.Duplicates)
+	}
+	if len(report.Invalid) != 1 || report.Invalid[0].Line != 4 {
+		t.Errorf("invalid = %+v, want line 4", report.Invalid)
+	}
+	if want := []poll.Club{poll.ClubVanmo, poll.ClubVanmo, poll.SharedNicknames}; !slices.Equal(creator.clubs, want) {
+		t.Errorf("clubs = %v, want %v", creator.clubs, want)
+	}
+}
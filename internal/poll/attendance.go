@@ -0,0 +1,18 @@
+package poll
+
+import "time"
+
+// Attendance records whether a player who voted to attend actually showed up.
+type Attendance struct {
+	PollID      int64
+	TgUserID    int64
+	Attended    bool
+	ActorUserID int64 // Admin who last changed the record (0 if set automatically)
+	UpdatedAt   time.Time
+}
+
+// AttendanceEntry pairs an attending vote with its confirmation status.
+type AttendanceEntry struct {
+	Vote     *Vote
+	Attended bool
+}
@@ -7,3 +7,16 @@ const (
 	ClubVanmo      Club = "vanmo"
 	ClubTbilissimo Club = "tbilissimo"
 )
+
+// Clubs lists all known clubs.
+var Clubs = []Club{ClubVanmo, ClubTbilissimo}
+
+// ParseClub returns the known club with the given name.
+func ParseClub(name string) (Club, bool) {
+	for _, c := range Clubs {
+		if string(c) == name {
+			return c, true
+		}
+	}
+	return "", false
+}
@@ -7,4 +7,27 @@ var (
 	ErrNoCancelledPoll = errors.New("no cancelled poll")
 	ErrPollExists      = errors.New("active poll already exists")
 	ErrPollDatePassed  = errors.New("poll date has passed")
+
+	ErrInvalidPlayerCount = errors.New("invalid number of players")
+	ErrDuplicatePlayer    = errors.New("player listed twice")
+	ErrInvalidRoles       = errors.New("invalid role composition")
+	ErrJudgeIsPlayer      = errors.New("judge cannot play")
+	ErrGameNotFound       = errors.New("game not found")
+
+	ErrNotAttendingVoter = errors.New("user did not vote to attend")
+
+	ErrNoPrice         = errors.New("event price not set")
+	ErrPaymentNotFound = errors.New("payment not found")
+
+	ErrInvalidGuestCount = errors.New("invalid number of guests")
+	ErrHostNotAttending  = errors.New("guest host is not attending")
+
+	ErrNickTaken             = errors.New("nickname already taken")
+	ErrNickNotFound          = errors.New("nickname not found")
+	ErrNickHasHistory        = errors.New("nickname is the player's only identity and has history")
+	ErrNickShared            = errors.New("nickname is shared by all clubs")
+	ErrHasNickname           = errors.New("user already has a nickname")
+	ErrNickRequestNotFound   = errors.New("nickname request not found")
+	ErrNickRequestNotPending = errors.New("nickname request already decided")
+	ErrNickRequestPending    = errors.New("nickname request already pending")
 )
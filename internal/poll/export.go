@@ -0,0 +1,63 @@
+package poll
+
+// Export is a snapshot of a club's data for analysis outside the bot.
+type Export struct {
+	Club      Club
+	Period    StatsPeriod
+	Polls     []*ExportedPoll
+	Nicknames []*Nickname // nicknames visible in the club, ordered by nickname
+}
+
+// ExportedPoll is a poll with its current votes.
+type ExportedPoll struct {
+	*Poll
+	Votes []*ExportedVote
+}
+
+// ExportedVote is a current vote with the voter's game nickname (empty if none).
+type ExportedVote struct {
+	*Vote
+	Nickname NicknameInfo
+}
+
+// Export collects the club's polls with the event date within the period, their current votes
+// with nicknames, and the club's nicknames.
+func (s *Service) Export(club Club, period StatsPeriod) (*Export, error) {
+	polls, err := s.polls.ListByClub(club, period)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Export{Club: club, Period: period}
+	for _, p := range polls {
+		ep, err := s.ExportPoll(p)
+		if err != nil {
+			return nil, err
+		}
+		e.Polls = append(e.Polls, ep)
+	}
+
+	e.Nicknames, err = s.nicknames.List(club)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ExportPoll returns the poll's current votes with the voters' nicknames in the poll's club.
+func (s *Service) ExportPoll(p *Poll) (*ExportedPoll, error) {
+	votes, err := s.currentVotes(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := s.NewNicknameCacheFromVotes(p.Club, votes)
+	if err != nil {
+		return nil, err
+	}
+
+	ep := &ExportedPoll{Poll: p, Votes: make([]*ExportedVote, len(votes))}
+	for i, v := range votes {
+		ep.Votes[i] = &ExportedVote{Vote: v, Nickname: cache.GetInfo(v.TgUserID, v.TgUsername)}
+	}
+	return ep, nil
+}
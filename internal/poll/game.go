@@ -0,0 +1,94 @@
+package poll
+
+import "time"
+
+// Game player count limits
+const (
+	MinGamePlayers = 6
+	MaxGamePlayers = 12
+)
+
+// Role is a player's role in a mafia game.
+type Role string
+
+const (
+	RoleCivilian Role = "civilian"
+	RoleMafia    Role = "mafia"
+	RoleDon      Role = "don"
+	RoleSheriff  Role = "sheriff"
+)
+
+// Side returns the team the role plays for.
+func (r Role) Side() Side {
+	switch r {
+	case RoleMafia, RoleDon:
+		return SideMafia
+	default:
+		return SideCity
+	}
+}
+
+// Side is a team in a mafia game.
+type Side string
+
+const (
+	SideCity  Side = "city"
+	SideMafia Side = "mafia"
+)
+
+// Game is a single mafia game played on an event night.
+type Game struct {
+	ID           int64
+	PollID       int64 // event night the game was played at
+	Number       int   // game number within the event night, starting at 1
+	Winner       Side
+	JudgeUserID  int64
+	JudgeName    string // judge display name at the time of recording
+	ActorUserID  int64  // admin who recorded the game
+	PlayedAt     time.Time
+	Participants []*GameParticipant // ordered by seat
+}
+
+// GameParticipant is a player seated at a game.
+type GameParticipant struct {
+	Seat       int // 1-based seat number
+	TgUserID   int64
+	TgUsername string
+	Name       string // display name at the time of recording
+	Role       Role
+}
+
+// Won returns true if the participant's side won the game.
+func (g *Game) Won(p *GameParticipant) bool {
+	return p.Role.Side() == g.Winner
+}
+
+// Validate checks that the game has a valid player count, role composition and judge.
+// A game needs mafia in the minority (but at least one), at most one don and
+// one sheriff, distinct players, and a judge who is not playing.
+func (g *Game) Validate() error {
+	if len(g.Participants) < MinGamePlayers || len(g.Participants) > MaxGamePlayers {
+		return ErrInvalidPlayerCount
+	}
+
+	seen := make(map[int64]bool, len(g.Participants))
+	counts := make(map[Role]int)
+	for _, p := range g.Participants {
+		if seen[p.TgUserID] {
+			return ErrDuplicatePlayer
+		}
+		seen[p.TgUserID] = true
+		counts[p.Role]++
+	}
+
+	mafia := counts[RoleMafia] + counts[RoleDon]
+	if counts[RoleDon] > 1 || counts[RoleSheriff] > 1 || mafia == 0 || mafia*2 >= len(g.Participants) {
+		return ErrInvalidRoles
+	}
+
+	if g.JudgeUserID != 0 && seen[g.JudgeUserID] {
+		return ErrJudgeIsPlayer
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package poll
+
+import (
+	"errors"
+	"testing"
+)
+
+// testGame builds a game with players 1..n, where the given seats play the given roles.
+func testGame(n int, winner Side, roles map[int]Role) *Game {
+	g := &Game{Winner: winner}
+	for seat := 1; seat <= n; seat++ {
+		role, ok := roles[seat]
+		if !ok {
+			role = RoleCivilian
+		}
+		g.Participants = append(g.Participants, &GameParticipant{Seat: seat, TgUserID: int64(seat), Role: role})
+	}
+	return g
+}
+
+func TestGameValidate(t *testing.T) {
+	classic := map[int]Role{1: RoleDon, 2: RoleMafia, 3: RoleMafia, 4: RoleSheriff}
+
+	tests := []struct {
+		name    string
+		game    *Game
+		wantErr error
+	}{
+		{"classic ten", testGame(10, SideCity, classic), nil},
+		{"too few players", testGame(5, SideCity, map[int]Role{1: RoleMafia}), ErrInvalidPlayerCount},
+		{"too many players", testGame(13, SideCity, classic), ErrInvalidPlayerCount},
+		{"no mafia", testGame(10, SideCity, map[int]Role{1: RoleSheriff}), ErrInvalidRoles},
+		{"two dons", testGame(10, SideCity, map[int]Role{1: RoleDon, 2: RoleDon}), ErrInvalidRoles},
+		{"two sheriffs", testGame(10, SideCity, map[int]Role{1: RoleMafia, 2: RoleSheriff, 3: RoleSheriff}), ErrInvalidRoles},
+		{"mafia majority", testGame(6, SideCity, map[int]Role{1: RoleMafia, 2: RoleMafia, 3: RoleDon}), ErrInvalidRoles},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.game.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	dup := testGame(10, SideCity, classic)
+	dup.Participants[9].TgUserID = 1
+	if err := dup.Validate(); !errors.Is(err, ErrDuplicatePlayer) {
+		t.Errorf("Validate() with duplicate = %v, want ErrDuplicatePlayer", err)
+	}
+
+	judged := testGame(10, SideCity, classic)
+	judged.JudgeUserID = 5
+	if err := judged.Validate(); !errors.Is(err, ErrJudgeIsPlayer) {
+		t.Errorf("Validate() with playing judge = %v, want ErrJudgeIsPlayer", err)
+	}
+}
+
+func TestCalculateRatings(t *testing.T) {
+	roles := map[int]Role{1: RoleDon, 2: RoleMafia, 3: RoleMafia, 4: RoleSheriff}
+
+	// First game between equal teams: every player moves by K/2
+	ratings := CalculateRatings([]*Game{testGame(10, SideMafia, roles)})
+	if len(ratings) != 10 {
+		t.Fatalf("expected 10 ratings, got %d", len(ratings))
+	}
+	byID := make(map[int64]*PlayerRating)
+	for _, r := range ratings {
+		byID[r.TgUserID] = r
+	}
+	if got := byID[1].Rating; got != InitialRating+RatingK/2 {
+		t.Errorf("winner rating = %f, want %f", got, InitialRating+RatingK/2)
+	}
+	if got := byID[5].Rating; got != InitialRating-RatingK/2 {
+		t.Errorf("loser rating = %f, want %f", got, InitialRating-RatingK/2)
+	}
+	if byID[1].Wins != 1 || byID[1].Games != 1 || byID[5].Wins != 0 {
+		t.Errorf("unexpected counters: winner %+v, loser %+v", byID[1], byID[5])
+	}
+	if ratings[0].Rating < ratings[len(ratings)-1].Rating {
+		t.Error("expected ratings ordered descending")
+	}
+
+	// Same teams again, favourites win: they gain less than K/2
+	ratings = CalculateRatings([]*Game{testGame(10, SideMafia, roles), testGame(10, SideMafia, roles)})
+	for _, r := range ratings {
+		if r.TgUserID == 1 {
+			gain := r.Rating - (InitialRating + RatingK/2)
+			if gain <= 0 || gain >= RatingK/2 {
+				t.Errorf("favourite second win gain = %f, want in (0, %f)", gain, RatingK/2)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,24 @@
+package poll
+
+import "strconv"
+
+// MaxGuests is the maximum number of guests a player can bring to an event.
+const MaxGuests = 5
+
+// GuestSuffix returns the guest marker shown after the voter's name (e.g. " +1"),
+// or empty string if the voter brings no guests.
+func (v *Vote) GuestSuffix() string {
+	if v.Guests <= 0 {
+		return ""
+	}
+	return " +" + strconv.Itoa(v.Guests)
+}
+
+// HeadCount returns the number of people the votes stand for: each voter plus their guests.
+func HeadCount(votes []*Vote) int {
+	n := len(votes)
+	for _, v := range votes {
+		n += v.Guests
+	}
+	return n
+}
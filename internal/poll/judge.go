@@ -0,0 +1,25 @@
+package poll
+
+import "time"
+
+// JudgeSuggestion is a candidate for hosting the next event.
+type JudgeSuggestion struct {
+	UserID     int64
+	LastHosted time.Time // zero if never hosted
+}
+
+// NextJudge picks the pool member who hosted least recently.
+// Members who never hosted come first; ties are broken by pool order.
+// Returns false if the pool is empty.
+func NextJudge(pool []int64, lastHosted map[int64]time.Time) (JudgeSuggestion, bool) {
+	var best JudgeSuggestion
+	found := false
+	for _, userID := range pool {
+		last := lastHosted[userID]
+		if !found || last.Before(best.LastHosted) {
+			best = JudgeSuggestion{UserID: userID, LastHosted: last}
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,36 @@
+package poll
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextJudge(t *testing.T) {
+	jan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		pool       []int64
+		lastHosted map[int64]time.Time
+		want       int64
+		wantFound  bool
+	}{
+		{"empty pool", nil, nil, 0, false},
+		{"never hosted comes first", []int64{1, 2, 3}, map[int64]time.Time{1: jan, 3: feb}, 2, true},
+		{"least recent", []int64{1, 2}, map[int64]time.Time{1: feb, 2: jan}, 2, true},
+		{"tie broken by pool order", []int64{3, 1, 2}, map[int64]time.Time{}, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := NextJudge(tt.pool, tt.lastHosted)
+			if found != tt.wantFound || got.UserID != tt.want {
+				t.Errorf("NextJudge() = %d, %v, want %d, %v", got.UserID, found, tt.want, tt.wantFound)
+			}
+			if found && !got.LastHosted.Equal(tt.lastHosted[got.UserID]) {
+				t.Errorf("LastHosted = %v, want %v", got.LastHosted, tt.lastHosted[got.UserID])
+			}
+		})
+	}
+}
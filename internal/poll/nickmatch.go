@@ -0,0 +1,88 @@
+package poll
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillicToLatin transliterates lowercase Cyrillic letters so that nicknames typed
+// in either alphabet compare equal ("Мадам Жу" and "madam zhu").
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// latinSpelling folds Latin spellings that players use interchangeably for the same sound.
+var latinSpelling = strings.NewReplacer(
+	"kh", "h",
+	"ck", "k",
+	"x", "ks",
+	"w", "v",
+	"q", "k",
+	"j", "y",
+)
+
+// NormalizeNick reduces a nickname to a lowercase Latin key: Cyrillic is transliterated,
+// and spaces, punctuation and emoji are dropped.
+func NormalizeNick(nick string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(nick) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			sb.WriteString(latin)
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return latinSpelling.Replace(sb.String())
+}
+
+// maxNickDistance is the number of typos tolerated in a normalized nickname
+// of the given length: one for short nicknames, one more per five letters.
+func maxNickDistance(length int) int {
+	return 1 + length/5
+}
+
+// ClosestNickname returns the nickname from nicks that the query most likely means:
+// the one with the smallest edit distance between normalized forms, within the typo
+// tolerance of the query length. Ties go to the earlier nickname in nicks.
+// Returns false if no nickname is close enough.
+func ClosestNickname(query string, nicks []string) (string, bool) {
+	key := []rune(NormalizeNick(query))
+	if len(key) == 0 {
+		return "", false
+	}
+
+	best, bestDistance := "", maxNickDistance(len(key))+1
+	for _, nick := range nicks {
+		if d := editDistance(key, []rune(NormalizeNick(nick))); d < bestDistance {
+			best, bestDistance = nick, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
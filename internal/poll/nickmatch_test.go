@@ -0,0 +1,55 @@
+package poll
+
+import "testing"
+
+func TestNormalizeNick(t *testing.T) {
+	tests := []struct {
+		nick string
+		want string
+	}{
+		{"Мадам Жу", "madamzhu"},
+		{"madam zhu", "madamzhu"},
+		{"Ёжик", "ezhik"},
+		{"Хохотун", "hohotun"},
+		{"Khokhotun", "hohotun"},
+		{"Jack-007", "yak007"},
+		{"🦊 Лиса!", "lisa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nick, func(t *testing.T) {
+			if got := NormalizeNick(tt.nick); got != tt.want {
+				t.Errorf("NormalizeNick(%q) = %q, want %q", tt.nick, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestNickname(t *testing.T) {
+	nicks := []string{"Мадам Жу", "Кот", "Кит", "Лиса", "Енот"}
+
+	tests := []struct {
+		name      string
+		query     string
+		want      string
+		wantFound bool
+	}{
+		{"transliterated", "Madam Zhu", "Мадам Жу", true},
+		{"typo", "Мадм Жу", "Мадам Жу", true},
+		{"latin typo", "Lisaa", "Лиса", true},
+		{"tie goes to first", "Кут", "Кот", true},
+		{"too far", "Медведь", "", false},
+		{"suffix within tolerance", "Енотик", "Енот", true},
+		{"short nick tolerates one typo", "Кто", "", false},
+		{"empty", "!!!", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := ClosestNickname(tt.query, nicks)
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("ClosestNickname(%q) = %q, %v, want %q, %v", tt.query, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
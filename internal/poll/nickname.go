@@ -0,0 +1,18 @@
+package poll
+
+// SharedNicknames is the nickname namespace visible in every club,
+// for players active in more than one club.
+const SharedNicknames Club = ""
+
+// Nickname is a stored link between a Telegram user and a game nickname.
+type Nickname struct {
+	Club       Club   // namespace of the nickname, SharedNicknames for all clubs
+	TgUserID   int64  // 0 if unknown
+	TgUsername string // normalized, without @
+	NicknameInfo
+}
+
+// Shared reports whether the nickname is visible in every club.
+func (n *Nickname) Shared() bool {
+	return n.Club == SharedNicknames
+}
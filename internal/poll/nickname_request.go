@@ -0,0 +1,26 @@
+package poll
+
+import "time"
+
+// NicknameRequestStatus is the review state of a player's nickname request.
+type NicknameRequestStatus string
+
+const (
+	NicknameRequestPending  NicknameRequestStatus = "pending"
+	NicknameRequestApproved NicknameRequestStatus = "approved"
+	NicknameRequestRejected NicknameRequestStatus = "rejected"
+)
+
+// NicknameRequest is a nickname a player asked for themselves, waiting for an admin decision.
+type NicknameRequest struct {
+	ID          int64
+	TgChatID    int64 // club chat where admins review the request
+	TgUserID    int64
+	TgUsername  string
+	TgFirstName string
+	GameNick    string
+	Gender      string // "male", "female", or "" for not set
+	Status      NicknameRequestStatus
+	DecidedBy   int64
+	CreatedAt   time.Time
+}
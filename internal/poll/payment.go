@@ -0,0 +1,12 @@
+package poll
+
+import "time"
+
+// Payment records that a player paid for an event.
+type Payment struct {
+	PollID      int64
+	TgUserID    int64
+	Amount      int // amount paid, in the club currency (₾)
+	ActorUserID int64
+	PaidAt      time.Time
+}
@@ -3,22 +3,36 @@ package poll
 import "time"
 
 type Poll struct {
-	ID                 int64
-	TgChatID           int64
-	Club               Club
-	TgPollID           string
-	TgMessageID        int
+	ID          int64
+	TgChatID    int64
+	Club        Club
+	TgPollID    string
+	TgMessageID int
 	// TgInvitationMessageID stores the invitation message ID (the persistent message
 	// that displays current votes and updates as users vote).
 	TgInvitationMessageID int
 	TgCancelMessageID     int
 	TgDoneMessageID       int
 	StartTime             string // Saved start time from /done (e.g. "19:00", "20:00"), empty if not set
-	EventDate          time.Time
-	Options            []OptionKind
-	IsActive           bool
-	IsPinned           bool
-	CreatedAt          time.Time
+	JudgeUserID           int64  // Event host (0 if not assigned)
+	JudgeName             string // Event host display name at the time of assignment
+	Price                 int    // Price per player in ₾ (0 if not set)
+	EventDate             time.Time
+	Options               []OptionKind
+	IsActive              bool
+	IsPinned              bool
+	CreatedAt             time.Time
+}
+
+// HasInlineVoting reports whether votes are cast with inline buttons on the invitation
+// message instead of a native Telegram poll (the invitation is the poll message).
+func (p *Poll) HasInlineVoting() bool {
+	return p.TgPollID == "" && p.TgMessageID != 0 && p.TgMessageID == p.TgInvitationMessageID
+}
+
+// IsCancelled reports whether the event was cancelled with /cancel.
+func (p *Poll) IsCancelled() bool {
+	return p.TgCancelMessageID != 0
 }
 
 // CreatePollResult contains the result of creating a poll,
@@ -0,0 +1,85 @@
+package poll
+
+import (
+	"math"
+	"sort"
+)
+
+// Elo rating parameters
+const (
+	InitialRating = 1500.0
+	RatingK       = 32.0 // maximum rating change per game
+)
+
+// PlayerRating is a player's Elo-style rating computed from game results.
+type PlayerRating struct {
+	TgUserID   int64
+	TgUsername string // username from the player's latest game
+	Name       string // display name from the player's latest game
+	Rating     float64
+	Games      int
+	Wins       int
+}
+
+// CalculateRatings computes Elo-style ratings from games in chronological order.
+// Each game is treated as a match between two teams: a team's strength is the
+// average rating of its players, and every player of a team gains or loses
+// RatingK * (score - expected), where score is 1 for a win and 0 for a loss.
+// Returns ratings ordered by rating (descending).
+func CalculateRatings(games []*Game) []*PlayerRating {
+	ratings := make(map[int64]*PlayerRating)
+
+	for _, g := range games {
+		teamTotal := map[Side]float64{}
+		teamSize := map[Side]int{}
+		for _, p := range g.Participants {
+			r, ok := ratings[p.TgUserID]
+			if !ok {
+				r = &PlayerRating{TgUserID: p.TgUserID, Rating: InitialRating}
+				ratings[p.TgUserID] = r
+			}
+			r.TgUsername = p.TgUsername
+			r.Name = p.Name
+			teamTotal[p.Role.Side()] += r.Rating
+			teamSize[p.Role.Side()]++
+		}
+		if teamSize[SideCity] == 0 || teamSize[SideMafia] == 0 {
+			continue
+		}
+
+		cityAvg := teamTotal[SideCity] / float64(teamSize[SideCity])
+		mafiaAvg := teamTotal[SideMafia] / float64(teamSize[SideMafia])
+		expected := map[Side]float64{
+			SideCity:  expectedScore(cityAvg, mafiaAvg),
+			SideMafia: expectedScore(mafiaAvg, cityAvg),
+		}
+
+		for _, p := range g.Participants {
+			r := ratings[p.TgUserID]
+			score := 0.0
+			if g.Won(p) {
+				score = 1
+				r.Wins++
+			}
+			r.Rating += RatingK * (score - expected[p.Role.Side()])
+			r.Games++
+		}
+	}
+
+	result := make([]*PlayerRating, 0, len(ratings))
+	for _, r := range ratings {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Rating != result[j].Rating {
+			return result[i].Rating > result[j].Rating
+		}
+		return result[i].TgUserID < result[j].TgUserID
+	})
+	return result
+}
+
+// expectedScore returns the expected score of a team rated a against a team rated b.
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
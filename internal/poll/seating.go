@@ -0,0 +1,46 @@
+package poll
+
+import "math/rand/v2"
+
+// MaxSeats is the number of seats at a game table
+const MaxSeats = 10
+
+// ShuffleSeats randomly assigns seat numbers 1..len(players) and returns players ordered by seat.
+func ShuffleSeats(players []*GameParticipant) []*GameParticipant {
+	seated := make([]*GameParticipant, len(players))
+	copy(seated, players)
+	rand.Shuffle(len(seated), func(i, j int) { seated[i], seated[j] = seated[j], seated[i] })
+	for i, p := range seated {
+		p.Seat = i + 1
+	}
+	return seated
+}
+
+// RolesForPlayerCount returns the role set for a table of n players:
+// one don, one sheriff, mafia making up 30% of the table (don included, at least one),
+// and civilians for the rest. For 10 players: don, 2 mafia, sheriff, 6 civilians.
+func RolesForPlayerCount(n int) []Role {
+	mafia := max(1, n*3/10)
+	roles := []Role{RoleDon, RoleSheriff}
+	for range mafia - 1 {
+		roles = append(roles, RoleMafia)
+	}
+	for len(roles) < n {
+		roles = append(roles, RoleCivilian)
+	}
+	return roles
+}
+
+// DealRoles randomly assigns roles to seated players.
+// Returns ErrInvalidPlayerCount if the table is smaller than MinGamePlayers or larger than MaxGamePlayers.
+func DealRoles(players []*GameParticipant) error {
+	if len(players) < MinGamePlayers || len(players) > MaxGamePlayers {
+		return ErrInvalidPlayerCount
+	}
+	roles := RolesForPlayerCount(len(players))
+	rand.Shuffle(len(roles), func(i, j int) { roles[i], roles[j] = roles[j], roles[i] })
+	for i, p := range players {
+		p.Role = roles[i]
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package poll
+
+import "testing"
+
+func TestShuffleSeats(t *testing.T) {
+	players := make([]*GameParticipant, 10)
+	for i := range players {
+		players[i] = &GameParticipant{TgUserID: int64(i + 1)}
+	}
+
+	seated := ShuffleSeats(players)
+	if len(seated) != len(players) {
+		t.Fatalf("got %d seated players, want %d", len(seated), len(players))
+	}
+
+	seen := make(map[int64]bool)
+	for i, p := range seated {
+		if p.Seat != i+1 {
+			t.Errorf("player at index %d has seat %d, want %d", i, p.Seat, i+1)
+		}
+		if seen[p.TgUserID] {
+			t.Errorf("player %d seated twice", p.TgUserID)
+		}
+		seen[p.TgUserID] = true
+	}
+}
+
+func TestRolesForPlayerCount(t *testing.T) {
+	tests := []struct {
+		n                               int
+		wantDon, wantMafia, wantSheriff int
+	}{
+		{6, 1, 0, 1},
+		{7, 1, 1, 1},
+		{10, 1, 2, 1},
+		{12, 1, 2, 1},
+	}
+
+	for _, tt := range tests {
+		roles := RolesForPlayerCount(tt.n)
+		if len(roles) != tt.n {
+			t.Errorf("RolesForPlayerCount(%d) returned %d roles", tt.n, len(roles))
+		}
+		counts := make(map[Role]int)
+		for _, r := range roles {
+			counts[r]++
+		}
+		if counts[RoleDon] != tt.wantDon || counts[RoleMafia] != tt.wantMafia || counts[RoleSheriff] != tt.wantSheriff {
+			t.Errorf("RolesForPlayerCount(%d) = %v", tt.n, counts)
+		}
+
+		// Dealt roles must always form a valid game
+		g := &Game{}
+		for i, r := range roles {
+			g.Participants = append(g.Participants, &GameParticipant{TgUserID: int64(i + 1), Role: r})
+		}
+		if err := g.Validate(); err != nil {
+			t.Errorf("RolesForPlayerCount(%d) produced invalid game: %v", tt.n, err)
+		}
+	}
+}
+
+func TestDealRoles(t *testing.T) {
+	players := make([]*GameParticipant, 10)
+	for i := range players {
+		players[i] = &GameParticipant{Seat: i + 1, TgUserID: int64(i + 1)}
+	}
+
+	if err := DealRoles(players); err != nil {
+		t.Fatalf("DealRoles failed: %v", err)
+	}
+	for _, p := range players {
+		if p.Role == "" {
+			t.Errorf("seat %d has no role", p.Seat)
+		}
+	}
+
+	if err := DealRoles(players[:5]); err != ErrInvalidPlayerCount {
+		t.Errorf("DealRoles with 5 players = %v, want ErrInvalidPlayerCount", err)
+	}
+}
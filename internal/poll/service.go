@@ -1,21 +1,34 @@
 package poll
 
-import "time"
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
 
 type PollRepository interface {
 	Create(p *Poll) error
 	GetLatestActive(chatID int64) (*Poll, error)
 	GetLatestCancelled(chatID int64) (*Poll, error)
 	GetLatest(chatID int64) (*Poll, error)
+	GetByID(id int64) (*Poll, error)
 	GetByTgPollID(tgPollID string) (*Poll, error)
 	Update(p *Poll) error
+	GetJudgeLastHosted(club Club, userIDs []int64) (map[int64]time.Time, error)
+	ListByClub(club Club, period StatsPeriod) ([]*Poll, error)
 }
 
 type VoteRepository interface {
+	// Record and RecordAll also remove the guests of voters whose new vote is not attending,
+	// in the same transaction.
 	Record(v *Vote) error
+	RecordAll(votes []*Vote) error
 	GetCurrentVotes(pollID int64) ([]*Vote, error)
 	LookupUserIDByUsername(username string) (int64, bool, error)
 	LookupUsernameByUserID(userID int64) (string, bool, error)
+	LookupLatestChatID(userID int64) (int64, bool, error)
 	UpdateVotesUserID(pollID int64, oldUserID, newUserID int64, tgUsername string) error
 	ConsolidateSyntheticVotes(pollID int64, realUserID int64, username string, gameNicks []string) error
 }
@@ -57,33 +70,125 @@ func (n NicknameInfo) GenderPrefix() string {
 	}
 }
 
+// NicknameRepository stores game nicknames in per-club namespaces plus the shared one.
+// Lookups taking a club see the club's own nicknames and the shared ones.
 type NicknameRepository interface {
-	Create(tgUserID *int64, tgUsername *string, gameNick string, gender string) (bool, error)
-	FindByGameNick(gameNick string) (tgUserID *int64, tgUsername *string, err error)
-	FindByTgUsername(username string) (gameNick string, tgUserID *int64, err error)
-	FindByTgUserID(userID int64) (gameNick string, err error)
-	GetDisplayNick(userID int64, username string) (string, error)
-	GetDisplayNicksBatch(keys []NicknameLookupKey) (byUserID map[int64]NicknameInfo, byUsername map[string]NicknameInfo, err error)
+	Create(club Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error)
+	Exists(club Club, gameNick string) (bool, error)
+	Get(club Club, gameNick string) (*Nickname, error)
+	FindByGameNick(club Club, gameNick string) (tgUserID *int64, tgUsername *string, err error)
+	FindByTgUsername(club Club, username string) (gameNick string, tgUserID *int64, err error)
+	FindByTgUserID(club Club, userID int64) (gameNick string, err error)
+	GetDisplayNick(club Club, userID int64, username string) (string, error)
+	GetDisplayNicksBatch(club Club, keys []NicknameLookupKey) (byUserID map[int64]NicknameInfo, byUsername map[string]NicknameInfo, err error)
 	UpdateUserIDByUsername(username string, userID int64) error
 	UpdateUserData(userID int64, username string) error
 	GetAllGameNicksForUser(userID int64, username string) ([]string, error)
+	List(club Club) ([]*Nickname, error)
+	Delete(club Club, gameNick string) (bool, error)
+	Rename(club Club, oldNick, newNick string) (bool, error)
+	SetGender(club Club, gameNick string, gender string) (bool, error)
+}
+
+type AttendanceRepository interface {
+	Init(pollID int64, userIDs []int64) error
+	Toggle(pollID, userID, actorUserID int64) (bool, error)
+	GetByPoll(pollID int64) ([]*Attendance, error)
+	CountNoShows(club Club, playerIDs []int64, aliases map[int64]int64) (map[int64]int, error)
+}
+
+type StatsRepository interface {
+	GetPlayerStats(club Club, userIDs []int64, period StatsPeriod) (*PlayerStats, error)
+	GetVoterUsernames(club Club) (map[int64]string, error)
+	// GetLeaderboard, GetPollVotes and GetClubSummary count votes under an alias (a synthetic ID)
+	// as votes of the player it maps to.
+	GetLeaderboard(club Club, period StatsPeriod, aliases map[int64]int64, limit int) ([]*LeaderboardEntry, error)
+	GetPollVotes(club Club, period StatsPeriod, aliases map[int64]int64) ([]*PollVote, error)
+	GetClubSummary(club Club, period StatsPeriod, aliases map[int64]int64, tableSize int) (*ClubSummary, error)
+	MarkReportPosted(chatID int64, period string) (bool, error)
+	UnmarkReportPosted(chatID int64, period string) error
+}
+
+type GameRepository interface {
+	Create(g *Game) error
+	GetByPoll(pollID int64) ([]*Game, error)
+	GetByClub(club Club, period StatsPeriod) ([]*Game, error)
+	Delete(pollID int64, number int) (bool, error)
+}
+
+type SeatingRepository interface {
+	Save(pollID int64, players []*GameParticipant) error
+	GetByPoll(pollID int64) ([]*GameParticipant, error)
+}
+
+type PaymentRepository interface {
+	Record(p *Payment) error
+	Delete(pollID, userID int64) (bool, error)
+	GetPaidUserIDs(pollID int64) (map[int64]bool, error)
+}
+
+type GuestRepository interface {
+	Set(pollID, hostUserID int64, count int, actorUserID int64) error
+	GetByPoll(pollID int64) (map[int64]int, error)
+}
+
+type NicknameRequestRepository interface {
+	Create(req *NicknameRequest) error
+	GetByID(id int64) (*NicknameRequest, error)
+	Decide(id int64, from, status NicknameRequestStatus, actorUserID int64) (bool, error)
+	HasPending(chatID, userID int64) (bool, error)
 }
 
 type Service struct {
-	polls     PollRepository
-	votes     VoteRepository
-	nicknames NicknameRepository
+	polls        PollRepository
+	votes        VoteRepository
+	nicknames    NicknameRepository
+	attendance   AttendanceRepository
+	stats        StatsRepository
+	games        GameRepository
+	seating      SeatingRepository
+	payments     PaymentRepository
+	guests       GuestRepository
+	nickRequests NicknameRequestRepository
 }
 
-func NewService(polls PollRepository, votes VoteRepository, nicknames NicknameRepository) *Service {
-	return &Service{polls: polls, votes: votes, nicknames: nicknames}
+// Repositories are the storage dependencies of a Service. Polls, Votes, Nicknames and Guests
+// back every poll; the others are only needed by the features built on them and may be left nil
+// where those features are not used, e.g. in tests.
+type Repositories struct {
+	Polls        PollRepository
+	Votes        VoteRepository
+	Nicknames    NicknameRepository
+	Attendance   AttendanceRepository
+	Stats        StatsRepository
+	Games        GameRepository
+	Seating      SeatingRepository
+	Payments     PaymentRepository
+	Guests       GuestRepository
+	NickRequests NicknameRequestRepository
+}
+
+func NewService(repos Repositories) *Service {
+	return &Service{
+		polls:        repos.Polls,
+		votes:        repos.Votes,
+		nicknames:    repos.Nicknames,
+		attendance:   repos.Attendance,
+		stats:        repos.Stats,
+		games:        repos.Games,
+		seating:      repos.Seating,
+		payments:     repos.Payments,
+		guests:       repos.Guests,
+		nickRequests: repos.NickRequests,
+	}
 }
 
 // CreatePoll creates a new poll for the given chat and event date.
 // Returns ErrPollExists if an active poll already exists in this chat with a future event date.
 // If an active poll exists but its event date is in the past, it will be deactivated and
 // the new poll created. The replaced poll is returned in CreatePollResult.ReplacedPoll.
-func (s *Service) CreatePoll(tgChatID int64, eventDate time.Time, club Club) (*CreatePollResult, error) {
+// price is the per-player price of the event (0 if not set).
+func (s *Service) CreatePoll(tgChatID int64, eventDate time.Time, club Club, price int) (*CreatePollResult, error) {
 	// Check if there's already an active poll
 	existing, err := s.polls.GetLatestActive(tgChatID)
 	if err != nil {
@@ -112,6 +217,7 @@ func (s *Service) CreatePoll(tgChatID int64, eventDate time.Time, club Club) (*C
 		TgChatID:  tgChatID,
 		Club:      club,
 		EventDate: eventDate,
+		Price:     price,
 		Options:   DefaultOptions(),
 		IsActive:  true,
 		IsPinned:  false,
@@ -158,6 +264,7 @@ func (s *Service) GetLatestPoll(tgChatID int64) (*Poll, error) {
 
 // CancelPoll cancels the active poll in the given chat.
 // Returns ErrNoActivePoll if no active poll exists.
+// Returns ErrPollDatePassed if the poll's event date is in the past.
 func (s *Service) CancelPoll(tgChatID int64) (*Poll, error) {
 	p, err := s.polls.GetLatestActive(tgChatID)
 	if err != nil {
@@ -166,6 +273,9 @@ func (s *Service) CancelPoll(tgChatID int64) (*Poll, error) {
 	if p == nil {
 		return nil, ErrNoActivePoll
 	}
+	if eventDatePassed(p.EventDate) {
+		return nil, ErrPollDatePassed
+	}
 
 	p.IsActive = false
 	p.IsPinned = false
@@ -189,11 +299,7 @@ func (s *Service) RestorePoll(tgChatID int64) (*Poll, error) {
 	}
 
 	// Check if event date is today or future
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	eventDay := time.Date(p.EventDate.Year(), p.EventDate.Month(), p.EventDate.Day(), 0, 0, 0, 0, p.EventDate.Location())
-
-	if eventDay.Before(today) {
+	if eventDatePassed(p.EventDate) {
 		return nil, ErrPollDatePassed
 	}
 
@@ -205,6 +311,14 @@ func (s *Service) RestorePoll(tgChatID int64) (*Poll, error) {
 	return p, nil
 }
 
+// eventDatePassed reports whether the event day is before today.
+func eventDatePassed(eventDate time.Time) bool {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	eventDay := time.Date(eventDate.Year(), eventDate.Month(), eventDate.Day(), 0, 0, 0, 0, eventDate.Location())
+	return eventDay.Before(today)
+}
+
 // SetPinned sets the pinned status for the active poll in the given chat.
 // Returns ErrNoActivePoll if no active poll exists.
 func (s *Service) SetPinned(tgChatID int64, pinned bool) (*Poll, error) {
@@ -223,6 +337,16 @@ func (s *Service) SetPinned(tgChatID int64, pinned bool) (*Poll, error) {
 	return p, nil
 }
 
+// GetPollByID returns the poll with the given ID, or nil if not found.
+func (s *Service) GetPollByID(id int64) (*Poll, error) {
+	return s.polls.GetByID(id)
+}
+
+// ListPolls returns the club's polls with the event date within the period, oldest first.
+func (s *Service) ListPolls(club Club, period StatsPeriod) ([]*Poll, error) {
+	return s.polls.ListByClub(club, period)
+}
+
 func (s *Service) GetPollByTgPollID(tgPollID string) (*Poll, error) {
 	return s.polls.GetByTgPollID(tgPollID)
 }
@@ -231,16 +355,24 @@ func (s *Service) UpdatePoll(p *Poll) error {
 	return s.polls.Update(p)
 }
 
-
+// RecordVote records a vote. A voter who is no longer attending loses their guests.
 func (s *Service) RecordVote(v *Vote) error {
 	return s.votes.Record(v)
 }
 
-// CreateNickname creates a new nickname mapping.
+// RecordVotes records several votes at once: either all of them are stored or none.
+// Voters who are no longer attending lose their guests.
+func (s *Service) RecordVotes(votes []*Vote) error {
+	return s.votes.RecordAll(votes)
+}
+
+// CreateNickname creates a new nickname mapping in the club's namespace
+// (SharedNicknames to make it visible in every club).
 // If tgUsername is provided, attempts to look up the user ID from voting history.
 // Gender should be "male", "female", or empty string for not set.
+// actorUserID is the admin creating the mapping, stored for audit purposes.
 // Returns true if created, false if duplicate.
-func (s *Service) CreateNickname(tgUserID *int64, tgUsername *string, gameNick string, gender string) (bool, error) {
+func (s *Service) CreateNickname(club Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
 	// If username provided but no user ID, try to look up from votes
 	if tgUserID == nil && tgUsername != nil {
 		if userID, found, err := s.votes.LookupUserIDByUsername(*tgUsername); err != nil {
@@ -250,18 +382,224 @@ func (s *Service) CreateNickname(tgUserID *int64, tgUsername *string, gameNick s
 		}
 	}
 
-	return s.nicknames.Create(tgUserID, tgUsername, gameNick, gender)
+	return s.nicknames.Create(club, tgUserID, tgUsername, gameNick, gender, actorUserID)
+}
+
+// ListNicknames returns the nickname mappings visible in the club (its own and shared) ordered by nickname.
+func (s *Service) ListNicknames(club Club) ([]*Nickname, error) {
+	return s.nicknames.List(club)
+}
+
+// DeleteNickname removes a nickname mapping visible in the club and returns the namespace it was in
+// (the club or SharedNicknames). Returns ErrNickNotFound if there is none, ErrNickShared if it is shared
+// and allowShared is false, and ErrNickHasHistory if the player has no other identity to keep
+// their history under.
+func (s *Service) DeleteNickname(club Club, gameNick string, allowShared bool) (Club, error) {
+	n, err := s.editableNickname(club, gameNick, allowShared)
+	if err != nil {
+		return "", err
+	}
+	deleted, err := s.nicknames.Delete(n.Club, gameNick)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return "", ErrNickNotFound
+	}
+	return n.Club, nil
+}
+
+// RenameNickname changes a game nickname visible in the club, keeping its Telegram link, gender, namespace
+// and history, and returns that namespace. Returns ErrNickTaken if newNick is already used,
+// ErrNickNotFound if oldNick does not exist and ErrNickShared if it is shared and allowShared is false.
+func (s *Service) RenameNickname(club Club, oldNick, newNick string, allowShared bool) (Club, error) {
+	n, err := s.editableNickname(club, oldNick, allowShared)
+	if err != nil {
+		return "", err
+	}
+	// Changing only the case of a nickname must not clash with the nickname itself
+	if !strings.EqualFold(oldNick, newNick) {
+		taken, err := s.nicknames.Exists(n.Club, newNick)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", ErrNickTaken
+		}
+	}
+	renamed, err := s.nicknames.Rename(n.Club, oldNick, newNick)
+	if err != nil {
+		return "", err
+	}
+	if !renamed {
+		return "", ErrNickNotFound
+	}
+	return n.Club, nil
+}
+
+// SetNicknameGender sets the gender of a nickname visible in the club ("male", "female", or "" to clear)
+// and returns the nickname's namespace. Returns ErrNickNotFound if the nickname does not exist
+// and ErrNickShared if it is shared and allowShared is false.
+func (s *Service) SetNicknameGender(club Club, gameNick string, gender string, allowShared bool) (Club, error) {
+	n, err := s.editableNickname(club, gameNick, allowShared)
+	if err != nil {
+		return "", err
+	}
+	updated, err := s.nicknames.SetGender(n.Club, gameNick, gender)
+	if err != nil {
+		return "", err
+	}
+	if !updated {
+		return "", ErrNickNotFound
+	}
+	return n.Club, nil
+}
+
+// editableNickname returns the nickname the club sees under gameNick, or ErrNickNotFound.
+// Shared nicknames are visible in every club, so changing them takes allowShared; otherwise
+// ErrNickShared is returned.
+func (s *Service) editableNickname(club Club, gameNick string, allowShared bool) (*Nickname, error) {
+	n, err := s.nicknames.Get(club, gameNick)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, ErrNickNotFound
+	}
+	if n.Shared() && !allowShared {
+		return nil, ErrNickShared
+	}
+	return n, nil
+}
+
+// RequestNickname stores a player's request for a game nickname in the club, to be reviewed by club admins.
+// Returns ErrHasNickname if the player already has a nickname in the club, ErrNickRequestPending
+// if their previous request is not decided yet and ErrNickTaken if the nickname belongs to someone else.
+func (s *Service) RequestNickname(club Club, req *NicknameRequest) error {
+	nick, err := s.nicknames.FindByTgUserID(club, req.TgUserID)
+	if err != nil {
+		return err
+	}
+	if nick != "" {
+		return ErrHasNickname
+	}
+	pending, err := s.nickRequests.HasPending(req.TgChatID, req.TgUserID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrNickRequestPending
+	}
+
+	taken, err := s.nicknames.Exists(club, req.GameNick)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrNickTaken
+	}
+	return s.nickRequests.Create(req)
 }
 
-// ResolveVoteIdentifier resolves a vote identifier to user information.
+// ApproveNicknameRequest creates the requested nickname in the club on behalf of the admin actorUserID.
+// The request is claimed first, so a rejection decided at the same time either wins before
+// the nickname is created or fails. Returns false if the nickname was taken while the request
+// was pending; the request is rejected then. If the player got a nickname in the club meanwhile,
+// the request is rejected too and ErrHasNickname is returned with it.
+// Returns ErrNickRequestNotFound or ErrNickRequestNotPending if there is nothing to approve in the chat.
+func (s *Service) ApproveNicknameRequest(id, chatID int64, club Club, actorUserID int64) (*NicknameRequest, bool, error) {
+	req, err := s.pendingNicknameRequest(id, chatID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.decideNicknameRequest(req, NicknameRequestPending, NicknameRequestApproved, actorUserID); err != nil {
+		return nil, false, err
+	}
+
+	created, err := s.createRequestedNickname(club, req, actorUserID)
+	if err != nil && !errors.Is(err, ErrHasNickname) {
+		// Give the request back to the admins to try again
+		if _, undoErr := s.nickRequests.Decide(req.ID, NicknameRequestApproved, NicknameRequestPending, 0); undoErr != nil {
+			return nil, false, errors.Join(err, undoErr)
+		}
+		return nil, false, err
+	}
+	if !created {
+		if err := s.decideNicknameRequest(req, NicknameRequestApproved, NicknameRequestRejected, actorUserID); err != nil {
+			return nil, false, err
+		}
+	}
+	return req, created, err
+}
+
+// createRequestedNickname creates the nickname of an approved request, unless the player
+// already has a nickname in the club (ErrHasNickname). Returns false if the nickname is taken.
+func (s *Service) createRequestedNickname(club Club, req *NicknameRequest, actorUserID int64) (bool, error) {
+	nick, err := s.nicknames.FindByTgUserID(club, req.TgUserID)
+	if err != nil {
+		return false, err
+	}
+	if nick != "" {
+		return false, ErrHasNickname
+	}
+	var username *string
+	if req.TgUsername != "" {
+		username = &req.TgUsername
+	}
+	return s.CreateNickname(club, &req.TgUserID, username, req.GameNick, req.Gender, actorUserID)
+}
+
+// RejectNicknameRequest declines a pending nickname request.
+func (s *Service) RejectNicknameRequest(id, chatID, actorUserID int64) (*NicknameRequest, error) {
+	req, err := s.pendingNicknameRequest(id, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decideNicknameRequest(req, NicknameRequestPending, NicknameRequestRejected, actorUserID); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// pendingNicknameRequest loads a request awaiting review in the given chat.
+func (s *Service) pendingNicknameRequest(id, chatID int64) (*NicknameRequest, error) {
+	req, err := s.nickRequests.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil || req.TgChatID != chatID {
+		return nil, ErrNickRequestNotFound
+	}
+	if req.Status != NicknameRequestPending {
+		return nil, ErrNickRequestNotPending
+	}
+	return req, nil
+}
+
+// decideNicknameRequest records the admin decision, moving the request from status from.
+// Fails with ErrNickRequestNotPending if another admin decided first.
+func (s *Service) decideNicknameRequest(req *NicknameRequest, from, status NicknameRequestStatus, actorUserID int64) error {
+	decided, err := s.nickRequests.Decide(req.ID, from, status, actorUserID)
+	if err != nil {
+		return err
+	}
+	if !decided {
+		return ErrNickRequestNotPending
+	}
+	req.Status = status
+	req.DecidedBy = actorUserID
+	return nil
+}
+
+// ResolveVoteIdentifier resolves a vote identifier to user information within a club.
 // If identifier starts with @, treats it as telegram username.
-// Otherwise, treats it as a game nickname.
+// Otherwise, treats it as a game nickname of the club (or a shared one).
 // Returns: userID (0 if unknown), username, displayName, error
-func (s *Service) ResolveVoteIdentifier(identifier string) (int64, string, string, error) {
+func (s *Service) ResolveVoteIdentifier(club Club, identifier string) (int64, string, string, error) {
 	if len(identifier) > 0 && identifier[0] == '@' {
 		// Telegram username
 		username := identifier[1:]
-		nick, userID, err := s.nicknames.FindByTgUsername(username)
+		nick, userID, err := s.nicknames.FindByTgUsername(club, username)
 		if err != nil {
 			return 0, "", "", err
 		}
@@ -284,7 +622,7 @@ func (s *Service) ResolveVoteIdentifier(identifier string) (int64, string, strin
 	}
 
 	// Game nickname - look up in nicknames table
-	userID, username, err := s.nicknames.FindByGameNick(identifier)
+	userID, username, err := s.nicknames.FindByGameNick(club, identifier)
 	if err != nil {
 		return 0, "", "", err
 	}
@@ -311,6 +649,60 @@ func (s *Service) ResolveVoteIdentifier(identifier string) (int64, string, strin
 	return ManualUserID(*username), *username, identifier, nil
 }
 
+// ResolvePlayer resolves an identifier like ResolveVoteIdentifier, but for looking up an existing player:
+// found is false when the identifier matches neither a nickname of the club nor anyone who has voted.
+func (s *Service) ResolvePlayer(club Club, identifier string) (userID int64, username, displayName string, found bool, err error) {
+	userID, username, displayName, err = s.ResolveVoteIdentifier(club, identifier)
+	if err != nil {
+		return 0, "", "", false, err
+	}
+	if userID > 0 {
+		return userID, username, displayName, true, nil
+	}
+
+	// A synthetic ID still names a player if it comes from a nickname or has manual votes
+	if strings.HasPrefix(identifier, "@") {
+		nick, _, err := s.nicknames.FindByTgUsername(club, username)
+		if err != nil {
+			return 0, "", "", false, err
+		}
+		found = nick != ""
+	} else {
+		found = username != "" // only a nickname match yields a username
+	}
+	if !found {
+		if _, found, err = s.votes.LookupLatestChatID(userID); err != nil {
+			return 0, "", "", false, err
+		}
+	}
+	return userID, username, displayName, found, nil
+}
+
+// MatchNickname finds the game nickname visible in the club that identifier most likely means,
+// tolerating case, Cyrillic/Latin transliteration and typos.
+// certain is true when the nickname differs from identifier only by case and can be used as is;
+// otherwise the match is a guess to be confirmed. Returns "" if no nickname is close enough.
+func (s *Service) MatchNickname(club Club, identifier string) (nick string, certain bool, err error) {
+	nicknames, err := s.nicknames.List(club)
+	if err != nil {
+		return "", false, err
+	}
+
+	nicks := make([]string, len(nicknames))
+	for i, n := range nicknames {
+		if strings.EqualFold(n.Nick, identifier) {
+			return n.Nick, true, nil
+		}
+		nicks[i] = n.Nick
+	}
+
+	nick, found := ClosestNickname(identifier, nicks)
+	if !found {
+		return "", false, nil
+	}
+	return nick, false, nil
+}
+
 // BackfillVotesForNickname updates votes in the active poll to use the canonical user ID.
 // Called after creating a nickname to consolidate votes.
 // Also updates tg_username on synthetic votes so they can be properly displayed with mentions.
@@ -389,9 +781,9 @@ func (s *Service) EnsureUserDataConsistency(chatID int64, userID int64, username
 	return s.votes.ConsolidateSyntheticVotes(p.ID, userID, username, gameNicks)
 }
 
-// GetDisplayNick returns the game nickname for a user, if one exists.
-func (s *Service) GetDisplayNick(userID int64, username string) (string, error) {
-	return s.nicknames.GetDisplayNick(userID, username)
+// GetDisplayNick returns the game nickname of a user in the club, if one exists.
+func (s *Service) GetDisplayNick(club Club, userID int64, username string) (string, error) {
+	return s.nicknames.GetDisplayNick(club, userID, username)
 }
 
 // GetAllGameNicksForUser returns all game nicks for a user.
@@ -438,9 +830,9 @@ func (c *NicknameCache) GetDisplayNick(userID int64, username string) string {
 	return info.DisplayNick()
 }
 
-// NewNicknameCache creates a cache pre-populated with nicknames for the given keys.
-func (s *Service) NewNicknameCache(keys []NicknameLookupKey) (*NicknameCache, error) {
-	byUserID, byUsername, err := s.nicknames.GetDisplayNicksBatch(keys)
+// NewNicknameCache creates a cache pre-populated with the club's nicknames for the given keys.
+func (s *Service) NewNicknameCache(club Club, keys []NicknameLookupKey) (*NicknameCache, error) {
+	byUserID, byUsername, err := s.nicknames.GetDisplayNicksBatch(club, keys)
 	if err != nil {
 		return nil, err
 	}
@@ -450,16 +842,391 @@ func (s *Service) NewNicknameCache(keys []NicknameLookupKey) (*NicknameCache, er
 	}, nil
 }
 
-// NewNicknameCacheFromVotes creates a cache for the users in the given votes.
-func (s *Service) NewNicknameCacheFromVotes(votes []*Vote) (*NicknameCache, error) {
-	keys := make([]NicknameLookupKey, len(votes))
-	for i, v := range votes {
-		keys[i] = NicknameLookupKey{
+// NewNicknameCacheFromVotes creates a cache of the club's nicknames for the users in the given votes.
+// Admins who entered manual votes are included so they can be displayed by nickname too.
+func (s *Service) NewNicknameCacheFromVotes(club Club, votes []*Vote) (*NicknameCache, error) {
+	keys := make([]NicknameLookupKey, 0, len(votes))
+	for _, v := range votes {
+		keys = append(keys, NicknameLookupKey{
 			UserID:   v.TgUserID,
 			Username: v.TgUsername,
+		})
+		if v.ActorUserID > 0 {
+			keys = append(keys, NicknameLookupKey{UserID: v.ActorUserID})
 		}
 	}
-	return s.NewNicknameCache(keys)
+	return s.NewNicknameCache(club, keys)
+}
+
+// PlayerIdentityIDs returns every user ID a player's votes may be recorded under:
+// the real Telegram ID plus synthetic IDs derived from the username and game nicks
+// (created by manual /vote before the player was linked).
+func (s *Service) PlayerIdentityIDs(userID int64, username string) ([]int64, error) {
+	var nicks []string
+	if userID > 0 || username != "" {
+		var err error
+		if nicks, err = s.nicknames.GetAllGameNicksForUser(userID, username); err != nil {
+			return nil, err
+		}
+	}
+	return identityIDs(userID, username, nicks), nil
+}
+
+// identityIDs lists the IDs of a player with the given game nicks, see PlayerIdentityIDs.
+func identityIDs(userID int64, username string, nicks []string) []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	add := func(id int64) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	add(userID)
+	if username != "" {
+		add(ManualUserID(username))
+		add(ManualUserID(NormalizeUsername(username)))
+	}
+	for _, nick := range nicks {
+		add(ManualUserID(nick))
+	}
+	return ids
+}
+
+// playerAliases maps the synthetic IDs of the club's players to the ID each player is known by,
+// so that statistics of all players at once merge identities like GetPlayerStats does.
+func (s *Service) playerAliases(club Club) (map[int64]int64, error) {
+	usernames, err := s.stats.GetVoterUsernames(club)
+	if err != nil {
+		return nil, err
+	}
+	nicknames, err := s.nicknames.List(club)
+	if err != nil {
+		return nil, err
+	}
+	return identityAliases(usernames, nicknames), nil
+}
+
+// identityAliases maps synthetic IDs to the player they belong to, by the same identities
+// as PlayerIdentityIDs: a player is known by the real ID where there is one,
+// otherwise by the synthetic ID of their username.
+// usernames maps real IDs of voters to their latest username.
+func identityAliases(usernames map[int64]string, nicknames []*Nickname) map[int64]int64 {
+	type player struct {
+		userID   int64
+		username string
+		nicks    []string
+	}
+	players := make(map[int64]*player) // by the ID the player is known by
+	byUsername := make(map[string]*player)
+	for userID, username := range usernames {
+		p := &player{userID: userID, username: username}
+		players[userID] = p
+		if username != "" {
+			byUsername[NormalizeUsername(username)] = p
+		}
+	}
+	for _, n := range nicknames {
+		var p *player
+		switch {
+		case n.TgUserID > 0:
+			if p = players[n.TgUserID]; p == nil {
+				p = &player{userID: n.TgUserID, username: n.TgUsername}
+				players[n.TgUserID] = p
+			}
+		case n.TgUsername != "":
+			if p = byUsername[n.TgUsername]; p == nil {
+				p = &player{username: n.TgUsername}
+				players[ManualUserID(n.TgUsername)] = p
+				byUsername[n.TgUsername] = p
+			}
+		default:
+			continue
+		}
+		p.nicks = append(p.nicks, n.Nick)
+	}
+
+	// Iterate in a fixed order so that an ID claimed by two players always goes to the same one
+	aliases := make(map[int64]int64)
+	for _, id := range slices.Sorted(maps.Keys(players)) {
+		p := players[id]
+		for _, alias := range identityIDs(p.userID, p.username, p.nicks) {
+			if _, claimed := aliases[alias]; alias != id && alias < 0 && !claimed {
+				aliases[alias] = id
+			}
+		}
+	}
+	return aliases
+}
+
+// FindVote returns the player's current vote in a poll, cast under any of their identities
+// (see PlayerIdentityIDs), or nil if the player has not voted.
+func (s *Service) FindVote(pollID, userID int64, username string) (*Vote, error) {
+	ids, err := s.PlayerIdentityIDs(userID, username)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.votes.GetCurrentVotes(pollID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		if slices.Contains(ids, v.TgUserID) {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetPlayerStats returns aggregated statistics for a player in a club.
+// The player is identified by all of their known identities (see PlayerIdentityIDs).
+func (s *Service) GetPlayerStats(club Club, userID int64, username string, period StatsPeriod) (*PlayerStats, error) {
+	ids, err := s.PlayerIdentityIDs(userID, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.stats.GetPlayerStats(club, ids, period)
+}
+
+// GetLeaderboard returns the players who played in a club within a period with their statistics,
+// ordered by number of events played (descending). At most limit entries are returned.
+// Votes under a player's synthetic IDs count as theirs, as in GetPlayerStats.
+func (s *Service) GetLeaderboard(club Club, period StatsPeriod, limit int) ([]*LeaderboardEntry, error) {
+	aliases, err := s.playerAliases(club)
+	if err != nil {
+		return nil, err
+	}
+	return s.stats.GetLeaderboard(club, period, aliases, limit)
+}
+
+// GetPollVotes returns every player's latest vote in each poll of a club within a period,
+// with votes under a player's synthetic IDs merged as in GetLeaderboard.
+func (s *Service) GetPollVotes(club Club, period StatsPeriod) ([]*PollVote, error) {
+	aliases, err := s.playerAliases(club)
+	if err != nil {
+		return nil, err
+	}
+	return s.stats.GetPollVotes(club, period, aliases)
+}
+
+// GetClubSummary returns aggregated club activity within a period.
+// Players are counted with their guests, with identities merged as in GetLeaderboard.
+// Each event is split into tables of tableSize as in SplitIntoTables (0 = a single table).
+func (s *Service) GetClubSummary(club Club, period StatsPeriod, tableSize int) (*ClubSummary, error) {
+	aliases, err := s.playerAliases(club)
+	if err != nil {
+		return nil, err
+	}
+	return s.stats.GetClubSummary(club, period, aliases, tableSize)
+}
+
+// MarkReportPosted records that a periodic report was posted to a chat.
+// Returns false if the report for this chat and period was already posted.
+func (s *Service) MarkReportPosted(chatID int64, period string) (bool, error) {
+	return s.stats.MarkReportPosted(chatID, period)
+}
+
+// UnmarkReportPosted forgets a report mark so that the report is posted again, e.g. after it failed to send.
+func (s *Service) UnmarkReportPosted(chatID int64, period string) error {
+	return s.stats.UnmarkReportPosted(chatID, period)
+}
+
+// RecordGame validates and stores a game result.
+// Returns ErrInvalidPlayerCount, ErrDuplicatePlayer, ErrInvalidRoles or ErrJudgeIsPlayer for invalid games.
+func (s *Service) RecordGame(g *Game) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	return s.games.Create(g)
+}
+
+// GetGames returns all games played on an event night.
+func (s *Service) GetGames(pollID int64) ([]*Game, error) {
+	return s.games.GetByPoll(pollID)
+}
+
+// DeleteGame removes a game of an event night by its number.
+// Returns ErrGameNotFound if there is no such game.
+func (s *Service) DeleteGame(pollID int64, number int) error {
+	deleted, err := s.games.Delete(pollID, number)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGameNotFound
+	}
+	return nil
+}
+
+// GetRatings computes player ratings from all club games within a period.
+// Games played under a player's synthetic IDs count as theirs, as in GetLeaderboard.
+func (s *Service) GetRatings(club Club, period StatsPeriod) ([]*PlayerRating, error) {
+	games, err := s.games.GetByClub(club, period)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.playerAliases(club)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range games {
+		for _, p := range g.Participants {
+			if id, ok := aliases[p.TgUserID]; ok {
+				p.TgUserID = id
+			}
+		}
+	}
+	return CalculateRatings(games), nil
+}
+
+// SeatPlayers randomly seats players for an event and saves the seating.
+// Any previous seating (and dealt roles) of the poll is replaced.
+func (s *Service) SeatPlayers(pollID int64, players []*GameParticipant) ([]*GameParticipant, error) {
+	seated := ShuffleSeats(players)
+	if err := s.seating.Save(pollID, seated); err != nil {
+		return nil, err
+	}
+	return seated, nil
+}
+
+// DealSeatedRoles randomly deals roles to the seated players of a poll and saves them.
+// Returns an empty slice if players were not seated yet.
+func (s *Service) DealSeatedRoles(pollID int64) ([]*GameParticipant, error) {
+	players, err := s.seating.GetByPoll(pollID)
+	if err != nil || len(players) == 0 {
+		return players, err
+	}
+	if err := DealRoles(players); err != nil {
+		return nil, err
+	}
+	if err := s.seating.Save(pollID, players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// SetJudge assigns the event host of a poll. A zero userID clears the judge.
+func (s *Service) SetJudge(p *Poll, userID int64, name string) error {
+	p.JudgeUserID = userID
+	p.JudgeName = name
+	if userID == 0 {
+		p.JudgeName = ""
+	}
+	return s.polls.Update(p)
+}
+
+// SuggestJudge suggests the next event host from a judge pool: the member who
+// hosted least recently in the club (see NextJudge). Returns false if the pool is empty.
+func (s *Service) SuggestJudge(club Club, pool []int64) (JudgeSuggestion, bool, error) {
+	lastHosted, err := s.polls.GetJudgeLastHosted(club, pool)
+	if err != nil {
+		return JudgeSuggestion{}, false, err
+	}
+	suggestion, found := NextJudge(pool, lastHosted)
+	return suggestion, found, nil
+}
+
+// SetPrice sets the per-player price of an event. A zero price clears it.
+func (s *Service) SetPrice(p *Poll, price int) error {
+	p.Price = price
+	return s.polls.Update(p)
+}
+
+// RecordPayment marks a player as having paid the event price.
+// Paying again overwrites the previous payment. Returns ErrNoPrice if the event has no price set.
+func (s *Service) RecordPayment(p *Poll, userID, actorUserID int64) error {
+	if p.Price <= 0 {
+		return ErrNoPrice
+	}
+	return s.payments.Record(&Payment{
+		PollID:      p.ID,
+		TgUserID:    userID,
+		Amount:      p.Price,
+		ActorUserID: actorUserID,
+		PaidAt:      time.Now(),
+	})
+}
+
+// RemovePayment deletes a player's payment for an event.
+// Returns ErrPaymentNotFound if the player has not paid.
+func (s *Service) RemovePayment(pollID, userID int64) error {
+	deleted, err := s.payments.Delete(pollID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPaymentNotFound
+	}
+	return nil
+}
+
+// GetUnpaidVotes returns attending voters who have not paid for the event.
+// Players marked as no-shows are not expected to pay and are excluded.
+func (s *Service) GetUnpaidVotes(pollID int64) ([]*Vote, error) {
+	entries, err := s.GetAttendanceEntries(pollID)
+	if err != nil {
+		return nil, err
+	}
+	paid, err := s.payments.GetPaidUserIDs(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unpaid []*Vote
+	for _, e := range entries {
+		if e.Attended && !paid[e.Vote.TgUserID] {
+			unpaid = append(unpaid, e.Vote)
+		}
+	}
+	return unpaid, nil
+}
+
+// SetGuests sets how many guests a player brings to an event. Zero removes the guests.
+// Returns ErrInvalidGuestCount if count is outside 0..MaxGuests and ErrHostNotAttending
+// if guests are added for a player whose current vote is not attending.
+func (s *Service) SetGuests(pollID, hostUserID int64, count int, actorUserID int64) error {
+	if count < 0 || count > MaxGuests {
+		return ErrInvalidGuestCount
+	}
+	if count > 0 {
+		votes, err := s.votes.GetCurrentVotes(pollID)
+		if err != nil {
+			return err
+		}
+		attending := false
+		for _, v := range votes {
+			if v.TgUserID == hostUserID {
+				attending = v.OptionKind().IsAttending()
+				break
+			}
+		}
+		if !attending {
+			return ErrHostNotAttending
+		}
+	}
+	return s.guests.Set(pollID, hostUserID, count, actorUserID)
+}
+
+// currentVotes returns the current votes of a poll with the voters' guests attached.
+func (s *Service) currentVotes(pollID int64) ([]*Vote, error) {
+	votes, err := s.votes.GetCurrentVotes(pollID)
+	if err != nil {
+		return nil, err
+	}
+	guests, err := s.guests.GetByPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		v.Guests = guests[v.TgUserID]
+	}
+	return votes, nil
+}
+
+// LookupLatestChatID finds the chat of the poll a user voted in most recently.
+func (s *Service) LookupLatestChatID(userID int64) (int64, bool, error) {
+	return s.votes.LookupLatestChatID(userID)
 }
 
 // LookupUserIDByUsername finds a user ID from vote history by username.
@@ -511,6 +1278,96 @@ func (s *Service) GetUndecidedVotes(pollID int64) ([]*Vote, error) {
 	return undecided, nil
 }
 
+// StartAttendance creates attendance records for everyone who voted to attend
+// and returns the current attendance list. Previously toggled records are preserved,
+// so running it again resumes where the host left off.
+func (s *Service) StartAttendance(pollID int64) ([]*AttendanceEntry, error) {
+	votes, err := s.GetAttendingVotes(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, len(votes))
+	for i, v := range votes {
+		userIDs[i] = v.TgUserID
+	}
+	if err := s.attendance.Init(pollID, userIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetAttendanceEntries(pollID)
+}
+
+// GetAttendanceEntries returns attending voters paired with their attendance status.
+// Voters without an attendance record are considered attended.
+func (s *Service) GetAttendanceEntries(pollID int64) ([]*AttendanceEntry, error) {
+	votes, err := s.GetAttendingVotes(pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.attendance.GetByPoll(pollID)
+	if err != nil {
+		return nil, err
+	}
+	attended := make(map[int64]bool, len(records))
+	for _, a := range records {
+		attended[a.TgUserID] = a.Attended
+	}
+
+	entries := make([]*AttendanceEntry, 0, len(votes))
+	for _, v := range votes {
+		status, ok := attended[v.TgUserID]
+		entries = append(entries, &AttendanceEntry{
+			Vote:     v,
+			Attended: !ok || status,
+		})
+	}
+	return entries, nil
+}
+
+// ToggleAttendance flips whether a player attended the event.
+// actorUserID is the admin making the change. Returns the new status,
+// or ErrNotAttendingVoter if the user is not among the poll's attending voters.
+func (s *Service) ToggleAttendance(pollID, userID, actorUserID int64) (bool, error) {
+	attending, err := s.GetAttendingVotes(pollID)
+	if err != nil {
+		return false, err
+	}
+	if !slices.ContainsFunc(attending, func(v *Vote) bool { return v.TgUserID == userID }) {
+		return false, ErrNotAttendingVoter
+	}
+	return s.attendance.Toggle(pollID, userID, actorUserID)
+}
+
+// GetNoShowCounts returns how many events each voter missed after voting to attend,
+// across all polls of the club, with identities merged as in GetPlayerStats.
+// The map is keyed by the votes' user IDs; voters without no-shows are absent from it.
+func (s *Service) GetNoShowCounts(club Club, votes []*Vote) (map[int64]int, error) {
+	aliases, err := s.playerAliases(club)
+	if err != nil {
+		return nil, err
+	}
+	playerIDs := make([]int64, len(votes))
+	for i, v := range votes {
+		playerIDs[i] = v.TgUserID
+		if id, ok := aliases[v.TgUserID]; ok {
+			playerIDs[i] = id
+		}
+	}
+	counts, err := s.attendance.CountNoShows(club, playerIDs, aliases)
+	if err != nil {
+		return nil, err
+	}
+	noShows := make(map[int64]int)
+	for i, v := range votes {
+		if n := counts[playerIDs[i]]; n > 0 {
+			noShows[v.TgUserID] = n
+		}
+	}
+	return noShows, nil
+}
+
 // CollectedData holds data for the /done command (collected enough players)
 type CollectedData struct {
 	Votes19 []*Vote // voters for 19:00
@@ -520,10 +1377,10 @@ type CollectedData struct {
 
 // StartTimeResult holds the result of determining start time and voter groups.
 type StartTimeResult struct {
-	EnoughPlayers bool     // true if minimum players requirement is met
-	StartTime     string   // "19:00" or "20:00" (empty if not enough players)
-	MainVoters    []*Vote  // voters to mention (starting at StartTime)
-	ComingLater   []*Vote  // voters coming later (21:00+ or 20:00+21:00 if starting at 19:00)
+	EnoughPlayers bool    // true if minimum players requirement is met
+	StartTime     string  // "19:00" or "20:00" (empty if not enough players)
+	MainVoters    []*Vote // voters to mention (starting at StartTime)
+	ComingLater   []*Vote // voters coming later (21:00+ or 20:00+21:00 if starting at 19:00)
 }
 
 // DetermineStartTimeAndVoters determines the game start time based on voter counts.
@@ -531,10 +1388,11 @@ type StartTimeResult struct {
 // 20:00+21:00 voters are coming later.
 // Otherwise, if 19:00+20:00 combined >= minPlayers, start at 20:00 and only
 // 21:00+ voters are coming later.
+// Guests count as players at their host's time (see HeadCount).
 // Returns EnoughPlayers=false if neither threshold is met.
 func DetermineStartTimeAndVoters(data *CollectedData, minPlayers int) StartTimeResult {
-	count19 := len(data.Votes19)
-	count20 := len(data.Votes20)
+	count19 := HeadCount(data.Votes19)
+	count20 := HeadCount(data.Votes20)
 	totalEarly := count19 + count20
 
 	if count19 >= minPlayers {
@@ -598,7 +1456,7 @@ func SplitVotersByStartTime(data *CollectedData, startTime string) (mainVoters,
 
 // GetCollectedData returns votes for 19:00, 20:00, and 21:00+ options
 func (s *Service) GetCollectedData(pollID int64) (*CollectedData, error) {
-	votes, err := s.votes.GetCurrentVotes(pollID)
+	votes, err := s.currentVotes(pollID)
 	if err != nil {
 		return nil, err
 	}
@@ -625,7 +1483,7 @@ func (s *Service) GetCollectedData(pollID int64) (*CollectedData, error) {
 
 // GetInvitationData returns results formatted for the invitation message
 func (s *Service) GetInvitationData(pollID int64) (*InvitationData, error) {
-	votes, err := s.votes.GetCurrentVotes(pollID)
+	votes, err := s.currentVotes(pollID)
 	if err != nil {
 		return nil, err
 	}
@@ -644,7 +1502,7 @@ func (s *Service) GetInvitationData(pollID int64) (*InvitationData, error) {
 			results.ComingLater = append(results.ComingLater, v)
 		case OptionDecideLater:
 			results.Undecided = append(results.Undecided, v)
-		// OptionNotComing is not displayed
+			// OptionNotComing is not displayed
 		}
 	}
 
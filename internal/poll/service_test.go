@@ -1,6 +1,8 @@
 package poll
 
 import (
+	"errors"
+	"sort"
 	"testing"
 	"time"
 )
@@ -47,6 +49,10 @@ func (m *mockPollRepo) GetLatest(chatID int64) (*Poll, error) {
 	return latest, nil
 }
 
+func (m *mockPollRepo) GetByID(id int64) (*Poll, error) {
+	return m.polls[id], nil
+}
+
 func (m *mockPollRepo) GetByTgPollID(tgPollID string) (*Poll, error) {
 	for _, p := range m.polls {
 		if p.TgPollID == tgPollID {
@@ -61,6 +67,21 @@ func (m *mockPollRepo) Update(p *Poll) error {
 	return nil
 }
 
+func (m *mockPollRepo) GetJudgeLastHosted(club Club, userIDs []int64) (map[int64]time.Time, error) {
+	return map[int64]time.Time{}, nil
+}
+
+func (m *mockPollRepo) ListByClub(club Club, period StatsPeriod) ([]*Poll, error) {
+	var polls []*Poll
+	for _, p := range m.polls {
+		if p.Club == club {
+			polls = append(polls, p)
+		}
+	}
+	sort.Slice(polls, func(i, j int) bool { return polls[i].ID < polls[j].ID })
+	return polls, nil
+}
+
 type mockVoteRepo struct {
 	votes []*Vote
 }
@@ -70,6 +91,11 @@ func (m *mockVoteRepo) Record(v *Vote) error {
 	return nil
 }
 
+func (m *mockVoteRepo) RecordAll(votes []*Vote) error {
+	m.votes = append(m.votes, votes...)
+	return nil
+}
+
 func (m *mockVoteRepo) GetCurrentVotes(pollID int64) ([]*Vote, error) {
 	latest := make(map[int64]*Vote)
 	for _, v := range m.votes {
@@ -95,6 +121,15 @@ func (m *mockVoteRepo) LookupUsernameByUserID(userID int64) (string, bool, error
 	return "", false, nil
 }
 
+func (m *mockVoteRepo) LookupLatestChatID(userID int64) (int64, bool, error) {
+	for _, v := range m.votes {
+		if v.TgUserID == userID {
+			return 0, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
 func (m *mockVoteRepo) UpdateVotesUserID(pollID int64, oldUserID, newUserID int64, tgUsername string) error {
 	return nil
 }
@@ -103,25 +138,142 @@ func (m *mockVoteRepo) ConsolidateSyntheticVotes(pollID int64, realUserID int64,
 	return nil
 }
 
-type mockNicknameRepo struct{}
+type mockGuestRepo struct {
+	guests map[int64]int // host user ID -> guests (single poll)
+}
+
+func (m *mockGuestRepo) Set(pollID, hostUserID int64, count int, actorUserID int64) error {
+	if m.guests == nil {
+		m.guests = make(map[int64]int)
+	}
+	m.guests[hostUserID] = count
+	return nil
+}
+
+func (m *mockGuestRepo) GetByPoll(pollID int64) (map[int64]int, error) {
+	return m.guests, nil
+}
 
-func (m *mockNicknameRepo) Create(tgUserID *int64, tgUsername *string, gameNick string, gender string) (bool, error) {
+// mockAttendanceRepo records toggled users; every user starts as attended.
+type mockAttendanceRepo struct {
+	noShows map[int64]bool
+}
+
+func (m *mockAttendanceRepo) Init(pollID int64, userIDs []int64) error { return nil }
+
+func (m *mockAttendanceRepo) Toggle(pollID, userID, actorUserID int64) (bool, error) {
+	if m.noShows == nil {
+		m.noShows = make(map[int64]bool)
+	}
+	m.noShows[userID] = !m.noShows[userID]
+	return !m.noShows[userID], nil
+}
+
+func (m *mockAttendanceRepo) GetByPoll(pollID int64) ([]*Attendance, error) { return nil, nil }
+
+func (m *mockAttendanceRepo) CountNoShows(club Club, playerIDs []int64, aliases map[int64]int64) (map[int64]int, error) {
+	return nil, nil
+}
+
+type mockNickRequestRepo struct {
+	requests map[int64]*NicknameRequest
+}
+
+func (m *mockNickRequestRepo) Create(req *NicknameRequest) error {
+	if m.requests == nil {
+		m.requests = make(map[int64]*NicknameRequest)
+	}
+	req.ID = int64(len(m.requests) + 1)
+	req.Status = NicknameRequestPending
+	stored := *req
+	m.requests[req.ID] = &stored
+	return nil
+}
+
+func (m *mockNickRequestRepo) GetByID(id int64) (*NicknameRequest, error) {
+	req, ok := m.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *req
+	return &copied, nil
+}
+
+func (m *mockNickRequestRepo) HasPending(chatID, userID int64) (bool, error) {
+	for _, req := range m.requests {
+		if req.TgChatID == chatID && req.TgUserID == userID && req.Status == NicknameRequestPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockNickRequestRepo) Decide(id int64, from, status NicknameRequestStatus, actorUserID int64) (bool, error) {
+	req, ok := m.requests[id]
+	if !ok || req.Status != from {
+		return false, nil
+	}
+	req.Status = status
+	req.DecidedBy = actorUserID
+	return true, nil
+}
+
+type mockNicknameRepo struct {
+	nicknames []*Nickname
+}
+
+func (m *mockNicknameRepo) Create(club Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
 	return true, nil
 }
 
-func (m *mockNicknameRepo) FindByGameNick(gameNick string) (*int64, *string, error) {
+func (m *mockNicknameRepo) Exists(club Club, gameNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepo) Get(club Club, gameNick string) (*Nickname, error) {
+	return nil, nil
+}
+
+func (m *mockNicknameRepo) FindByGameNick(club Club, gameNick string) (*int64, *string, error) {
+	for _, n := range m.nicknames {
+		if n.Nick != gameNick {
+			continue
+		}
+		var userID *int64
+		var username *string
+		if n.TgUserID != 0 {
+			userID = &n.TgUserID
+		}
+		if n.TgUsername != "" {
+			username = &n.TgUsername
+		}
+		return userID, username, nil
+	}
 	return nil, nil, nil
 }
 
-func (m *mockNicknameRepo) FindByTgUsername(username string) (string, *int64, error) {
+func (m *mockNicknameRepo) FindByTgUsername(club Club, username string) (string, *int64, error) {
+	for _, n := range m.nicknames {
+		if n.TgUsername == username {
+			if n.TgUserID != 0 {
+				return n.Nick, &n.TgUserID, nil
+			}
+			return n.Nick, nil, nil
+		}
+	}
 	return "", nil, nil
 }
 
-func (m *mockNicknameRepo) FindByTgUserID(userID int64) (string, error) {
+func (m *mockNicknameRepo) FindByTgUserID(club Club, userID int64) (string, error) {
+	for _, n := range m.nicknames {
+		if n.TgUserID == userID {
+			return n.Nick, nil
+		}
+	}
 	return "", nil
 }
 
-func (m *mockNicknameRepo) GetDisplayNick(userID int64, username string) (string, error) {
+func (m *mockNicknameRepo) GetDisplayNick(club Club, userID int64, username string) (string, error) {
 	return "", nil
 }
 
@@ -137,7 +289,23 @@ func (m *mockNicknameRepo) GetAllGameNicksForUser(userID int64, username string)
 	return nil, nil
 }
 
-func (m *mockNicknameRepo) GetDisplayNicksBatch(keys []NicknameLookupKey) (map[int64]NicknameInfo, map[string]NicknameInfo, error) {
+func (m *mockNicknameRepo) List(club Club) ([]*Nickname, error) {
+	return m.nicknames, nil
+}
+
+func (m *mockNicknameRepo) Delete(club Club, gameNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepo) Rename(club Club, oldNick, newNick string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepo) SetGender(club Club, gameNick string, gender string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockNicknameRepo) GetDisplayNicksBatch(club Club, keys []NicknameLookupKey) (map[int64]NicknameInfo, map[string]NicknameInfo, error) {
 	return make(map[int64]NicknameInfo), make(map[string]NicknameInfo), nil
 }
 
@@ -145,9 +313,14 @@ func TestService_CreatePoll(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
-	result, err := svc.CreatePoll(-123456, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ClubVanmo)
+	result, err := svc.CreatePoll(-123456, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ClubVanmo, 0)
 	if err != nil {
 		t.Fatalf("CreatePoll failed: %v", err)
 	}
@@ -163,9 +336,14 @@ func TestService_GetInvitationData(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
-	result, _ := svc.CreatePoll(-123456, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ClubVanmo)
+	result, _ := svc.CreatePoll(-123456, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), ClubVanmo, 0)
 	p := result.Poll
 
 	// Add votes: 19:00, 20:00, 21:00+, decide later
@@ -196,13 +374,18 @@ func TestIntegration_PollVoteResultsFlow(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
 	chatID := int64(-123456)
 	futureDate := time.Now().AddDate(0, 0, 7) // 1 week from now
 
 	// Step 1: Create poll
-	result, err := svc.CreatePoll(chatID, futureDate, ClubVanmo)
+	result, err := svc.CreatePoll(chatID, futureDate, ClubVanmo, 0)
 	if err != nil {
 		t.Fatalf("CreatePoll failed: %v", err)
 	}
@@ -264,13 +447,18 @@ func TestIntegration_CancelRestoreFlow(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
 	chatID := int64(-123456)
 	futureDate := time.Now().AddDate(0, 0, 7) // 1 week from now
 
 	// Step 1: Create poll
-	result, err := svc.CreatePoll(chatID, futureDate, ClubVanmo)
+	result, err := svc.CreatePoll(chatID, futureDate, ClubVanmo, 0)
 	if err != nil {
 		t.Fatalf("CreatePoll failed: %v", err)
 	}
@@ -312,24 +500,49 @@ func TestIntegration_CancelRestoreFlow(t *testing.T) {
 	}
 }
 
+func TestService_CancelPoll_DatePassed(t *testing.T) {
+	chatID := int64(-123456)
+	pollRepo := &mockPollRepo{polls: map[int64]*Poll{
+		1: {ID: 1, TgChatID: chatID, EventDate: time.Now().AddDate(0, 0, -1), IsActive: true},
+	}}
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     &mockVoteRepo{},
+		Nicknames: &mockNicknameRepo{},
+		Guests:    &mockGuestRepo{},
+	})
+
+	if _, err := svc.CancelPoll(chatID); err != ErrPollDatePassed {
+		t.Errorf("CancelPoll() error = %v, want ErrPollDatePassed", err)
+	}
+	if !pollRepo.polls[1].IsActive {
+		t.Error("past poll should stay active")
+	}
+}
+
 // Integration test: Duplicate poll prevention
 func TestIntegration_DuplicatePollPrevention(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
 	chatID := int64(-123456)
 	futureDate := time.Now().AddDate(0, 0, 7)
 
 	// Create first poll
-	_, err := svc.CreatePoll(chatID, futureDate, ClubVanmo)
+	_, err := svc.CreatePoll(chatID, futureDate, ClubVanmo, 0)
 	if err != nil {
 		t.Fatalf("First CreatePoll failed: %v", err)
 	}
 
 	// Try to create second poll - should fail
-	_, err = svc.CreatePoll(chatID, futureDate, ClubVanmo)
+	_, err = svc.CreatePoll(chatID, futureDate, ClubVanmo, 0)
 	if err != ErrPollExists {
 		t.Errorf("expected ErrPollExists for duplicate poll, got %v", err)
 	}
@@ -340,12 +553,17 @@ func TestIntegration_GetVotes(t *testing.T) {
 	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
 	voteRepo := &mockVoteRepo{}
 	nickRepo := &mockNicknameRepo{}
-	svc := NewService(pollRepo, voteRepo, nickRepo)
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nickRepo,
+		Guests:    &mockGuestRepo{},
+	})
 
 	chatID := int64(-123456)
 	futureDate := time.Now().AddDate(0, 0, 7)
 
-	result, _ := svc.CreatePoll(chatID, futureDate, ClubVanmo)
+	result, _ := svc.CreatePoll(chatID, futureDate, ClubVanmo, 0)
 	poll := result.Poll
 
 	now := time.Now()
@@ -436,6 +654,20 @@ func TestPoll_PopulateInvitationData(t *testing.T) {
 	}
 }
 
+func TestPoll_HasInlineVoting(t *testing.T) {
+	native := &Poll{TgPollID: "poll", TgMessageID: 10, TgInvitationMessageID: 9}
+	if native.HasInlineVoting() {
+		t.Error("expected native poll not to use inline voting")
+	}
+	inline := &Poll{TgMessageID: 9, TgInvitationMessageID: 9}
+	if !inline.HasInlineVoting() {
+		t.Error("expected invitation-only poll to use inline voting")
+	}
+	if (&Poll{}).HasInlineVoting() {
+		t.Error("expected unsent poll not to use inline voting")
+	}
+}
+
 func TestDetermineStartTimeAndVoters(t *testing.T) {
 	// Helper to create N votes
 	makeVotes := func(n int) []*Vote {
@@ -512,6 +744,17 @@ func TestDetermineStartTimeAndVoters(t *testing.T) {
 			wantLaterCount: 0,
 			wantEnough:     true,
 		},
+		{
+			name:           "guests count towards 19:00",
+			votes19:        append(makeVotes(9), &Vote{TgUserID: 100, Guests: 2}),
+			votes20:        makeVotes(1),
+			votes21:        nil,
+			minPlayers:     11,
+			wantStartTime:  "19:00",
+			wantMainCount:  10, // voters, guests are attached to their host
+			wantLaterCount: 1,
+			wantEnough:     true,
+		},
 		{
 			name:           "no votes at all",
 			votes19:        nil,
@@ -550,3 +793,342 @@ func TestDetermineStartTimeAndVoters(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ToggleAttendance(t *testing.T) {
+	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
+	voteRepo := &mockVoteRepo{}
+	attendanceRepo := &mockAttendanceRepo{}
+	svc := NewService(Repositories{
+		Polls:      pollRepo,
+		Votes:      voteRepo,
+		Nicknames:  &mockNicknameRepo{},
+		Attendance: attendanceRepo,
+		Guests:     &mockGuestRepo{},
+	})
+
+	result, _ := svc.CreatePoll(-123456, time.Now().AddDate(0, 0, 1), ClubVanmo, 0)
+	p := result.Poll
+	svc.RecordVote(&Vote{PollID: p.ID, TgUserID: 1, TgFirstName: "Player", TgOptionIndex: int(OptionComeAt19)})
+	svc.RecordVote(&Vote{PollID: p.ID, TgUserID: 2, TgFirstName: "Maybe", TgOptionIndex: int(OptionDecideLater)})
+
+	attended, err := svc.ToggleAttendance(p.ID, 1, 0)
+	if err != nil || attended {
+		t.Errorf("ToggleAttendance(attending voter) = %v, %v, want false, nil", attended, err)
+	}
+	for _, userID := range []int64{2, 3} {
+		if _, err := svc.ToggleAttendance(p.ID, userID, 0); !errors.Is(err, ErrNotAttendingVoter) {
+			t.Errorf("ToggleAttendance(%d) = %v, want ErrNotAttendingVoter", userID, err)
+		}
+	}
+	if len(attendanceRepo.noShows) != 1 {
+		t.Errorf("toggled users = %v, want only 1", attendanceRepo.noShows)
+	}
+}
+
+func TestService_SetGuests(t *testing.T) {
+	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
+	voteRepo := &mockVoteRepo{}
+	nickRepo := &mockNicknameRepo{}
+	guestRepo := &mockGuestRepo{}
+	svc := NewService(Repositories{Polls: pollRepo, Votes: voteRepo, Nicknames: nickRepo, Guests: guestRepo})
+
+	result, _ := svc.CreatePoll(-123456, time.Now().AddDate(0, 0, 1), ClubVanmo, 0)
+	p := result.Poll
+	svc.RecordVote(&Vote{PollID: p.ID, TgUserID: 1, TgFirstName: "Host", TgOptionIndex: int(OptionComeAt19)})
+	svc.RecordVote(&Vote{PollID: p.ID, TgUserID: 2, TgFirstName: "Maybe", TgOptionIndex: int(OptionDecideLater)})
+
+	if err := svc.SetGuests(p.ID, 1, MaxGuests+1, 0); !errors.Is(err, ErrInvalidGuestCount) {
+		t.Errorf("SetGuests(too many) = %v, want ErrInvalidGuestCount", err)
+	}
+	if err := svc.SetGuests(p.ID, 2, 1, 0); !errors.Is(err, ErrHostNotAttending) {
+		t.Errorf("SetGuests(undecided host) = %v, want ErrHostNotAttending", err)
+	}
+	if err := svc.SetGuests(p.ID, 1, 2, 0); err != nil {
+		t.Fatalf("SetGuests failed: %v", err)
+	}
+
+	data, err := svc.GetInvitationData(p.ID)
+	if err != nil {
+		t.Fatalf("GetInvitationData failed: %v", err)
+	}
+	if len(data.Participants) != 1 || data.Participants[0].Guests != 2 {
+		t.Fatalf("expected host with 2 guests, got %+v", data.Participants)
+	}
+	if got := HeadCount(data.Participants); got != 3 {
+		t.Errorf("HeadCount = %d, want 3", got)
+	}
+	if got := data.Participants[0].GuestSuffix(); got != " +2" {
+		t.Errorf("GuestSuffix = %q, want \" +2\"", got)
+	}
+}
+
+func TestService_NicknameRequests(t *testing.T) {
+	requestRepo := &mockNickRequestRepo{}
+	svc := NewService(Repositories{
+		Polls:        &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:        &mockVoteRepo{},
+		Nicknames:    &mockNicknameRepo{},
+		NickRequests: requestRepo,
+	})
+
+	approved := &NicknameRequest{TgChatID: -123456, TgUserID: 1, TgUsername: "player", TgFirstName: "Player", GameNick: "Кот"}
+	if err := svc.RequestNickname(ClubVanmo, approved); err != nil {
+		t.Fatalf("RequestNickname failed: %v", err)
+	}
+	rejected := &NicknameRequest{TgChatID: -123456, TgUserID: 2, TgFirstName: "Other", GameNick: "Лиса"}
+	if err := svc.RequestNickname(ClubVanmo, rejected); err != nil {
+		t.Fatalf("RequestNickname failed: %v", err)
+	}
+
+	req, created, err := svc.ApproveNicknameRequest(approved.ID, -123456, ClubVanmo, 99)
+	if err != nil {
+		t.Fatalf("ApproveNicknameRequest failed: %v", err)
+	}
+	if !created || req.Status != NicknameRequestApproved || req.DecidedBy != 99 {
+		t.Errorf("expected approved request with created nickname, got created=%v %+v", created, req)
+	}
+	if _, _, err := svc.ApproveNicknameRequest(approved.ID, -123456, ClubVanmo, 99); !errors.Is(err, ErrNickRequestNotPending) {
+		t.Errorf("second approval = %v, want ErrNickRequestNotPending", err)
+	}
+
+	req, err = svc.RejectNicknameRequest(rejected.ID, -123456, 99)
+	if err != nil {
+		t.Fatalf("RejectNicknameRequest failed: %v", err)
+	}
+	if req.Status != NicknameRequestRejected {
+		t.Errorf("expected rejected request, got %+v", req)
+	}
+
+	if _, err := svc.RejectNicknameRequest(42, -123456, 99); !errors.Is(err, ErrNickRequestNotFound) {
+		t.Errorf("rejecting unknown request = %v, want ErrNickRequestNotFound", err)
+	}
+	// Requests can only be decided in the chat they were posted to
+	other := &NicknameRequest{TgChatID: -123456, TgUserID: 3, TgFirstName: "Third", GameNick: "Енот"}
+	svc.RequestNickname(ClubVanmo, other)
+	if _, err := svc.RejectNicknameRequest(other.ID, -654321, 99); !errors.Is(err, ErrNickRequestNotFound) {
+		t.Errorf("rejecting from another chat = %v, want ErrNickRequestNotFound", err)
+	}
+
+	// One request at a time
+	again := &NicknameRequest{TgChatID: -123456, TgUserID: 3, TgFirstName: "Third", GameNick: "Барсук"}
+	if err := svc.RequestNickname(ClubVanmo, again); !errors.Is(err, ErrNickRequestPending) {
+		t.Errorf("second pending request = %v, want ErrNickRequestPending", err)
+	}
+}
+
+func TestService_RequestNickname_HasNickname(t *testing.T) {
+	nicknames := &mockNicknameRepo{nicknames: []*Nickname{{Club: ClubVanmo, TgUserID: 1, NicknameInfo: NicknameInfo{Nick: "Кот"}}}}
+	svc := NewService(Repositories{
+		Polls:        &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:        &mockVoteRepo{},
+		Nicknames:    nicknames,
+		NickRequests: &mockNickRequestRepo{},
+	})
+
+	req := &NicknameRequest{TgChatID: -123456, TgUserID: 1, TgFirstName: "Player", GameNick: "Лиса"}
+	if err := svc.RequestNickname(ClubVanmo, req); !errors.Is(err, ErrHasNickname) {
+		t.Errorf("request of a player with a nickname = %v, want ErrHasNickname", err)
+	}
+}
+
+// countingNicknameRepo counts created nicknames.
+type countingNicknameRepo struct {
+	*mockNicknameRepo
+	created int
+}
+
+func (m *countingNicknameRepo) Create(club Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
+	m.created++
+	return true, nil
+}
+
+// racingNickRequestRepo lets another admin reject every request right after it is loaded.
+type racingNickRequestRepo struct {
+	*mockNickRequestRepo
+}
+
+func (m *racingNickRequestRepo) GetByID(id int64) (*NicknameRequest, error) {
+	req, err := m.mockNickRequestRepo.GetByID(id)
+	if req == nil || err != nil {
+		return req, err
+	}
+	loaded := *req
+	m.Decide(id, NicknameRequestPending, NicknameRequestRejected, 98)
+	return &loaded, nil
+}
+
+func TestService_ApproveNicknameRequest_LosesToRejection(t *testing.T) {
+	nicknames := &countingNicknameRepo{mockNicknameRepo: &mockNicknameRepo{}}
+	requests := &racingNickRequestRepo{&mockNickRequestRepo{}}
+	svc := NewService(Repositories{
+		Polls:        &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:        &mockVoteRepo{},
+		Nicknames:    nicknames,
+		NickRequests: requests,
+	})
+
+	req := &NicknameRequest{TgChatID: -123456, TgUserID: 1, TgFirstName: "Player", GameNick: "Лиса"}
+	if err := svc.RequestNickname(ClubVanmo, req); err != nil {
+		t.Fatalf("RequestNickname failed: %v", err)
+	}
+	if _, _, err := svc.ApproveNicknameRequest(req.ID, -123456, ClubVanmo, 99); !errors.Is(err, ErrNickRequestNotPending) {
+		t.Errorf("approval after a rejection = %v, want ErrNickRequestNotPending", err)
+	}
+	if nicknames.created != 0 {
+		t.Errorf("rejected request created %d nicknames", nicknames.created)
+	}
+}
+
+func TestService_ApproveNicknameRequest_HasNickname(t *testing.T) {
+	nicknames := &countingNicknameRepo{mockNicknameRepo: &mockNicknameRepo{}}
+	svc := NewService(Repositories{
+		Polls:        &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:        &mockVoteRepo{},
+		Nicknames:    nicknames,
+		NickRequests: &mockNickRequestRepo{},
+	})
+
+	req := &NicknameRequest{TgChatID: -123456, TgUserID: 1, TgFirstName: "Player", GameNick: "Лиса"}
+	if err := svc.RequestNickname(ClubVanmo, req); err != nil {
+		t.Fatalf("RequestNickname failed: %v", err)
+	}
+	// An admin ran /nick for the player while the request was pending
+	nicknames.nicknames = []*Nickname{{Club: ClubVanmo, TgUserID: 1, NicknameInfo: NicknameInfo{Nick: "Кот"}}}
+
+	decided, created, err := svc.ApproveNicknameRequest(req.ID, -123456, ClubVanmo, 99)
+	if !errors.Is(err, ErrHasNickname) || created {
+		t.Fatalf("ApproveNicknameRequest = %v, %v; want ErrHasNickname", created, err)
+	}
+	if decided.Status != NicknameRequestRejected || nicknames.created != 0 {
+		t.Errorf("expected a rejected request and no new nickname, got %+v and %d created", decided, nicknames.created)
+	}
+}
+
+func TestService_MatchNickname(t *testing.T) {
+	nicknames := &mockNicknameRepo{nicknames: []*Nickname{
+		{Club: ClubVanmo, NicknameInfo: NicknameInfo{Nick: "Мадам Жу"}},
+		{Club: ClubVanmo, NicknameInfo: NicknameInfo{Nick: "Кот"}},
+	}}
+	svc := NewService(Repositories{
+		Polls:     &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:     &mockVoteRepo{},
+		Nicknames: nicknames,
+	})
+
+	tests := []struct {
+		identifier  string
+		wantNick    string
+		wantCertain bool
+	}{
+		{"мадам жу", "Мадам Жу", true},
+		{"Madam Zhu", "Мадам Жу", false},
+		{"Мадам Жо", "Мадам Жу", false},
+		{"Енот", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			nick, certain, err := svc.MatchNickname(ClubVanmo, tt.identifier)
+			if err != nil {
+				t.Fatalf("MatchNickname failed: %v", err)
+			}
+			if nick != tt.wantNick || certain != tt.wantCertain {
+				t.Errorf("MatchNickname(%q) = %q, %v, want %q, %v", tt.identifier, nick, certain, tt.wantNick, tt.wantCertain)
+			}
+		})
+	}
+}
+
+func TestService_ResolvePlayer(t *testing.T) {
+	nicknames := &mockNicknameRepo{nicknames: []*Nickname{
+		{Club: ClubVanmo, TgUserID: 1, TgUsername: "cat", NicknameInfo: NicknameInfo{Nick: "Кот"}},
+		{Club: ClubVanmo, TgUsername: "fox", NicknameInfo: NicknameInfo{Nick: "Лиса"}},
+	}}
+	votes := &mockVoteRepo{votes: []*Vote{{PollID: 1, TgUserID: ManualUserID("Енот"), TgOptionIndex: 0}}}
+	svc := NewService(Repositories{
+		Polls:     &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:     votes,
+		Nicknames: nicknames,
+	})
+
+	tests := []struct {
+		identifier string
+		wantID     int64
+		wantFound  bool
+	}{
+		{"Кот", 1, true},
+		{"@cat", 1, true},
+		{"Лиса", ManualUserID("fox"), true},
+		{"@fox", ManualUserID("fox"), true},
+		{"Енот", ManualUserID("Енот"), true}, // manual votes only
+		{"Кто", 0, false},
+		{"@nobody", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			userID, _, _, found, err := svc.ResolvePlayer(ClubVanmo, tt.identifier)
+			if err != nil {
+				t.Fatalf("ResolvePlayer failed: %v", err)
+			}
+			if found != tt.wantFound || (found && userID != tt.wantID) {
+				t.Errorf("ResolvePlayer(%q) = %d, %v, want %d, %v", tt.identifier, userID, found, tt.wantID, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestService_FindVote(t *testing.T) {
+	votes := &mockVoteRepo{votes: []*Vote{
+		{PollID: 1, TgUserID: ManualUserID("cat"), TgOptionIndex: int(OptionComeAt19), IsManual: true},
+		{PollID: 1, TgUserID: 2, TgOptionIndex: int(OptionNotComing)},
+	}}
+	svc := NewService(Repositories{
+		Polls:     &mockPollRepo{polls: make(map[int64]*Poll)},
+		Votes:     votes,
+		Nicknames: &mockNicknameRepo{},
+	})
+
+	// Found under the synthetic ID of the username
+	v, err := svc.FindVote(1, 111, "cat")
+	if err != nil {
+		t.Fatalf("FindVote failed: %v", err)
+	}
+	if v == nil || v.TgUserID != ManualUserID("cat") {
+		t.Errorf("FindVote(cat) = %+v, want the manual vote", v)
+	}
+	if v, _ := svc.FindVote(1, 3, "fox"); v != nil {
+		t.Errorf("FindVote(fox) = %+v, want nil", v)
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	pollRepo := &mockPollRepo{polls: make(map[int64]*Poll)}
+	voteRepo := &mockVoteRepo{}
+	nicknames := &mockNicknameRepo{nicknames: []*Nickname{{Club: ClubVanmo, NicknameInfo: NicknameInfo{Nick: "Кот"}}}}
+	svc := NewService(Repositories{
+		Polls:     pollRepo,
+		Votes:     voteRepo,
+		Nicknames: nicknames,
+		Guests:    &mockGuestRepo{},
+	})
+
+	vanmo := &Poll{TgChatID: -1, Club: ClubVanmo, IsActive: true}
+	pollRepo.Create(vanmo)
+	pollRepo.Create(&Poll{TgChatID: -2, Club: ClubTbilissimo, IsActive: true})
+	voteRepo.Record(&Vote{PollID: vanmo.ID, TgUserID: 1, TgFirstName: "Ivan", TgOptionIndex: 0})
+
+	e, err := svc.Export(ClubVanmo, StatsPeriod{})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(e.Polls) != 1 || e.Polls[0].ID != vanmo.ID {
+		t.Fatalf("expected the vanmo poll only, got %+v", e.Polls)
+	}
+	if len(e.Polls[0].Votes) != 1 || e.Polls[0].Votes[0].TgFirstName != "Ivan" {
+		t.Errorf("expected the poll's current vote, got %+v", e.Polls[0].Votes)
+	}
+	if len(e.Nicknames) != 1 {
+		t.Errorf("expected club nicknames, got %+v", e.Nicknames)
+	}
+}
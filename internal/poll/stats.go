@@ -0,0 +1,69 @@
+package poll
+
+import "time"
+
+// StatsPeriod is a date range for statistics queries.
+// From is inclusive, To is exclusive; zero values mean no bound.
+type StatsPeriod struct {
+	From time.Time
+	To   time.Time
+}
+
+// PlayerStats holds aggregated attendance statistics for a single player.
+type PlayerStats struct {
+	VotedAttending int       // polls where the final vote was 19:00, 20:00 or 21:00+
+	EventsPlayed   int       // events held (not cancelled, not in the future) the player voted for and did not miss
+	Cancelled      int       // events the player voted for that were cancelled
+	NoShows        int       // events the player voted for but did not show up (marked via /attended)
+	LateArrivals   int       // polls where the final vote was 21:00+
+	LastVisit      time.Time // date of the last event played, zero if never
+	Debt           int       // unpaid amount in ₾ over events played that had a price set
+}
+
+// LeaderboardEntry holds a player's position data in the club leaderboard.
+type LeaderboardEntry struct {
+	TgUserID    int64  // the player's real ID, or a synthetic one if the player never voted themselves
+	TgUsername  string // username from the player's latest vote
+	TgFirstName string // first name from the player's latest vote
+	PlayerStats
+}
+
+// PollVote is a player's latest vote in a poll of the club together with the event's outcome for them.
+type PollVote struct {
+	PollID        int64
+	TgUserID      int64 // the player's ID, see LeaderboardEntry
+	TgUsername    string
+	TgFirstName   string
+	TgOptionIndex int
+	VotedAt       time.Time
+	EventDay      time.Time // the event's date at midnight, local time
+	Cancelled     bool
+	NoShow        bool // marked as not attended via /attended
+	Price         int  // 0 if not set
+	Paid          int  // sum of payments recorded for the player
+	Guests        int  // guests the player brings
+}
+
+// ClubSummary holds aggregated club activity for a period.
+type ClubSummary struct {
+	EventsHeld      int          // events that took place with at least one player
+	TotalAttendance int          // sum of players and their guests over all events held
+	Tables          int          // game tables over all events held, see TableCount
+	BusiestWeekday  time.Weekday // weekday with the most players in total (valid if EventsHeld > 0)
+}
+
+// AveragePlayersPerEvent returns the average number of players per event night (over all of its tables).
+func (s *ClubSummary) AveragePlayersPerEvent() float64 {
+	if s.EventsHeld == 0 {
+		return 0
+	}
+	return float64(s.TotalAttendance) / float64(s.EventsHeld)
+}
+
+// AverageTableSize returns the average number of players (with guests) per game table.
+func (s *ClubSummary) AverageTableSize() float64 {
+	if s.Tables == 0 {
+		return 0
+	}
+	return float64(s.TotalAttendance) / float64(s.Tables)
+}
@@ -0,0 +1,112 @@
+package poll
+
+import (
+	"slices"
+	"sort"
+)
+
+// TableCount returns how many tables SplitIntoTables makes for heads people:
+// one unless there are enough for at least two full tables of tableSize.
+func TableCount(heads, tableSize int) int {
+	if tableSize <= 0 || heads < 2*tableSize {
+		return 1
+	}
+	return heads / tableSize
+}
+
+// SplitIntoTables splits main voters into game tables of tableSize players.
+// Guests count as players and sit at their host's table, so tables and the split threshold
+// are measured in heads (see HeadCount), not votes.
+// Splitting happens only when there are enough people for at least two full tables;
+// otherwise a single table with all voters is returned.
+// Voters beyond the last full table (latest in vote order) become reserves along with their
+// guests, as does a host whose group no longer fits any table; the seats such a group leaves
+// go to the earliest reserves that fit them.
+//
+// Seated voters are dealt to tables in snake order (1, 2, 2, 1, 1, 2, ...), so early
+// and late arrivals are spread evenly. If ratings are given, voters are dealt by rating
+// (highest first, unrated players count as InitialRating) to balance table strength.
+// Hosts with guests are dealt before single voters, while all tables still have room.
+// Within a table voters keep their original vote order.
+func SplitIntoTables(votes []*Vote, tableSize int, ratings map[int64]float64) (tables [][]*Vote, reserves []*Vote) {
+	heads := HeadCount(votes)
+	count := TableCount(heads, tableSize)
+	if count == 1 {
+		return [][]*Vote{votes}, nil
+	}
+
+	// Remember arrival order to restore it within each table and among reserves
+	order := make(map[*Vote]int, len(votes))
+	for i, v := range votes {
+		order[v] = i
+	}
+
+	seated := votes
+	for i, seats := 0, 0; i < len(votes); i++ {
+		seats += 1 + votes[i].Guests
+		if seats > count*tableSize {
+			seated, reserves = votes[:i], slices.Clone(votes[i:])
+			break
+		}
+	}
+
+	dealOrder := make([]*Vote, len(seated))
+	copy(dealOrder, seated)
+	if ratings != nil {
+		rating := func(v *Vote) float64 {
+			if r, ok := ratings[v.TgUserID]; ok {
+				return r
+			}
+			return InitialRating
+		}
+		sort.SliceStable(dealOrder, func(i, j int) bool { return rating(dealOrder[i]) > rating(dealOrder[j]) })
+	}
+	sort.SliceStable(dealOrder, func(i, j int) bool { return dealOrder[i].Guests > dealOrder[j].Guests })
+
+	tables = make([][]*Vote, count)
+	free := make([]int, count)
+	for i := range free {
+		free[i] = tableSize
+	}
+	// seat puts a voter with guests at the table, or the emptiest one if it is full
+	seat := func(v *Vote, table int) bool {
+		size := 1 + v.Guests
+		if free[table] < size {
+			for t := range free {
+				if free[t] > free[table] {
+					table = t
+				}
+			}
+			if free[table] < size {
+				return false
+			}
+		}
+		tables[table] = append(tables[table], v)
+		free[table] -= size
+		return true
+	}
+
+	var unseated []*Vote
+	for i, v := range dealOrder {
+		table := i % count
+		if (i/count)%2 == 1 {
+			table = count - 1 - table
+		}
+		if !seat(v, table) {
+			unseated = append(unseated, v)
+		}
+	}
+
+	// Seats left by a group that didn't fit go to the earliest reserves that fit them
+	byArrival := func(i, j *Vote) int { return order[i] - order[j] }
+	reserves = append(unseated, reserves...)
+	slices.SortFunc(reserves, byArrival)
+	if len(unseated) > 0 {
+		reserves = slices.DeleteFunc(reserves, func(v *Vote) bool { return seat(v, 0) })
+	}
+
+	for _, t := range tables {
+		slices.SortFunc(t, byArrival)
+	}
+	return tables, reserves
+}
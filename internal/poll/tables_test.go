@@ -0,0 +1,149 @@
+package poll
+
+import (
+	"slices"
+	"testing"
+)
+
+func testVotes(n int) []*Vote {
+	votes := make([]*Vote, n)
+	for i := range votes {
+		votes[i] = &Vote{TgUserID: int64(i + 1)}
+	}
+	return votes
+}
+
+func voteIDs(votes []*Vote) []int64 {
+	ids := make([]int64, len(votes))
+	for i, v := range votes {
+		ids[i] = v.TgUserID
+	}
+	return ids
+}
+
+func TestSplitIntoTables_NoSplit(t *testing.T) {
+	for _, tt := range []struct {
+		n, size int
+	}{
+		{15, 10}, // not enough for two tables
+		{25, 0},  // splitting disabled
+	} {
+		tables, reserves := SplitIntoTables(testVotes(tt.n), tt.size, nil)
+		if len(tables) != 1 || len(tables[0]) != tt.n || len(reserves) != 0 {
+			t.Errorf("SplitIntoTables(%d, %d) = %d tables, %d reserves, want single table", tt.n, tt.size, len(tables), len(reserves))
+		}
+	}
+}
+
+func TestTableCount(t *testing.T) {
+	for _, tt := range []struct {
+		heads, size, want int
+	}{
+		{15, 10, 1},
+		{25, 0, 1},
+		{20, 10, 2},
+		{29, 10, 2},
+		{30, 10, 3},
+	} {
+		if got := TableCount(tt.heads, tt.size); got != tt.want {
+			t.Errorf("TableCount(%d, %d) = %d, want %d", tt.heads, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestSplitIntoTables_ByArrival(t *testing.T) {
+	tables, reserves := SplitIntoTables(testVotes(25), 10, nil)
+
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(tables))
+	}
+	// Snake order: 1→T1, 2→T2, 3→T2, 4→T1, 5→T1, ...
+	wantT1 := []int64{1, 4, 5, 8, 9, 12, 13, 16, 17, 20}
+	wantT2 := []int64{2, 3, 6, 7, 10, 11, 14, 15, 18, 19}
+	if got := voteIDs(tables[0]); !slices.Equal(got, wantT1) {
+		t.Errorf("table 1 = %v, want %v", got, wantT1)
+	}
+	if got := voteIDs(tables[1]); !slices.Equal(got, wantT2) {
+		t.Errorf("table 2 = %v, want %v", got, wantT2)
+	}
+	if got := voteIDs(reserves); !slices.Equal(got, []int64{21, 22, 23, 24, 25}) {
+		t.Errorf("reserves = %v, want latest 5 voters", got)
+	}
+}
+
+func TestSplitIntoTables_ByRating(t *testing.T) {
+	// Players 1-4 are the strongest and must not end up at the same table
+	ratings := map[int64]float64{1: 1700, 2: 1690, 3: 1680, 4: 1670, 20: 1400}
+	tables, _ := SplitIntoTables(testVotes(20), 10, ratings)
+
+	strong := map[int]int{}
+	for i, table := range tables {
+		for _, v := range table {
+			if v.TgUserID <= 4 {
+				strong[i]++
+			}
+		}
+		// Arrival order is kept within a table
+		for j := 1; j < len(table); j++ {
+			if table[j-1].TgUserID > table[j].TgUserID {
+				t.Errorf("table %d not in vote order: %v", i+1, voteIDs(table))
+				break
+			}
+		}
+	}
+	if strong[0] != 2 || strong[1] != 2 {
+		t.Errorf("strong players split %v, want 2 per table", strong)
+	}
+}
+
+func TestSplitIntoTables_GuestsTakeSeats(t *testing.T) {
+	// 18 voters with 4 guests are 22 people: enough for two tables of 10
+	votes := testVotes(18)
+	votes[0].Guests = 2
+	votes[5].Guests = 2
+
+	tables, reserves := SplitIntoTables(votes, 10, nil)
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(tables))
+	}
+	for i, table := range tables {
+		if got := HeadCount(table); got != 10 {
+			t.Errorf("table %d seats %d people, want 10: %v", i+1, got, voteIDs(table))
+		}
+	}
+	// Hosts are dealt first, one per table
+	if !slices.Contains(voteIDs(tables[0]), 1) || !slices.Contains(voteIDs(tables[1]), 6) {
+		t.Errorf("hosts 1 and 6 should sit at different tables: %v, %v", voteIDs(tables[0]), voteIDs(tables[1]))
+	}
+	if got := voteIDs(reserves); !slices.Equal(got, []int64{17, 18}) {
+		t.Errorf("reserves = %v, want latest 2 voters", got)
+	}
+
+	// Fewer people than two tables: no split even with many votes
+	votes = testVotes(19)
+	if tables, _ := SplitIntoTables(votes, 10, nil); len(tables) != 1 {
+		t.Errorf("19 people split into %d tables, want 1", len(tables))
+	}
+}
+
+func TestSplitIntoTables_GroupDoesNotFit(t *testing.T) {
+	// Three groups of 6 can't share two tables of 10: the latest host becomes a reserve
+	// and the first reserve takes the seat left
+	votes := testVotes(6)
+	for _, v := range votes[:3] {
+		v.Guests = 5
+	}
+
+	tables, reserves := SplitIntoTables(votes, 10, nil)
+	for i, table := range tables {
+		if got := HeadCount(table); got > 10 {
+			t.Errorf("table %d seats %d people, want at most 10", i+1, got)
+		}
+	}
+	if got := voteIDs(reserves); !slices.Equal(got, []int64{3}) {
+		t.Errorf("reserves = %v, want host 3", got)
+	}
+	if got := HeadCount(tables[0]) + HeadCount(tables[1]); got != 15 {
+		t.Errorf("seated %d people, want 15", got)
+	}
+}
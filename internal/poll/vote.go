@@ -14,7 +14,9 @@ type Vote struct {
 	TgFirstName   string
 	TgOptionIndex int
 	IsManual      bool
+	ActorUserID   int64 // Admin who entered a manual vote (0 for votes cast by the user)
 	VotedAt       time.Time
+	Guests        int // Guests the voter brings to the event (stored per poll, not with the vote)
 }
 
 // NormalizeUsername normalizes a Telegram username to lowercase.
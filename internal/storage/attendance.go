@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type AttendanceRepository struct {
+	db *DB
+}
+
+func NewAttendanceRepository(db *DB) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+// Init creates attendance records for the given users, marking them as attended.
+// Existing records are left untouched, so calling Init again keeps previous toggles.
+func (r *AttendanceRepository) Init(pollID int64, userIDs []int64) error {
+	now := time.Now()
+	for _, userID := range userIDs {
+		_, err := r.db.db.Exec(`
+			INSERT OR IGNORE INTO attendance (poll_id, tg_user_id, attended, updated_at)
+			VALUES (?, ?, 1, ?)
+		`, pollID, userID, now)
+		if err != nil {
+			return fmt.Errorf("init attendance: %w", err)
+		}
+	}
+	return nil
+}
+
+// Toggle flips the attended flag for a user and returns the new value.
+// If no record exists yet, the user is considered attended and is marked as a no-show.
+func (r *AttendanceRepository) Toggle(pollID, userID, actorUserID int64) (bool, error) {
+	_, err := r.db.db.Exec(`
+		INSERT INTO attendance (poll_id, tg_user_id, attended, actor_user_id, updated_at)
+		VALUES (?, ?, 0, ?, ?)
+		ON CONFLICT(poll_id, tg_user_id) DO UPDATE SET
+			attended = 1 - attended,
+			actor_user_id = excluded.actor_user_id,
+			updated_at = excluded.updated_at
+	`, pollID, userID, nullInt64(actorUserID), time.Now())
+	if err != nil {
+		return false, fmt.Errorf("toggle attendance: %w", err)
+	}
+
+	var attended bool
+	err = r.db.db.QueryRow(`
+		SELECT attended FROM attendance WHERE poll_id = ? AND tg_user_id = ?
+	`, pollID, userID).Scan(&attended)
+	if err != nil {
+		return false, fmt.Errorf("read attendance: %w", err)
+	}
+	return attended, nil
+}
+
+// GetByPoll returns all attendance records for a poll.
+func (r *AttendanceRepository) GetByPoll(pollID int64) ([]*poll.Attendance, error) {
+	rows, err := r.db.db.Query(`
+		SELECT poll_id, tg_user_id, attended, actor_user_id, updated_at
+		FROM attendance
+		WHERE poll_id = ?
+	`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("query attendance: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*poll.Attendance
+	for rows.Next() {
+		var a poll.Attendance
+		var actorUserID sql.NullInt64
+		if err := rows.Scan(&a.PollID, &a.TgUserID, &a.Attended, &actorUserID, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan attendance: %w", err)
+		}
+		a.ActorUserID = actorUserID.Int64
+		records = append(records, &a)
+	}
+	return records, rows.Err()
+}
+
+// CountNoShows returns the number of events each player promised to attend but missed,
+// across all polls of the club. Players are merged with aliases and counted the same way
+// as PlayerStats.NoShows (see playerPollsCTE), so playerIDs are IDs players are merged into.
+// Players without no-shows are absent from the map.
+func (r *AttendanceRepository) CountNoShows(club poll.Club, playerIDs []int64, aliases map[int64]int64) (map[int64]int, error) {
+	counts := make(map[int64]int)
+	if len(playerIDs) == 0 {
+		return counts, nil
+	}
+
+	cte, args := playerPollsCTE(club, poll.StatsPeriod{}, aliases)
+	placeholders := make([]string, len(playerIDs))
+	for i, id := range playerIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := cte + fmt.Sprintf(`
+		SELECT player_id, COUNT(*)
+		FROM player_polls
+		WHERE attended = 0 AND player_id IN (%s)
+		GROUP BY player_id
+	`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query no-shows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, fmt.Errorf("scan no-shows: %w", err)
+		}
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
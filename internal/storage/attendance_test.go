@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestAttendanceRepository_InitAndToggle(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+
+	p := &poll.Poll{
+		TgChatID:  -123456,
+		EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:  true,
+	}
+	pollRepo.Create(p)
+
+	if err := attendanceRepo.Init(p.ID, []int64{111, 222}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	// Toggle 222 to no-show
+	attended, err := attendanceRepo.Toggle(p.ID, 222, 999)
+	if err != nil {
+		t.Fatalf("Toggle failed: %v", err)
+	}
+	if attended {
+		t.Error("expected 222 to be marked as no-show after toggle")
+	}
+
+	// Init again must not reset the toggle
+	if err := attendanceRepo.Init(p.ID, []int64{111, 222}); err != nil {
+		t.Fatalf("second Init failed: %v", err)
+	}
+
+	records, err := attendanceRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	for _, r := range records {
+		switch r.TgUserID {
+		case 111:
+			if !r.Attended {
+				t.Error("expected 111 to be attended")
+			}
+		case 222:
+			if r.Attended {
+				t.Error("expected 222 to stay a no-show after re-init")
+			}
+			if r.ActorUserID != 999 {
+				t.Errorf("expected actor 999, got %d", r.ActorUserID)
+			}
+		}
+	}
+
+	// Toggle back
+	attended, err = attendanceRepo.Toggle(p.ID, 222, 999)
+	if err != nil {
+		t.Fatalf("Toggle failed: %v", err)
+	}
+	if !attended {
+		t.Error("expected 222 to be attended after second toggle")
+	}
+}
+
+func TestAttendanceRepository_CountNoShows(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+
+	syntheticID := poll.ManualUserID("кот")
+	newPoll := func(chatID int64, club poll.Club, day int) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:  chatID,
+			Club:      club,
+			EventDate: time.Date(2025, 2, day, 0, 0, 0, 0, time.UTC),
+		}
+		pollRepo.Create(p)
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: 111, TgFirstName: "Alice", TgOptionIndex: int(poll.OptionComeAt19)})
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: 222, TgFirstName: "Bob", TgOptionIndex: int(poll.OptionComeAt19)})
+		attendanceRepo.Init(p.ID, []int64{111, 222})
+		return p
+	}
+
+	// Missed events in two chats of the same club both count
+	for i, chatID := range []int64{-123456, -654321} {
+		p := newPoll(chatID, poll.ClubVanmo, 1+i)
+		attendanceRepo.Toggle(p.ID, 222, 0)
+	}
+
+	// Poll of another club must not be counted
+	other := newPoll(-999, poll.ClubTbilissimo, 3)
+	attendanceRepo.Toggle(other.ID, 222, 0)
+
+	// A missed event under the player's synthetic identity is merged into the player
+	manual := newPoll(-123456, poll.ClubVanmo, 4)
+	voteRepo.Record(&poll.Vote{PollID: manual.ID, TgUserID: syntheticID, TgFirstName: "Кот", TgOptionIndex: int(poll.OptionComeAt20), IsManual: true})
+	attendanceRepo.Init(manual.ID, []int64{syntheticID})
+	attendanceRepo.Toggle(manual.ID, syntheticID, 0)
+
+	aliases := map[int64]int64{syntheticID: 111}
+	counts, err := attendanceRepo.CountNoShows(poll.ClubVanmo, []int64{111, 222}, aliases)
+	if err != nil {
+		t.Fatalf("CountNoShows failed: %v", err)
+	}
+	if counts[111] != 1 {
+		t.Errorf("expected 1 no-show for 111, got %d", counts[111])
+	}
+	if counts[222] != 2 {
+		t.Errorf("expected 2 no-shows for 222, got %d", counts[222])
+	}
+}
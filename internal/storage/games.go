@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type GameRepository struct {
+	db *DB
+}
+
+func NewGameRepository(db *DB) *GameRepository {
+	return &GameRepository{db: db}
+}
+
+// Create stores a game with its participants and assigns the game ID and number.
+// The game number is the next one within the game's event night.
+func (r *GameRepository) Create(g *poll.Game) error {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`SELECT COALESCE(MAX(number), 0) + 1 FROM games WHERE poll_id = ?`, g.PollID).Scan(&g.Number)
+	if err != nil {
+		return fmt.Errorf("get next game number: %w", err)
+	}
+
+	if g.PlayedAt.IsZero() {
+		g.PlayedAt = time.Now()
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO games (poll_id, number, winner, judge_user_id, judge_name, actor_user_id, played_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, g.PollID, g.Number, string(g.Winner), nullInt64(g.JudgeUserID), g.JudgeName, nullInt64(g.ActorUserID), g.PlayedAt)
+	if err != nil {
+		return fmt.Errorf("insert game: %w", err)
+	}
+
+	g.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	for _, p := range g.Participants {
+		_, err := tx.Exec(`
+			INSERT INTO game_participants (game_id, seat, tg_user_id, tg_username, name, role)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, g.ID, p.Seat, p.TgUserID, p.TgUsername, p.Name, string(p.Role))
+		if err != nil {
+			return fmt.Errorf("insert game participant: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit game: %w", err)
+	}
+	return nil
+}
+
+// GetByPoll returns all games of an event night ordered by game number.
+func (r *GameRepository) GetByPoll(pollID int64) ([]*poll.Game, error) {
+	return r.queryGames(`
+		SELECT g.id, g.poll_id, g.number, g.winner, g.judge_user_id, g.judge_name, g.actor_user_id, g.played_at
+		FROM games g
+		WHERE g.poll_id = ?
+		ORDER BY g.number
+	`, pollID)
+}
+
+// GetByClub returns all games of a club within a period in chronological order.
+// Games of cancelled events are included, since they were actually played.
+func (r *GameRepository) GetByClub(club poll.Club, period poll.StatsPeriod) ([]*poll.Game, error) {
+	from, to := sqlDate(period.From), sqlDate(period.To)
+	return r.queryGames(`
+		SELECT g.id, g.poll_id, g.number, g.winner, g.judge_user_id, g.judge_name, g.actor_user_id, g.played_at
+		FROM games g
+		JOIN polls p ON p.id = g.poll_id
+		WHERE p.club = ?
+			AND (? = '' OR substr(p.event_date, 1, 10) >= ?)
+			AND (? = '' OR substr(p.event_date, 1, 10) < ?)
+		ORDER BY substr(p.event_date, 1, 10), g.poll_id, g.number
+	`, string(club), from, from, to, to)
+}
+
+// Delete removes a game of an event night by its number.
+// Returns false if there is no such game.
+func (r *GameRepository) Delete(pollID int64, number int) (bool, error) {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var gameID int64
+	err = tx.QueryRow(`SELECT id FROM games WHERE poll_id = ? AND number = ?`, pollID, number).Scan(&gameID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("find game: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM game_participants WHERE game_id = ?`, gameID); err != nil {
+		return false, fmt.Errorf("delete game participants: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM games WHERE id = ?`, gameID); err != nil {
+		return false, fmt.Errorf("delete game: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit game deletion: %w", err)
+	}
+	return true, nil
+}
+
+// queryGames runs a games query and loads participants for all returned games.
+func (r *GameRepository) queryGames(query string, args ...any) ([]*poll.Game, error) {
+	rows, err := r.db.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []*poll.Game
+	byID := make(map[int64]*poll.Game)
+	for rows.Next() {
+		var g poll.Game
+		var winner string
+		var judgeUserID, actorUserID sql.NullInt64
+		var judgeName sql.NullString
+		if err := rows.Scan(&g.ID, &g.PollID, &g.Number, &winner, &judgeUserID, &judgeName, &actorUserID, &g.PlayedAt); err != nil {
+			return nil, fmt.Errorf("scan game: %w", err)
+		}
+		g.Winner = poll.Side(winner)
+		g.JudgeUserID = judgeUserID.Int64
+		g.JudgeName = judgeName.String
+		g.ActorUserID = actorUserID.Int64
+		games = append(games, &g)
+		byID[g.ID] = &g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return games, nil
+	}
+
+	placeholders := make([]string, len(games))
+	ids := make([]any, len(games))
+	for i, g := range games {
+		placeholders[i] = "?"
+		ids[i] = g.ID
+	}
+
+	participantRows, err := r.db.db.Query(fmt.Sprintf(`
+		SELECT game_id, seat, tg_user_id, tg_username, name, role
+		FROM game_participants
+		WHERE game_id IN (%s)
+		ORDER BY game_id, seat
+	`, strings.Join(placeholders, ",")), ids...)
+	if err != nil {
+		return nil, fmt.Errorf("query game participants: %w", err)
+	}
+	defer participantRows.Close()
+
+	for participantRows.Next() {
+		var gameID int64
+		var p poll.GameParticipant
+		var username sql.NullString
+		var role string
+		if err := participantRows.Scan(&gameID, &p.Seat, &p.TgUserID, &username, &p.Name, &role); err != nil {
+			return nil, fmt.Errorf("scan game participant: %w", err)
+		}
+		p.TgUsername = username.String
+		p.Role = poll.Role(role)
+		byID[gameID].Participants = append(byID[gameID].Participants, &p)
+	}
+	return games, participantRows.Err()
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func newTestGame(pollID int64, winner poll.Side) *poll.Game {
+	g := &poll.Game{PollID: pollID, Winner: winner, JudgeUserID: 100, JudgeName: "Судья", ActorUserID: 200}
+	roles := []poll.Role{poll.RoleDon, poll.RoleMafia, poll.RoleSheriff, poll.RoleCivilian, poll.RoleCivilian, poll.RoleCivilian}
+	for i, role := range roles {
+		g.Participants = append(g.Participants, &poll.GameParticipant{
+			Seat:     i + 1,
+			TgUserID: int64(i + 1),
+			Name:     "Player",
+			Role:     role,
+		})
+	}
+	return g
+}
+
+func TestGameRepository_CreateAndGet(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	gameRepo := NewGameRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+
+	g1 := newTestGame(p.ID, poll.SideCity)
+	if err := gameRepo.Create(g1); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	g2 := newTestGame(p.ID, poll.SideMafia)
+	g2.JudgeUserID = 0
+	g2.JudgeName = ""
+	if err := gameRepo.Create(g2); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if g1.Number != 1 || g2.Number != 2 {
+		t.Errorf("game numbers = %d, %d, want 1, 2", g1.Number, g2.Number)
+	}
+
+	games, err := gameRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("expected 2 games, got %d", len(games))
+	}
+
+	got := games[0]
+	if got.Winner != poll.SideCity || got.JudgeUserID != 100 || got.JudgeName != "Судья" || got.ActorUserID != 200 {
+		t.Errorf("unexpected game: %+v", got)
+	}
+	if len(got.Participants) != 6 {
+		t.Fatalf("expected 6 participants, got %d", len(got.Participants))
+	}
+	if got.Participants[0].Seat != 1 || got.Participants[0].Role != poll.RoleDon {
+		t.Errorf("unexpected first participant: %+v", got.Participants[0])
+	}
+	if games[1].JudgeUserID != 0 || games[1].JudgeName != "" {
+		t.Errorf("expected game without judge, got %+v", games[1])
+	}
+}
+
+func TestGameRepository_GetByClubAndDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	gameRepo := NewGameRepository(db)
+
+	feb := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(feb)
+	mar := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(mar)
+	other := &poll.Poll{TgChatID: -654321, Club: poll.ClubTbilissimo, EventDate: time.Date(2025, 2, 2, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(other)
+
+	gameRepo.Create(newTestGame(mar.ID, poll.SideCity))
+	gameRepo.Create(newTestGame(feb.ID, poll.SideMafia))
+	gameRepo.Create(newTestGame(other.ID, poll.SideCity))
+
+	all, err := gameRepo.GetByClub(poll.ClubVanmo, poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("GetByClub failed: %v", err)
+	}
+	if len(all) != 2 || all[0].PollID != feb.ID || all[1].PollID != mar.ID {
+		t.Fatalf("expected vanmo games in chronological order, got %+v", all)
+	}
+
+	febOnly, err := gameRepo.GetByClub(poll.ClubVanmo, poll.StatsPeriod{
+		From: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("GetByClub failed: %v", err)
+	}
+	if len(febOnly) != 1 || febOnly[0].PollID != feb.ID {
+		t.Errorf("expected only the February game, got %+v", febOnly)
+	}
+
+	deleted, err := gameRepo.Delete(feb.ID, 1)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v, want true, nil", deleted, err)
+	}
+	deleted, err = gameRepo.Delete(feb.ID, 1)
+	if err != nil || deleted {
+		t.Errorf("second Delete = %v, %v, want false, nil", deleted, err)
+	}
+
+	games, err := gameRepo.GetByPoll(feb.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(games) != 0 {
+		t.Errorf("expected no games after delete, got %d", len(games))
+	}
+}
+
+func TestService_GetRatings_MergesIdentities(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	gameRepo := NewGameRepository(db)
+	nickRepo := NewNicknameRepository(db)
+	svc := poll.NewService(poll.Repositories{
+		Polls:     pollRepo,
+		Votes:     NewVoteRepository(db),
+		Nicknames: nickRepo,
+		Stats:     NewStatsRepository(db),
+		Games:     gameRepo,
+	})
+
+	// Player 1 was recorded by game nick before being linked to Telegram
+	userID := int64(1)
+	nickRepo.Create(poll.ClubVanmo, &userID, nil, "Кот", "", 0)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+	early := newTestGame(p.ID, poll.SideMafia)
+	early.Participants[0].TgUserID = poll.ManualUserID("Кот")
+	gameRepo.Create(early)
+	gameRepo.Create(newTestGame(p.ID, poll.SideMafia))
+
+	ratings, err := svc.GetRatings(poll.ClubVanmo, poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("GetRatings failed: %v", err)
+	}
+	if len(ratings) != 6 {
+		t.Fatalf("expected 6 rated players, got %d", len(ratings))
+	}
+	for _, r := range ratings {
+		if r.TgUserID == userID && (r.Games != 2 || r.Wins != 2) {
+			t.Errorf("merged rating = %+v, want 2 games and 2 wins", r)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+type GuestRepository struct {
+	db *DB
+}
+
+func NewGuestRepository(db *DB) *GuestRepository {
+	return &GuestRepository{db: db}
+}
+
+// Set stores how many guests a player brings to a poll's event.
+// A zero count removes the player's guests.
+func (r *GuestRepository) Set(pollID, hostUserID int64, count int, actorUserID int64) error {
+	if count == 0 {
+		if _, err := r.db.db.Exec(`DELETE FROM guests WHERE poll_id = ? AND host_user_id = ?`, pollID, hostUserID); err != nil {
+			return fmt.Errorf("delete guests: %w", err)
+		}
+		return nil
+	}
+
+	_, err := r.db.db.Exec(`
+		INSERT INTO guests (poll_id, host_user_id, count, actor_user_id, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(poll_id, host_user_id) DO UPDATE SET
+			count = excluded.count,
+			actor_user_id = excluded.actor_user_id,
+			updated_at = excluded.updated_at
+	`, pollID, hostUserID, count, nullInt64(actorUserID), time.Now())
+	if err != nil {
+		return fmt.Errorf("set guests: %w", err)
+	}
+	return nil
+}
+
+// GetByPoll returns the number of guests per host user for a poll.
+// Hosts without guests are absent from the map.
+func (r *GuestRepository) GetByPoll(pollID int64) (map[int64]int, error) {
+	rows, err := r.db.db.Query(`SELECT host_user_id, count FROM guests WHERE poll_id = ?`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("query guests: %w", err)
+	}
+	defer rows.Close()
+
+	guests := make(map[int64]int)
+	for rows.Next() {
+		var hostUserID int64
+		var count int
+		if err := rows.Scan(&hostUserID, &count); err != nil {
+			return nil, fmt.Errorf("scan guests: %w", err)
+		}
+		guests[hostUserID] = count
+	}
+	return guests, rows.Err()
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestGuestRepository_SetAndGet(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	guestRepo := NewGuestRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+
+	if err := guestRepo.Set(p.ID, 1, 1, 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := guestRepo.Set(p.ID, 2, 1, 99); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	// Setting again replaces the count
+	if err := guestRepo.Set(p.ID, 1, 3, 0); err != nil {
+		t.Fatalf("Set again failed: %v", err)
+	}
+	// Zero removes the guests
+	if err := guestRepo.Set(p.ID, 2, 0, 99); err != nil {
+		t.Fatalf("Set zero failed: %v", err)
+	}
+
+	guests, err := guestRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(guests) != 1 || guests[1] != 3 {
+		t.Errorf("expected only host 1 with 3 guests, got %v", guests)
+	}
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"nuclight.org/consigliere/internal/metrics"
+)
+
+// conn is the database handle the repositories use. It times every statement for
+// the SQLite latency metric. It wraps *sql.DB rather than embedding it, so a statement
+// method that is not timed here does not compile instead of silently skipping the metric.
+type conn struct {
+	db *sql.DB
+}
+
+// querier runs a query on either conn or *tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func observe(op string, start time.Time) {
+	metrics.DBQueryDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
+
+func (c conn) Exec(query string, args ...any) (sql.Result, error) {
+	defer observe("exec", time.Now())
+	return c.db.Exec(query, args...)
+}
+
+func (c conn) Query(query string, args ...any) (*sql.Rows, error) {
+	defer observe("query", time.Now())
+	return c.db.Query(query, args...)
+}
+
+// QueryRow is timed until the first row is ready; scanning it is not included.
+func (c conn) QueryRow(query string, args ...any) *sql.Row {
+	defer observe("query_row", time.Now())
+	return c.db.QueryRow(query, args...)
+}
+
+func (c conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer observe("query_row", time.Now())
+	return c.db.QueryRowContext(ctx, query, args...)
+}
+
+func (c conn) Begin() (*tx, error) {
+	t, err := c.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &tx{t}, nil
+}
+
+func (c conn) Close() error {
+	return c.db.Close()
+}
+
+// tx is a transaction timed like conn, including its commit.
+type tx struct {
+	tx *sql.Tx
+}
+
+func (t *tx) Exec(query string, args ...any) (sql.Result, error) {
+	defer observe("exec", time.Now())
+	return t.tx.Exec(query, args...)
+}
+
+func (t *tx) Query(query string, args ...any) (*sql.Rows, error) {
+	defer observe("query", time.Now())
+	return t.tx.Query(query, args...)
+}
+
+func (t *tx) QueryRow(query string, args ...any) *sql.Row {
+	defer observe("query_row", time.Now())
+	return t.tx.QueryRow(query, args...)
+}
+
+func (t *tx) Commit() error {
+	defer observe("commit", time.Now())
+	return t.tx.Commit()
+}
+
+// Rollback is not timed: it is deferred after every transaction and is a no-op after a commit.
+func (t *tx) Rollback() error {
+	return t.tx.Rollback()
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"nuclight.org/consigliere/internal/metrics"
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// observations returns how many statements of the operation were timed so far.
+func observations(t *testing.T, op string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := metrics.DBQueryDuration.WithLabelValues(op).(prometheus.Histogram).Write(&m); err != nil {
+		t.Fatalf("read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestConn_TimesStatements(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	before := map[string]uint64{}
+	for _, op := range []string{"exec", "query", "query_row", "commit"} {
+		before[op] = observations(t, op)
+	}
+
+	pollRepo := NewPollRepository(db)
+	p := &poll.Poll{TgChatID: -123456, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), IsActive: true}
+	if err := pollRepo.Create(p); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := pollRepo.GetLatestActive(p.TgChatID); err != nil {
+		t.Fatalf("GetLatestActive failed: %v", err)
+	}
+	votes := []*poll.Vote{{PollID: p.ID, TgUserID: 111, TgFirstName: "Alice"}}
+	if err := NewVoteRepository(db).RecordAll(votes); err != nil {
+		t.Fatalf("RecordAll failed: %v", err)
+	}
+	if _, err := NewVoteRepository(db).GetCurrentVotes(p.ID); err != nil {
+		t.Fatalf("GetCurrentVotes failed: %v", err)
+	}
+
+	for op, n := range before {
+		if observations(t, op) <= n {
+			t.Errorf("no %s statements timed", op)
+		}
+	}
+}
+
+func TestTx_TimesQueries(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	before := observations(t, "query")
+	tx, err := db.db.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	defer tx.Rollback()
+	rows, err := tx.Query(`SELECT id FROM polls`)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	rows.Close()
+
+	if observations(t, "query") <= before {
+		t.Error("transaction query not timed")
+	}
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type NicknameRequestRepository struct {
+	db *DB
+}
+
+func NewNicknameRequestRepository(db *DB) *NicknameRequestRepository {
+	return &NicknameRequestRepository{db: db}
+}
+
+// Create stores a new pending nickname request and sets its ID.
+func (r *NicknameRequestRepository) Create(req *poll.NicknameRequest) error {
+	var genderVal *string
+	if req.Gender != "" {
+		genderVal = &req.Gender
+	}
+
+	req.Status = poll.NicknameRequestPending
+	req.CreatedAt = time.Now()
+	result, err := r.db.db.Exec(`
+		INSERT INTO nickname_requests (tg_chat_id, tg_user_id, tg_username, tg_first_name, game_nick, gender, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, req.TgChatID, req.TgUserID, req.TgUsername, req.TgFirstName, req.GameNick, genderVal, req.Status, req.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert nickname request: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	req.ID = id
+	return nil
+}
+
+// GetByID returns a nickname request, or nil if it does not exist.
+func (r *NicknameRequestRepository) GetByID(id int64) (*poll.NicknameRequest, error) {
+	var req poll.NicknameRequest
+	var username, gender sql.NullString
+	var decidedBy sql.NullInt64
+	err := r.db.db.QueryRow(`
+		SELECT id, tg_chat_id, tg_user_id, tg_username, tg_first_name, game_nick, gender, status, decided_by, created_at
+		FROM nickname_requests
+		WHERE id = ?
+	`, id).Scan(&req.ID, &req.TgChatID, &req.TgUserID, &username, &req.TgFirstName, &req.GameNick, &gender, &req.Status, &decidedBy, &req.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get nickname request: %w", err)
+	}
+	req.TgUsername = username.String
+	req.Gender = gender.String
+	req.DecidedBy = decidedBy.Int64
+	return &req, nil
+}
+
+// Decide moves a request from one status to another, e.g. a pending request to approved.
+// Returns false if the request does not exist or is no longer in the from status.
+func (r *NicknameRequestRepository) Decide(id int64, from, status poll.NicknameRequestStatus, actorUserID int64) (bool, error) {
+	result, err := r.db.db.Exec(`
+		UPDATE nickname_requests SET status = ?, decided_by = ?, decided_at = ?
+		WHERE id = ? AND status = ?
+	`, status, nullInt64(actorUserID), time.Now(), id, from)
+	if err != nil {
+		return false, fmt.Errorf("decide nickname request: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// HasPending reports whether the user has an undecided request in the chat.
+func (r *NicknameRequestRepository) HasPending(chatID, userID int64) (bool, error) {
+	var pending bool
+	err := r.db.db.QueryRow(`
+		SELECT COUNT(*) > 0 FROM nickname_requests
+		WHERE tg_chat_id = ? AND tg_user_id = ? AND status = ?
+	`, chatID, userID, poll.NicknameRequestPending).Scan(&pending)
+	if err != nil {
+		return false, fmt.Errorf("check pending nickname request: %w", err)
+	}
+	return pending, nil
+}
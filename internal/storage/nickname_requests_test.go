@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"testing"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestNicknameRequestRepository_CreateAndDecide(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRequestRepository(db)
+
+	req := &poll.NicknameRequest{TgChatID: -123456, TgUserID: 1, TgUsername: "player", TgFirstName: "Player", GameNick: "Мадам Жу", Gender: "female"}
+	if err := repo.Create(req); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if req.ID == 0 {
+		t.Fatal("expected ID to be set")
+	}
+
+	got, err := repo.GetByID(req.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got == nil || got.GameNick != "Мадам Жу" || got.Gender != "female" || got.TgUsername != "player" || got.Status != poll.NicknameRequestPending {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+
+	decided, err := repo.Decide(req.ID, poll.NicknameRequestPending, poll.NicknameRequestApproved, 99)
+	if err != nil || !decided {
+		t.Fatalf("Decide = %v, %v; want true", decided, err)
+	}
+	// A decided request cannot be decided again
+	decided, err = repo.Decide(req.ID, poll.NicknameRequestPending, poll.NicknameRequestRejected, 98)
+	if err != nil || decided {
+		t.Fatalf("second Decide = %v, %v; want false", decided, err)
+	}
+
+	got, _ = repo.GetByID(req.ID)
+	if got.Status != poll.NicknameRequestApproved || got.DecidedBy != 99 {
+		t.Errorf("expected approved by 99, got %+v", got)
+	}
+
+	if missing, err := repo.GetByID(req.ID + 1); err != nil || missing != nil {
+		t.Errorf("GetByID(missing) = %+v, %v; want nil", missing, err)
+	}
+}
+
+func TestNicknameRequestRepository_HasPending(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRequestRepository(db)
+
+	req := &poll.NicknameRequest{TgChatID: -123456, TgUserID: 1, TgFirstName: "Player", GameNick: "Кот"}
+	repo.Create(req)
+
+	if pending, err := repo.HasPending(-123456, 1); err != nil || !pending {
+		t.Errorf("HasPending = %v, %v; want true", pending, err)
+	}
+	if pending, _ := repo.HasPending(-654321, 1); pending {
+		t.Error("expected no pending request in another chat")
+	}
+	repo.Decide(req.ID, poll.NicknameRequestPending, poll.NicknameRequestRejected, 99)
+	if pending, _ := repo.HasPending(-123456, 1); pending {
+		t.Error("expected no pending request after the decision")
+	}
+}
@@ -17,16 +17,24 @@ func NewNicknameRepository(db *DB) *NicknameRepository {
 	return &NicknameRepository{db: db}
 }
 
+// Nickname lookups see the club's own nicknames and the shared ones; on a clash the club's nickname wins.
+const (
+	clubNicknamesFilter = "club IN (?, '')"
+	clubNicknamesFirst  = "club = ''"
+)
+
 // isUniqueConstraintError checks if the error is a SQLite unique constraint violation.
 func isUniqueConstraintError(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
 
-// Create inserts a new nickname record if game_nick is not already taken.
+// Create inserts a new nickname record in the club's namespace (poll.SharedNicknames for all clubs)
+// if game_nick is not already taken there, ignoring case (see Exists).
 // Returns true if inserted, false if game_nick is already used by another player.
 // Username is normalized to lowercase before storing.
 // Gender should be "male", "female", or empty string for not set.
-func (r *NicknameRepository) Create(tgUserID *int64, tgUsername *string, gameNick string, gender string) (bool, error) {
+// actorUserID is the admin who created the record (0 if unknown).
+func (r *NicknameRepository) Create(club poll.Club, tgUserID *int64, tgUsername *string, gameNick string, gender string, actorUserID int64) (bool, error) {
 	// Normalize username for storage
 	var normalizedUsername *string
 	if tgUsername != nil {
@@ -40,44 +48,117 @@ func (r *NicknameRepository) Create(tgUserID *int64, tgUsername *string, gameNic
 		genderVal = &gender
 	}
 
-	// Check if game_nick is already taken (globally unique)
-	var exists bool
-	err := r.db.db.QueryRow(`
-		SELECT EXISTS(SELECT 1 FROM nicknames WHERE game_nick = ?)
-	`, gameNick).Scan(&exists)
+	tx, err := r.db.db.Begin()
 	if err != nil {
-		return false, fmt.Errorf("check duplicate: %w", err)
+		return false, fmt.Errorf("begin transaction: %w", err)
 	}
-	if exists {
-		return false, nil
-	}
-
-	_, err = r.db.db.Exec(`
-		INSERT INTO nicknames (tg_user_id, tg_username, game_nick, gender, created_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, tgUserID, normalizedUsername, gameNick, genderVal, time.Now())
+	defer tx.Rollback()
+
+	// Insert first: the write locks the database, so a concurrent Create waits for
+	// this transaction instead of passing the clash check alongside it.
+	result, err := tx.Exec(`
+		INSERT INTO nicknames (club, tg_user_id, tg_username, game_nick, gender, actor_user_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, string(club), tgUserID, normalizedUsername, gameNick, genderVal, nullInt64(actorUserID), time.Now())
 	if err != nil {
-		// Handle unique constraint violation (race condition)
 		if isUniqueConstraintError(err) {
 			return false, nil
 		}
 		return false, fmt.Errorf("insert nickname: %w", err)
 	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("last insert id: %w", err)
+	}
+
+	// The unique index only covers an exact (club, game_nick) pair, so clashes with the
+	// shared namespace and in another case are checked here
+	taken, err := nicknameTaken(tx, club, gameNick, id)
+	if err != nil {
+		return false, err
+	}
+	if taken {
+		return false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit nickname: %w", err)
+	}
 	return true, nil
 }
 
-// FindByGameNick returns the telegram identity for a game nickname.
+// Exists reports whether game_nick would clash in the club's namespace:
+// a club nickname clashes with the same club and the shared namespace,
+// a shared nickname (poll.SharedNicknames) clashes with every club.
+// Nicknames differing only in case clash, so "Кот" and "кот" are not both created.
+func (r *NicknameRepository) Exists(club poll.Club, gameNick string) (bool, error) {
+	return nicknameTaken(r.db.db, club, gameNick, 0)
+}
+
+// nicknameTaken implements Exists, skipping the nickname with exceptID. SQLite's NOCASE
+// only folds ASCII, so the case-insensitive comparison is done here rather than in SQL.
+func nicknameTaken(q querier, club poll.Club, gameNick string, exceptID int64) (bool, error) {
+	rows, err := q.Query(`
+		SELECT game_nick FROM nicknames
+		WHERE length(game_nick) = length(?) AND (club = ? OR club = '' OR ? = '') AND id != ?
+	`, gameNick, string(club), string(club), exceptID)
+	if err != nil {
+		return false, fmt.Errorf("check duplicate: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var nick string
+		if err := rows.Scan(&nick); err != nil {
+			return false, fmt.Errorf("check duplicate: %w", err)
+		}
+		if strings.EqualFold(nick, gameNick) {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("check duplicate: %w", err)
+	}
+	return false, nil
+}
+
+// Get returns the nickname record visible in the club, or nil if there is none.
+func (r *NicknameRepository) Get(club poll.Club, gameNick string) (*poll.Nickname, error) {
+	var userID sql.NullInt64
+	var username, gender sql.NullString
+	var clubStr string
+	n := &poll.Nickname{}
+	err := r.db.db.QueryRow(`
+		SELECT club, tg_user_id, tg_username, game_nick, gender
+		FROM nicknames
+		WHERE game_nick = ? AND `+clubNicknamesFilter+`
+		ORDER BY `+clubNicknamesFirst+`
+		LIMIT 1
+	`, gameNick, string(club)).Scan(&clubStr, &userID, &username, &n.Nick, &gender)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get nickname: %w", err)
+	}
+	n.Club = poll.Club(clubStr)
+	n.TgUserID = userID.Int64
+	n.TgUsername = username.String
+	n.Gender = gender.String
+	return n, nil
+}
+
+// FindByGameNick returns the telegram identity for a game nickname visible in the club.
 // Returns the most recently added match if multiple exist.
-func (r *NicknameRepository) FindByGameNick(gameNick string) (tgUserID *int64, tgUsername *string, err error) {
+func (r *NicknameRepository) FindByGameNick(club poll.Club, gameNick string) (tgUserID *int64, tgUsername *string, err error) {
 	var userID sql.NullInt64
 	var username sql.NullString
 	err = r.db.db.QueryRow(`
 		SELECT tg_user_id, tg_username
 		FROM nicknames
-		WHERE game_nick = ?
-		ORDER BY created_at DESC
+		WHERE game_nick = ? AND `+clubNicknamesFilter+`
+		ORDER BY `+clubNicknamesFirst+`, created_at DESC
 		LIMIT 1
-	`, gameNick).Scan(&userID, &username)
+	`, gameNick, string(club)).Scan(&userID, &username)
 	if err == sql.ErrNoRows {
 		return nil, nil, nil
 	}
@@ -93,17 +174,17 @@ func (r *NicknameRepository) FindByGameNick(gameNick string) (tgUserID *int64, t
 	return tgUserID, tgUsername, nil
 }
 
-// FindByTgUsername returns the most recent game nickname and user ID for a telegram username.
+// FindByTgUsername returns the most recent game nickname visible in the club and user ID for a telegram username.
 // Username is normalized to lowercase for lookup.
-func (r *NicknameRepository) FindByTgUsername(username string) (gameNick string, tgUserID *int64, err error) {
+func (r *NicknameRepository) FindByTgUsername(club poll.Club, username string) (gameNick string, tgUserID *int64, err error) {
 	var userID sql.NullInt64
 	err = r.db.db.QueryRow(`
 		SELECT game_nick, tg_user_id
 		FROM nicknames
-		WHERE tg_username = ?
-		ORDER BY created_at DESC
+		WHERE tg_username = ? AND `+clubNicknamesFilter+`
+		ORDER BY `+clubNicknamesFirst+`, created_at DESC
 		LIMIT 1
-	`, poll.NormalizeUsername(username)).Scan(&gameNick, &userID)
+	`, poll.NormalizeUsername(username), string(club)).Scan(&gameNick, &userID)
 	if err == sql.ErrNoRows {
 		return "", nil, nil
 	}
@@ -116,15 +197,15 @@ func (r *NicknameRepository) FindByTgUsername(username string) (gameNick string,
 	return gameNick, tgUserID, nil
 }
 
-// FindByTgUserID returns the most recent game nickname for a telegram user ID.
-func (r *NicknameRepository) FindByTgUserID(userID int64) (gameNick string, err error) {
+// FindByTgUserID returns the most recent game nickname visible in the club for a telegram user ID.
+func (r *NicknameRepository) FindByTgUserID(club poll.Club, userID int64) (gameNick string, err error) {
 	err = r.db.db.QueryRow(`
 		SELECT game_nick
 		FROM nicknames
-		WHERE tg_user_id = ?
-		ORDER BY created_at DESC
+		WHERE tg_user_id = ? AND `+clubNicknamesFilter+`
+		ORDER BY `+clubNicknamesFirst+`, created_at DESC
 		LIMIT 1
-	`, userID).Scan(&gameNick)
+	`, userID, string(club)).Scan(&gameNick)
 	if err == sql.ErrNoRows {
 		return "", nil
 	}
@@ -134,13 +215,13 @@ func (r *NicknameRepository) FindByTgUserID(userID int64) (gameNick string, err
 	return gameNick, nil
 }
 
-// GetDisplayNick returns the game nickname for display, given a user ID or username.
+// GetDisplayNick returns the game nickname visible in the club for display, given a user ID or username.
 // Checks by user ID first, then by username.
 // Username is normalized to lowercase for lookup.
-func (r *NicknameRepository) GetDisplayNick(userID int64, username string) (string, error) {
+func (r *NicknameRepository) GetDisplayNick(club poll.Club, userID int64, username string) (string, error) {
 	// Try by user ID first (more reliable)
 	if userID > 0 {
-		nick, err := r.FindByTgUserID(userID)
+		nick, err := r.FindByTgUserID(club, userID)
 		if err != nil {
 			return "", err
 		}
@@ -151,7 +232,7 @@ func (r *NicknameRepository) GetDisplayNick(userID int64, username string) (stri
 
 	// Fall back to username (FindByTgUsername normalizes internally)
 	if username != "" {
-		nick, _, err := r.FindByTgUsername(username)
+		nick, _, err := r.FindByTgUsername(club, username)
 		if err != nil {
 			return "", err
 		}
@@ -238,11 +319,11 @@ func (r *NicknameRepository) GetAllGameNicksForUser(userID int64, username strin
 	return nicks, rows.Err()
 }
 
-// GetDisplayNicksBatch returns game nicknames for multiple users in a single query.
+// GetDisplayNicksBatch returns game nicknames visible in the club for multiple users in a single query.
 // Returns a map from user ID or username to NicknameInfo (nick + gender).
 // For users with both ID and username, the result is keyed by user ID.
 // Usernames are normalized to lowercase for lookup and in the returned map.
-func (r *NicknameRepository) GetDisplayNicksBatch(keys []poll.NicknameLookupKey) (map[int64]poll.NicknameInfo, map[string]poll.NicknameInfo, error) {
+func (r *NicknameRepository) GetDisplayNicksBatch(club poll.Club, keys []poll.NicknameLookupKey) (map[int64]poll.NicknameInfo, map[string]poll.NicknameInfo, error) {
 	if len(keys) == 0 {
 		return make(map[int64]poll.NicknameInfo), make(map[string]poll.NicknameInfo), nil
 	}
@@ -274,17 +355,18 @@ func (r *NicknameRepository) GetDisplayNicksBatch(keys []poll.NicknameLookupKey)
 	if len(userIDs) > 0 {
 		// Build placeholders
 		placeholders := make([]string, len(userIDs))
-		args := make([]any, len(userIDs))
+		args := make([]any, len(userIDs), len(userIDs)+1)
 		for i, id := range userIDs {
 			placeholders[i] = "?"
 			args[i] = id
 		}
+		args = append(args, string(club))
 
 		query := fmt.Sprintf(`
 			SELECT tg_user_id, game_nick, gender
 			FROM nicknames
-			WHERE tg_user_id IN (%s)
-			ORDER BY created_at DESC
+			WHERE tg_user_id IN (%s) AND `+clubNicknamesFilter+`
+			ORDER BY `+clubNicknamesFirst+`, created_at DESC
 		`, strings.Join(placeholders, ","))
 
 		rows, err := r.db.db.Query(query, args...)
@@ -313,17 +395,18 @@ func (r *NicknameRepository) GetDisplayNicksBatch(keys []poll.NicknameLookupKey)
 	// Query by usernames if any
 	if len(usernames) > 0 {
 		placeholders := make([]string, len(usernames))
-		args := make([]any, len(usernames))
+		args := make([]any, len(usernames), len(usernames)+1)
 		for i, u := range usernames {
 			placeholders[i] = "?"
 			args[i] = u
 		}
+		args = append(args, string(club))
 
 		query := fmt.Sprintf(`
 			SELECT tg_username, game_nick, gender
 			FROM nicknames
-			WHERE tg_username IN (%s)
-			ORDER BY created_at DESC
+			WHERE tg_username IN (%s) AND `+clubNicknamesFilter+`
+			ORDER BY `+clubNicknamesFirst+`, created_at DESC
 		`, strings.Join(placeholders, ","))
 
 		rows, err := r.db.db.Query(query, args...)
@@ -351,3 +434,189 @@ func (r *NicknameRepository) GetDisplayNicksBatch(keys []poll.NicknameLookupKey)
 
 	return byUserID, byUsername, nil
 }
+
+// List returns the nickname records visible in the club (its own and shared) ordered by game nickname.
+func (r *NicknameRepository) List(club poll.Club) ([]*poll.Nickname, error) {
+	rows, err := r.db.db.Query(`
+		SELECT club, tg_user_id, tg_username, game_nick, gender
+		FROM nicknames
+		WHERE `+clubNicknamesFilter+`
+		ORDER BY game_nick COLLATE NOCASE
+	`, string(club))
+	if err != nil {
+		return nil, fmt.Errorf("query nicknames: %w", err)
+	}
+	defer rows.Close()
+
+	var nicknames []*poll.Nickname
+	for rows.Next() {
+		var userID sql.NullInt64
+		var username, gender sql.NullString
+		var clubStr string
+		n := &poll.Nickname{}
+		if err := rows.Scan(&clubStr, &userID, &username, &n.Nick, &gender); err != nil {
+			return nil, fmt.Errorf("scan nickname: %w", err)
+		}
+		n.Club = poll.Club(clubStr)
+		n.TgUserID = userID.Int64
+		n.TgUsername = username.String
+		n.Gender = gender.String
+		nicknames = append(nicknames, n)
+	}
+	return nicknames, rows.Err()
+}
+
+// Delete removes a game nickname from the given namespace. Returns false if it does not exist.
+// The history recorded under the nickname's synthetic ID (see poll.ManualUserID) moves to the
+// player's remaining identity in the same transaction: their Telegram ID, else their username.
+// Returns poll.ErrNickHasHistory if the nickname is the player's only identity and has history.
+func (r *NicknameRepository) Delete(club poll.Club, gameNick string) (bool, error) {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var userID sql.NullInt64
+	var username sql.NullString
+	err = tx.QueryRow(`SELECT tg_user_id, tg_username FROM nicknames WHERE game_nick = ? AND club = ?`, gameNick, string(club)).Scan(&userID, &username)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get nickname: %w", err)
+	}
+
+	var newID int64
+	switch {
+	case userID.Int64 > 0:
+		newID = userID.Int64
+	case username.String != "":
+		newID = poll.ManualUserID(username.String)
+	default:
+		history, err := hasPlayerHistory(tx, club, poll.ManualUserID(gameNick))
+		if err != nil {
+			return false, err
+		}
+		if history {
+			return false, poll.ErrNickHasHistory
+		}
+	}
+	if newID != 0 {
+		if err := rekeyPlayer(tx, club, poll.ManualUserID(gameNick), newID); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM nicknames WHERE game_nick = ? AND club = ?`, gameNick, string(club)); err != nil {
+		return false, fmt.Errorf("delete nickname: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit nickname deletion: %w", err)
+	}
+	return true, nil
+}
+
+// Rename changes a game nickname in the given namespace. Returns false if oldNick does not exist.
+// The caller checks that the new nickname is not taken (see Exists). The history recorded under
+// the old nickname's synthetic ID moves to the new one in the same transaction.
+func (r *NicknameRepository) Rename(club poll.Club, oldNick, newNick string) (bool, error) {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE nicknames SET game_nick = ? WHERE game_nick = ? AND club = ?`, newNick, oldNick, string(club))
+	if err != nil {
+		return false, fmt.Errorf("rename nickname: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+	if err := rekeyPlayer(tx, club, poll.ManualUserID(oldNick), poll.ManualUserID(newNick)); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit nickname rename: %w", err)
+	}
+	return true, nil
+}
+
+// playerTables are the tables that record a player's history by user ID. Those with
+// one row per poll and player are unique; the rest may hold several rows of a player.
+var playerTables = []struct {
+	table, column, pollScope string
+	unique                   bool
+}{
+	{"votes", "tg_user_id", "poll_id", false},
+	{"attendance", "tg_user_id", "poll_id", true},
+	{"payments", "tg_user_id", "poll_id", true},
+	{"guests", "host_user_id", "poll_id", true},
+	{"seats", "tg_user_id", "poll_id", false},
+	{"game_participants", "tg_user_id", "(SELECT poll_id FROM games WHERE games.id = game_id)", false},
+}
+
+// clubPolls selects the polls a namespace's nicknames are used in: the club's polls,
+// or every poll for shared nicknames (they clash with every club's nicknames).
+const clubPolls = `SELECT id FROM polls WHERE club = ? OR ? = ''`
+
+// rekeyPlayer moves the club's history of a player from oldID to newID. In unique tables
+// a row newID already has for the same poll wins over the moved one.
+func rekeyPlayer(t *tx, club poll.Club, oldID, newID int64) error {
+	for _, pt := range playerTables {
+		verb := "UPDATE"
+		if pt.unique {
+			verb = "UPDATE OR IGNORE"
+		}
+		query := fmt.Sprintf(`%s %s SET %s = ? WHERE %s = ? AND %s IN (%s)`, verb, pt.table, pt.column, pt.column, pt.pollScope, clubPolls)
+		if _, err := t.Exec(query, newID, oldID, string(club), string(club)); err != nil {
+			return fmt.Errorf("move %s: %w", pt.table, err)
+		}
+		if pt.unique {
+			// Left over where newID already had a row for the poll
+			query := fmt.Sprintf(`DELETE FROM %s WHERE %s = ? AND %s IN (%s)`, pt.table, pt.column, pt.pollScope, clubPolls)
+			if _, err := t.Exec(query, oldID, string(club), string(club)); err != nil {
+				return fmt.Errorf("delete moved %s: %w", pt.table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hasPlayerHistory reports whether the club's polls record anything under the player's ID.
+func hasPlayerHistory(t *tx, club poll.Club, userID int64) (bool, error) {
+	for _, pt := range playerTables {
+		var exists bool
+		query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE %s = ? AND %s IN (%s))`, pt.table, pt.column, pt.pollScope, clubPolls)
+		if err := t.QueryRow(query, userID, string(club), string(club)).Scan(&exists); err != nil {
+			return false, fmt.Errorf("check %s: %w", pt.table, err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetGender updates the gender of a game nickname in the given namespace ("male", "female", or "" to clear).
+// Returns false if the nickname does not exist.
+func (r *NicknameRepository) SetGender(club poll.Club, gameNick string, gender string) (bool, error) {
+	var genderVal *string
+	if gender != "" {
+		genderVal = &gender
+	}
+	result, err := r.db.db.Exec(`UPDATE nicknames SET gender = ? WHERE game_nick = ? AND club = ?`, genderVal, gameNick, string(club))
+	if err != nil {
+		return false, fmt.Errorf("set nickname gender: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestNicknameRepository_Manage(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRepository(db)
+
+	userID := int64(1)
+	username := "Player"
+	if _, err := repo.Create(poll.ClubVanmo, &userID, &username, "Кот", "male", 0); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := repo.Create(poll.ClubVanmo, nil, nil, "Лиса", "", 0); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if renamed, err := repo.Rename(poll.ClubVanmo, "Кот", "Енот"); err != nil || !renamed {
+		t.Fatalf("Rename = %v, %v; want true", renamed, err)
+	}
+	if renamed, err := repo.Rename(poll.ClubVanmo, "Кот", "Барсук"); err != nil || renamed {
+		t.Fatalf("Rename(missing) = %v, %v; want false", renamed, err)
+	}
+	if updated, err := repo.SetGender(poll.ClubVanmo, "Лиса", "female"); err != nil || !updated {
+		t.Fatalf("SetGender = %v, %v; want true", updated, err)
+	}
+
+	nicknames, err := repo.List(poll.ClubVanmo)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(nicknames) != 2 {
+		t.Fatalf("expected 2 nicknames, got %d", len(nicknames))
+	}
+	// Ordered by nickname; the renamed one keeps its link and gender
+	if n := nicknames[0]; n.Nick != "Енот" || n.TgUserID != 1 || n.TgUsername != "player" || n.Gender != "male" {
+		t.Errorf("unexpected first nickname: %+v", n)
+	}
+	if n := nicknames[1]; n.Nick != "Лиса" || n.TgUserID != 0 || n.Gender != "female" {
+		t.Errorf("unexpected second nickname: %+v", n)
+	}
+
+	if deleted, err := repo.Delete(poll.ClubVanmo, "Лиса"); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true", deleted, err)
+	}
+	if deleted, err := repo.Delete(poll.ClubVanmo, "Лиса"); err != nil || deleted {
+		t.Fatalf("Delete(missing) = %v, %v; want false", deleted, err)
+	}
+	if nicknames, _ := repo.List(poll.ClubVanmo); len(nicknames) != 1 {
+		t.Errorf("expected 1 nickname after delete, got %d", len(nicknames))
+	}
+}
+
+func TestNicknameRepository_ClubNamespaces(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRepository(db)
+
+	vanmoCat, tbilissimoCat, sharedFox := int64(1), int64(2), int64(3)
+	// The same nickname in two clubs belongs to different players
+	if created, err := repo.Create(poll.ClubVanmo, &vanmoCat, nil, "Кот", "", 0); err != nil || !created {
+		t.Fatalf("Create(vanmo) = %v, %v; want true", created, err)
+	}
+	if created, err := repo.Create(poll.ClubTbilissimo, &tbilissimoCat, nil, "Кот", "", 0); err != nil || !created {
+		t.Fatalf("Create(tbilissimo) = %v, %v; want true", created, err)
+	}
+	// A shared nickname cannot reuse a club nickname, and vice versa
+	if created, err := repo.Create(poll.SharedNicknames, &sharedFox, nil, "Кот", "", 0); err != nil || created {
+		t.Fatalf("Create(shared Кот) = %v, %v; want false", created, err)
+	}
+	if created, err := repo.Create(poll.SharedNicknames, &sharedFox, nil, "Лиса", "female", 0); err != nil || !created {
+		t.Fatalf("Create(shared Лиса) = %v, %v; want true", created, err)
+	}
+	if created, err := repo.Create(poll.ClubVanmo, &vanmoCat, nil, "Лиса", "", 0); err != nil || created {
+		t.Fatalf("Create(vanmo Лиса) = %v, %v; want false", created, err)
+	}
+	// Nicknames differing only in case clash too
+	if created, err := repo.Create(poll.ClubVanmo, &vanmoCat, nil, "кот", "", 0); err != nil || created {
+		t.Fatalf("Create(vanmo кот) = %v, %v; want false", created, err)
+	}
+	if created, err := repo.Create(poll.ClubTbilissimo, &tbilissimoCat, nil, "ЛИСА", "", 0); err != nil || created {
+		t.Fatalf("Create(tbilissimo ЛИСА) = %v, %v; want false", created, err)
+	}
+	if taken, err := repo.Exists(poll.ClubTbilissimo, "кОт"); err != nil || !taken {
+		t.Fatalf("Exists(tbilissimo кОт) = %v, %v; want true", taken, err)
+	}
+
+	for club, want := range map[poll.Club]int64{poll.ClubVanmo: vanmoCat, poll.ClubTbilissimo: tbilissimoCat} {
+		userID, _, err := repo.FindByGameNick(club, "Кот")
+		if err != nil || userID == nil || *userID != want {
+			t.Errorf("FindByGameNick(%s, Кот) = %v, %v; want %d", club, userID, err, want)
+		}
+		userID, _, err = repo.FindByGameNick(club, "Лиса")
+		if err != nil || userID == nil || *userID != sharedFox {
+			t.Errorf("FindByGameNick(%s, Лиса) = %v, %v; want shared %d", club, userID, err, sharedFox)
+		}
+	}
+
+	byUserID, _, err := repo.GetDisplayNicksBatch(poll.ClubTbilissimo, []poll.NicknameLookupKey{{UserID: vanmoCat}, {UserID: tbilissimoCat}, {UserID: sharedFox}})
+	if err != nil {
+		t.Fatalf("GetDisplayNicksBatch failed: %v", err)
+	}
+	if _, ok := byUserID[vanmoCat]; ok {
+		t.Errorf("VANMO nickname must not be visible in Tbilissimo: %v", byUserID)
+	}
+	if byUserID[tbilissimoCat].Nick != "Кот" || byUserID[sharedFox].Nick != "Лиса" {
+		t.Errorf("unexpected Tbilissimo nicknames: %v", byUserID)
+	}
+
+	if nicknames, _ := repo.List(poll.ClubVanmo); len(nicknames) != 2 || !nicknames[1].Shared() {
+		t.Errorf("expected VANMO Кот and shared Лиса, got %+v", nicknames)
+	}
+}
+
+func TestNicknameRepository_CreateConcurrent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRepository(db)
+
+	// A club and a shared nickname of the same name are not covered by the unique index,
+	// so only the transaction keeps concurrent creations from both succeeding
+	clubs := []poll.Club{poll.ClubVanmo, poll.SharedNicknames, poll.ClubTbilissimo, poll.SharedNicknames}
+	var wg sync.WaitGroup
+	for i, club := range clubs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := int64(i + 1)
+			repo.Create(club, &userID, nil, "Кот", "", 0)
+		}()
+	}
+	wg.Wait()
+
+	var shared, total int
+	for _, club := range []poll.Club{poll.ClubVanmo, poll.ClubTbilissimo} {
+		nicknames, err := repo.List(club)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		for _, n := range nicknames {
+			if n.Shared() {
+				shared++
+			}
+			total++
+		}
+	}
+	// Either one shared nickname (listed in both clubs) or up to one per club
+	if shared > 0 && shared != total {
+		t.Errorf("shared and club nickname Кот both created")
+	}
+}
+
+func TestNicknameRepository_RenameAndDeleteMoveHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRepository(db)
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+	guestRepo := NewGuestRepository(db)
+
+	newPoll := func(club poll.Club) *poll.Poll {
+		p := &poll.Poll{TgChatID: -123456, Club: club, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+		if err := pollRepo.Create(p); err != nil {
+			t.Fatalf("Create poll failed: %v", err)
+		}
+		return p
+	}
+	vanmo, tbilissimo := newPoll(poll.ClubVanmo), newPoll(poll.ClubTbilissimo)
+
+	// Both clubs have a Кот; only VANMO's history moves with its rename
+	oldID := poll.ManualUserID("Кот")
+	for _, p := range []*poll.Poll{vanmo, tbilissimo} {
+		if err := voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: oldID, TgFirstName: "Кот", IsManual: true}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if err := attendanceRepo.Init(p.ID, []int64{oldID}); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+	}
+	if err := guestRepo.Set(vanmo.ID, oldID, 2, 0); err != nil {
+		t.Fatalf("Set guests failed: %v", err)
+	}
+	for _, club := range []poll.Club{poll.ClubVanmo, poll.ClubTbilissimo} {
+		if _, err := repo.Create(club, nil, nil, "Кот", "", 0); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	if renamed, err := repo.Rename(poll.ClubVanmo, "Кот", "Енот"); err != nil || !renamed {
+		t.Fatalf("Rename = %v, %v; want true", renamed, err)
+	}
+	newID := poll.ManualUserID("Енот")
+	votes, err := voteRepo.GetCurrentVotes(vanmo.ID)
+	if err != nil || len(votes) != 1 || votes[0].TgUserID != newID {
+		t.Errorf("VANMO votes after rename = %+v, %v; want one by Енот", votes, err)
+	}
+	if guests, _ := guestRepo.GetByPoll(vanmo.ID); guests[newID] != 2 {
+		t.Errorf("VANMO guests after rename = %v; want 2 of Енот", guests)
+	}
+	if attendance, _ := attendanceRepo.GetByPoll(vanmo.ID); len(attendance) != 1 || attendance[0].TgUserID != newID {
+		t.Errorf("VANMO attendance after rename = %+v; want Енот", attendance)
+	}
+	if votes, _ := voteRepo.GetCurrentVotes(tbilissimo.ID); len(votes) != 1 || votes[0].TgUserID != oldID {
+		t.Errorf("Tbilissimo votes must stay with its Кот, got %+v", votes)
+	}
+
+	// Tbilissimo's Кот has no other identity to keep the history under
+	if deleted, err := repo.Delete(poll.ClubTbilissimo, "Кот"); !errors.Is(err, poll.ErrNickHasHistory) || deleted {
+		t.Fatalf("Delete(history) = %v, %v; want ErrNickHasHistory", deleted, err)
+	}
+	if n, _ := repo.Get(poll.ClubTbilissimo, "Кот"); n == nil {
+		t.Fatal("refused delete must keep the nickname")
+	}
+
+	// Once linked to a Telegram user, the history moves to them
+	userID := int64(42)
+	if _, err := db.db.Exec(`UPDATE nicknames SET tg_user_id = ? WHERE game_nick = 'Енот'`, userID); err != nil {
+		t.Fatalf("link nickname: %v", err)
+	}
+	if deleted, err := repo.Delete(poll.ClubVanmo, "Енот"); err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true", deleted, err)
+	}
+	if votes, _ := voteRepo.GetCurrentVotes(vanmo.ID); len(votes) != 1 || votes[0].TgUserID != userID {
+		t.Errorf("VANMO votes after delete = %+v; want one by %d", votes, userID)
+	}
+	if guests, _ := guestRepo.GetByPoll(vanmo.ID); guests[userID] != 2 {
+		t.Errorf("VANMO guests after delete = %v; want 2 of %d", guests, userID)
+	}
+}
+
+func TestService_SharedNicknameEdits(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNicknameRepository(db)
+	svc := poll.NewService(poll.Repositories{
+		Polls:     NewPollRepository(db),
+		Votes:     NewVoteRepository(db),
+		Nicknames: repo,
+	})
+	if _, err := repo.Create(poll.SharedNicknames, nil, nil, "Лиса", "", 0); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := svc.RenameNickname(poll.ClubVanmo, "Лиса", "Енот", false); !errors.Is(err, poll.ErrNickShared) {
+		t.Errorf("RenameNickname(shared) = %v; want ErrNickShared", err)
+	}
+	if _, err := svc.SetNicknameGender(poll.ClubVanmo, "Лиса", "female", false); !errors.Is(err, poll.ErrNickShared) {
+		t.Errorf("SetNicknameGender(shared) = %v; want ErrNickShared", err)
+	}
+	if _, err := svc.DeleteNickname(poll.ClubVanmo, "Лиса", false); !errors.Is(err, poll.ErrNickShared) {
+		t.Errorf("DeleteNickname(shared) = %v; want ErrNickShared", err)
+	}
+	if _, err := svc.RenameNickname(poll.ClubVanmo, "Лиса", "Енот", true); err != nil {
+		t.Errorf("RenameNickname(shared, allowed) = %v", err)
+	}
+	if n, _ := repo.Get(poll.ClubTbilissimo, "Енот"); n == nil || !n.Shared() {
+		t.Errorf("renamed nickname must stay shared, got %+v", n)
+	}
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type PaymentRepository struct {
+	db *DB
+}
+
+func NewPaymentRepository(db *DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// Record stores a player's payment for a poll, replacing any previous payment.
+func (r *PaymentRepository) Record(p *poll.Payment) error {
+	_, err := r.db.db.Exec(`
+		INSERT INTO payments (poll_id, tg_user_id, amount, actor_user_id, paid_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(poll_id, tg_user_id) DO UPDATE SET
+			amount = excluded.amount,
+			actor_user_id = excluded.actor_user_id,
+			paid_at = excluded.paid_at
+	`, p.PollID, p.TgUserID, p.Amount, nullInt64(p.ActorUserID), p.PaidAt)
+	if err != nil {
+		return fmt.Errorf("record payment: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a player's payment for a poll. Returns false if there was none.
+func (r *PaymentRepository) Delete(pollID, userID int64) (bool, error) {
+	result, err := r.db.db.Exec(`DELETE FROM payments WHERE poll_id = ? AND tg_user_id = ?`, pollID, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete payment: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetPaidUserIDs returns the set of users who paid for a poll.
+func (r *PaymentRepository) GetPaidUserIDs(pollID int64) (map[int64]bool, error) {
+	rows, err := r.db.db.Query(`SELECT tg_user_id FROM payments WHERE poll_id = ?`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("query payments: %w", err)
+	}
+	defer rows.Close()
+
+	paid := make(map[int64]bool)
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("scan payment: %w", err)
+		}
+		paid[userID] = true
+	}
+	return paid, rows.Err()
+}
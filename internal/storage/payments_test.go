@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestPaymentRepository_RecordAndDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	paymentRepo := NewPaymentRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local), Price: 20}
+	pollRepo.Create(p)
+
+	got, err := pollRepo.GetByID(p.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.Price != 20 {
+		t.Errorf("Price = %d, want 20", got.Price)
+	}
+
+	if err := paymentRepo.Record(&poll.Payment{PollID: p.ID, TgUserID: 1, Amount: 20, ActorUserID: 99, PaidAt: time.Now()}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	// Recording again replaces the payment instead of failing on the unique index
+	if err := paymentRepo.Record(&poll.Payment{PollID: p.ID, TgUserID: 1, Amount: 25, PaidAt: time.Now()}); err != nil {
+		t.Fatalf("Record again failed: %v", err)
+	}
+
+	paid, err := paymentRepo.GetPaidUserIDs(p.ID)
+	if err != nil {
+		t.Fatalf("GetPaidUserIDs failed: %v", err)
+	}
+	if len(paid) != 1 || !paid[1] {
+		t.Fatalf("expected user 1 to be paid, got %v", paid)
+	}
+
+	deleted, err := paymentRepo.Delete(p.ID, 1)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true, nil", deleted, err)
+	}
+	deleted, err = paymentRepo.Delete(p.ID, 1)
+	if err != nil || deleted {
+		t.Fatalf("second Delete = %v, %v; want false, nil", deleted, err)
+	}
+}
+
+func TestStatsRepository_GetPlayerStatsDebt(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+	paymentRepo := NewPaymentRepository(db)
+	statsRepo := NewStatsRepository(db)
+
+	const userID = int64(111)
+
+	played := func(day, price int) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:  -123456,
+			Club:      poll.ClubVanmo,
+			EventDate: time.Date(2025, 2, day, 0, 0, 0, 0, time.Local),
+			Price:     price,
+		}
+		pollRepo.Create(p)
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt19)})
+		return p
+	}
+
+	played(1, 20)        // unpaid
+	p2 := played(5, 20)  // paid in full
+	p3 := played(8, 25)  // paid partially
+	played(12, 0)        // no price set: not counted
+	p5 := played(15, 20) // no-show: not counted
+	paymentRepo.Record(&poll.Payment{PollID: p2.ID, TgUserID: userID, Amount: 20, PaidAt: time.Now()})
+	paymentRepo.Record(&poll.Payment{PollID: p3.ID, TgUserID: userID, Amount: 20, PaidAt: time.Now()})
+	attendanceRepo.Toggle(p5.ID, userID, 0)
+
+	stats, err := statsRepo.GetPlayerStats(poll.ClubVanmo, []int64{userID}, poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("GetPlayerStats failed: %v", err)
+	}
+	if stats.Debt != 25 {
+		t.Errorf("Debt = %d, want 25", stats.Debt)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"nuclight.org/consigliere/internal/poll"
@@ -46,9 +47,9 @@ func (r *PollRepository) Create(p *poll.Poll) error {
 		p.Options = poll.DefaultOptions()
 	}
 	result, err := r.db.db.Exec(`
-		INSERT INTO polls (tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, event_date, options, is_active, is_pinned, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, p.TgChatID, string(p.Club), p.TgPollID, p.TgMessageID, p.TgInvitationMessageID, p.TgCancelMessageID, p.TgDoneMessageID, p.StartTime, p.EventDate, optionsToString(p.Options), p.IsActive, p.IsPinned, time.Now())
+		INSERT INTO polls (tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.TgChatID, string(p.Club), p.TgPollID, p.TgMessageID, p.TgInvitationMessageID, p.TgCancelMessageID, p.TgDoneMessageID, p.StartTime, nullInt64(p.JudgeUserID), p.JudgeName, nullInt64(int64(p.Price)), p.EventDate, optionsToString(p.Options), p.IsActive, p.IsPinned, time.Now())
 	if err != nil {
 		return fmt.Errorf("insert poll: %w", err)
 	}
@@ -63,7 +64,7 @@ func (r *PollRepository) Create(p *poll.Poll) error {
 
 func (r *PollRepository) GetLatestActive(chatID int64) (*poll.Poll, error) {
 	row := r.db.db.QueryRow(`
-		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, event_date, options, is_active, is_pinned, created_at
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
 		FROM polls
 		WHERE tg_chat_id = ? AND is_active = 1
 		ORDER BY created_at DESC
@@ -73,9 +74,19 @@ func (r *PollRepository) GetLatestActive(chatID int64) (*poll.Poll, error) {
 	return r.scanPoll(row)
 }
 
+func (r *PollRepository) GetByID(id int64) (*poll.Poll, error) {
+	row := r.db.db.QueryRow(`
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
+		FROM polls
+		WHERE id = ?
+	`, id)
+
+	return r.scanPoll(row)
+}
+
 func (r *PollRepository) GetByTgPollID(tgPollID string) (*poll.Poll, error) {
 	row := r.db.db.QueryRow(`
-		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, event_date, options, is_active, is_pinned, created_at
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
 		FROM polls
 		WHERE tg_poll_id = ?
 	`, tgPollID)
@@ -85,7 +96,7 @@ func (r *PollRepository) GetByTgPollID(tgPollID string) (*poll.Poll, error) {
 
 func (r *PollRepository) GetLatestCancelled(chatID int64) (*poll.Poll, error) {
 	row := r.db.db.QueryRow(`
-		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, event_date, options, is_active, is_pinned, created_at
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
 		FROM polls
 		WHERE tg_chat_id = ? AND is_active = 0
 		ORDER BY created_at DESC
@@ -97,7 +108,7 @@ func (r *PollRepository) GetLatestCancelled(chatID int64) (*poll.Poll, error) {
 
 func (r *PollRepository) GetLatest(chatID int64) (*poll.Poll, error) {
 	row := r.db.db.QueryRow(`
-		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, event_date, options, is_active, is_pinned, created_at
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
 		FROM polls
 		WHERE tg_chat_id = ?
 		ORDER BY created_at DESC
@@ -110,25 +121,99 @@ func (r *PollRepository) GetLatest(chatID int64) (*poll.Poll, error) {
 func (r *PollRepository) Update(p *poll.Poll) error {
 	_, err := r.db.db.Exec(`
 		UPDATE polls
-		SET tg_poll_id = ?, tg_message_id = ?, tg_invitation_message_id = ?, tg_cancel_message_id = ?, tg_done_message_id = ?, start_time = ?, options = ?, is_active = ?, is_pinned = ?, club = ?
+		SET tg_poll_id = ?, tg_message_id = ?, tg_invitation_message_id = ?, tg_cancel_message_id = ?, tg_done_message_id = ?, start_time = ?, judge_user_id = ?, judge_name = ?, price = ?, options = ?, is_active = ?, is_pinned = ?, club = ?
 		WHERE id = ?
-	`, p.TgPollID, p.TgMessageID, p.TgInvitationMessageID, p.TgCancelMessageID, p.TgDoneMessageID, p.StartTime, optionsToString(p.Options), p.IsActive, p.IsPinned, string(p.Club), p.ID)
+	`, p.TgPollID, p.TgMessageID, p.TgInvitationMessageID, p.TgCancelMessageID, p.TgDoneMessageID, p.StartTime, nullInt64(p.JudgeUserID), p.JudgeName, nullInt64(int64(p.Price)), optionsToString(p.Options), p.IsActive, p.IsPinned, string(p.Club), p.ID)
 	if err != nil {
 		return fmt.Errorf("update poll: %w", err)
 	}
 	return nil
 }
 
-func (r *PollRepository) scanPoll(row *sql.Row) (*poll.Poll, error) {
+// GetJudgeLastHosted returns the date of the last non-cancelled event each user hosted in a club.
+// Users who never hosted are absent from the map.
+func (r *PollRepository) GetJudgeLastHosted(club poll.Club, userIDs []int64) (map[int64]time.Time, error) {
+	lastHosted := make(map[int64]time.Time)
+	if len(userIDs) == 0 {
+		return lastHosted, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]any, 0, len(userIDs)+1)
+	args = append(args, string(club))
+	for i, id := range userIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	rows, err := r.db.db.Query(fmt.Sprintf(`
+		SELECT judge_user_id, MAX(substr(event_date, 1, 10))
+		FROM polls
+		WHERE club = ? AND COALESCE(tg_cancel_message_id, 0) = 0 AND judge_user_id IN (%s)
+		GROUP BY judge_user_id
+	`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("query judge last hosted: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var day string
+		if err := rows.Scan(&userID, &day); err != nil {
+			return nil, fmt.Errorf("scan judge last hosted: %w", err)
+		}
+		t, err := time.ParseInLocation(sqlDateLayout, day, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("parse judge last hosted date: %w", err)
+		}
+		lastHosted[userID] = t
+	}
+	return lastHosted, rows.Err()
+}
+
+// ListByClub returns the club's polls with the event date within the period, oldest first.
+func (r *PollRepository) ListByClub(club poll.Club, period poll.StatsPeriod) ([]*poll.Poll, error) {
+	from, to := sqlDate(period.From), sqlDate(period.To)
+	rows, err := r.db.db.Query(`
+		SELECT id, tg_chat_id, club, tg_poll_id, tg_message_id, tg_invitation_message_id, tg_cancel_message_id, tg_done_message_id, start_time, judge_user_id, judge_name, price, event_date, options, is_active, is_pinned, created_at
+		FROM polls
+		WHERE club = ?
+			AND (? = '' OR substr(event_date, 1, 10) >= ?)
+			AND (? = '' OR substr(event_date, 1, 10) < ?)
+		ORDER BY substr(event_date, 1, 10), id
+	`, string(club), from, from, to, to)
+	if err != nil {
+		return nil, fmt.Errorf("query polls: %w", err)
+	}
+	defer rows.Close()
+
+	var polls []*poll.Poll
+	for rows.Next() {
+		p, err := r.scanPoll(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan poll: %w", err)
+		}
+		polls = append(polls, p)
+	}
+	return polls, rows.Err()
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *PollRepository) scanPoll(row rowScanner) (*poll.Poll, error) {
 	var p poll.Poll
 	var clubStr string
-	var tgPollID, startTime sql.NullString
-	var tgMessageID, tgInvitationMessageID, tgCancelMessageID, tgDoneMessageID sql.NullInt64
+	var tgPollID, startTime, judgeName sql.NullString
+	var tgMessageID, tgInvitationMessageID, tgCancelMessageID, tgDoneMessageID, judgeUserID, price sql.NullInt64
 	var optionsStr string
 
 	err := row.Scan(
 		&p.ID, &p.TgChatID, &clubStr, &tgPollID, &tgMessageID, &tgInvitationMessageID, &tgCancelMessageID, &tgDoneMessageID,
-		&startTime, &p.EventDate, &optionsStr, &p.IsActive, &p.IsPinned, &p.CreatedAt,
+		&startTime, &judgeUserID, &judgeName, &price, &p.EventDate, &optionsStr, &p.IsActive, &p.IsPinned, &p.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil // Not found
@@ -144,6 +229,9 @@ func (r *PollRepository) scanPoll(row *sql.Row) (*poll.Poll, error) {
 	p.TgCancelMessageID = int(tgCancelMessageID.Int64)
 	p.TgDoneMessageID = int(tgDoneMessageID.Int64)
 	p.StartTime = startTime.String
+	p.JudgeUserID = judgeUserID.Int64
+	p.JudgeName = judgeName.String
+	p.Price = int(price.Int64)
 	p.Options = parseOptions(optionsStr)
 
 	return &p, nil
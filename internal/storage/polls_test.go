@@ -150,3 +150,110 @@ func TestPollRepository_GetLatestActive_NoPoll(t *testing.T) {
 		t.Fatal("expected nil poll for non-existent chat")
 	}
 }
+
+func TestPollRepository_Judge(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPollRepository(db)
+
+	newPoll := func(day int, judgeID int64, cancelled bool) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:    -123456,
+			Club:        poll.ClubVanmo,
+			EventDate:   time.Date(2025, 2, day, 0, 0, 0, 0, time.Local),
+			JudgeUserID: judgeID,
+			JudgeName:   "Судья",
+		}
+		if cancelled {
+			p.TgCancelMessageID = 42
+		}
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return p
+	}
+
+	p := newPoll(1, 10, false)
+	newPoll(5, 20, false)
+	newPoll(8, 10, false)
+	newPoll(12, 20, true) // cancelled events don't count
+
+	got, err := repo.GetByID(p.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.JudgeUserID != 10 || got.JudgeName != "Судья" {
+		t.Errorf("judge = %d %q, want 10 Судья", got.JudgeUserID, got.JudgeName)
+	}
+
+	// Clearing the judge stores NULL
+	got.JudgeUserID = 0
+	got.JudgeName = ""
+	if err := repo.Update(got); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got, _ = repo.GetByID(p.ID)
+	if got.JudgeUserID != 0 || got.JudgeName != "" {
+		t.Errorf("expected cleared judge, got %d %q", got.JudgeUserID, got.JudgeName)
+	}
+
+	lastHosted, err := repo.GetJudgeLastHosted(poll.ClubVanmo, []int64{10, 20, 30})
+	if err != nil {
+		t.Fatalf("GetJudgeLastHosted failed: %v", err)
+	}
+	if len(lastHosted) != 2 {
+		t.Fatalf("expected 2 judges, got %v", lastHosted)
+	}
+	if got := lastHosted[10].Format("2006-01-02"); got != "2025-02-08" {
+		t.Errorf("judge 10 last hosted %s, want 2025-02-08", got)
+	}
+	if got := lastHosted[20].Format("2006-01-02"); got != "2025-02-05" {
+		t.Errorf("judge 20 last hosted %s, want 2025-02-05", got)
+	}
+}
+
+func TestPollRepository_ListByClub(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewPollRepository(db)
+
+	for _, p := range []*poll.Poll{
+		{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{TgChatID: -2, Club: poll.ClubTbilissimo, EventDate: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), Price: 20},
+	} {
+		if err := repo.Create(p); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	all, err := repo.ListByClub(poll.ClubVanmo, poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("ListByClub failed: %v", err)
+	}
+	if len(all) != 3 || all[0].EventDate.Day() != 28 || all[2].EventDate.Month() != time.April {
+		t.Fatalf("expected 3 vanmo polls by event date, got %+v", all)
+	}
+
+	march, err := repo.ListByClub(poll.ClubVanmo, poll.StatsPeriod{
+		From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("ListByClub failed: %v", err)
+	}
+	if len(march) != 1 || march[0].EventDate.Day() != 10 {
+		t.Errorf("expected the March poll only, got %+v", march)
+	}
+
+	other, err := repo.ListByClub(poll.ClubTbilissimo, poll.StatsPeriod{})
+	if err != nil {
+		t.Fatalf("ListByClub failed: %v", err)
+	}
+	if len(other) != 1 || other[0].Price != 20 {
+		t.Errorf("expected the tbilissimo poll with its price, got %+v", other)
+	}
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+type SeatingRepository struct {
+	db *DB
+}
+
+func NewSeatingRepository(db *DB) *SeatingRepository {
+	return &SeatingRepository{db: db}
+}
+
+// Save replaces the seating of a poll with the given players.
+// Players without a dealt role are stored with a NULL role.
+func (r *SeatingRepository) Save(pollID int64, players []*poll.GameParticipant) error {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM seats WHERE poll_id = ?`, pollID); err != nil {
+		return fmt.Errorf("delete seating: %w", err)
+	}
+
+	for _, p := range players {
+		var role *string
+		if p.Role != "" {
+			s := string(p.Role)
+			role = &s
+		}
+		_, err := tx.Exec(`
+			INSERT INTO seats (poll_id, seat, tg_user_id, tg_username, name, role)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, pollID, p.Seat, p.TgUserID, p.TgUsername, p.Name, role)
+		if err != nil {
+			return fmt.Errorf("insert seat: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit seating: %w", err)
+	}
+	return nil
+}
+
+// GetByPoll returns the seating of a poll ordered by seat.
+// Returns an empty slice if players were not seated yet.
+func (r *SeatingRepository) GetByPoll(pollID int64) ([]*poll.GameParticipant, error) {
+	rows, err := r.db.db.Query(`
+		SELECT seat, tg_user_id, tg_username, name, role
+		FROM seats
+		WHERE poll_id = ?
+		ORDER BY seat
+	`, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("query seating: %w", err)
+	}
+	defer rows.Close()
+
+	var players []*poll.GameParticipant
+	for rows.Next() {
+		var p poll.GameParticipant
+		var username, role sql.NullString
+		if err := rows.Scan(&p.Seat, &p.TgUserID, &username, &p.Name, &role); err != nil {
+			return nil, fmt.Errorf("scan seat: %w", err)
+		}
+		p.TgUsername = username.String
+		p.Role = poll.Role(role.String)
+		players = append(players, &p)
+	}
+	return players, rows.Err()
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestSeatingRepository_SaveAndGet(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	seatRepo := NewSeatingRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+
+	players, err := seatRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(players) != 0 {
+		t.Fatalf("expected no seating, got %d", len(players))
+	}
+
+	seating := []*poll.GameParticipant{
+		{Seat: 2, TgUserID: 1, TgUsername: "cat", Name: "Кот"},
+		{Seat: 1, TgUserID: 2, Name: "Лиса"},
+	}
+	if err := seatRepo.Save(p.ID, seating); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	players, err = seatRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(players) != 2 || players[0].Name != "Лиса" || players[1].TgUsername != "cat" || players[0].Role != "" {
+		t.Fatalf("unexpected seating: %+v, %+v", players[0], players[1])
+	}
+
+	// Saving again replaces the seating, including dealt roles
+	seating[0].Role = poll.RoleDon
+	seating[1].Role = poll.RoleCivilian
+	if err := seatRepo.Save(p.ID, seating[:1]); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	players, err = seatRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(players) != 1 || players[0].Role != poll.RoleDon {
+		t.Errorf("expected replaced seating with role, got %+v", players)
+	}
+}
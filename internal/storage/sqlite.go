@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -8,10 +9,14 @@ import (
 	"strings"
 
 	_ "modernc.org/sqlite"
+
+	"nuclight.org/consigliere/internal/poll"
 )
 
 type DB struct {
-	db *sql.DB
+	db conn
+
+	unassignedNicknames []string // set by Migrate, see UnassignedNicknames
 }
 
 func NewDB(path string) (*DB, error) {
@@ -33,7 +38,7 @@ func NewDB(path string) (*DB, error) {
 		return nil, fmt.Errorf("enable foreign keys: %w", err)
 	}
 
-	return &DB{db: db}, nil
+	return &DB{db: conn{db}}, nil
 }
 
 func (d *DB) Close() error {
@@ -83,7 +88,101 @@ func (d *DB) Migrate() error {
 
 	CREATE INDEX IF NOT EXISTS idx_nicknames_tg_user_id ON nicknames(tg_user_id);
 	CREATE INDEX IF NOT EXISTS idx_nicknames_tg_username ON nicknames(tg_username);
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_nicknames_game_nick_unique ON nicknames(game_nick);
+
+	CREATE TABLE IF NOT EXISTS attendance (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		poll_id INTEGER NOT NULL REFERENCES polls(id),
+		tg_user_id INTEGER NOT NULL,
+		attended INTEGER NOT NULL DEFAULT 1,
+		actor_user_id INTEGER,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_poll_user ON attendance(poll_id, tg_user_id);
+	CREATE INDEX IF NOT EXISTS idx_attendance_tg_user_id ON attendance(tg_user_id);
+
+	CREATE TABLE IF NOT EXISTS reports (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tg_chat_id INTEGER NOT NULL,
+		period TEXT NOT NULL,
+		posted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_chat_period ON reports(tg_chat_id, period);
+
+	CREATE TABLE IF NOT EXISTS games (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		poll_id INTEGER NOT NULL REFERENCES polls(id),
+		number INTEGER NOT NULL,
+		winner TEXT NOT NULL,
+		judge_user_id INTEGER,
+		judge_name TEXT,
+		actor_user_id INTEGER,
+		played_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_games_poll_number ON games(poll_id, number);
+
+	CREATE TABLE IF NOT EXISTS game_participants (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		game_id INTEGER NOT NULL REFERENCES games(id),
+		seat INTEGER NOT NULL,
+		tg_user_id INTEGER NOT NULL,
+		tg_username TEXT,
+		name TEXT NOT NULL,
+		role TEXT NOT NULL
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_game_participants_game_seat ON game_participants(game_id, seat);
+	CREATE INDEX IF NOT EXISTS idx_game_participants_tg_user_id ON game_participants(tg_user_id);
+
+	CREATE TABLE IF NOT EXISTS seats (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		poll_id INTEGER NOT NULL REFERENCES polls(id),
+		seat INTEGER NOT NULL,
+		tg_user_id INTEGER NOT NULL,
+		tg_username TEXT,
+		name TEXT NOT NULL,
+		role TEXT
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_seats_poll_seat ON seats(poll_id, seat);
+
+	CREATE TABLE IF NOT EXISTS payments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		poll_id INTEGER NOT NULL REFERENCES polls(id),
+		tg_user_id INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		actor_user_id INTEGER,
+		paid_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_poll_user ON payments(poll_id, tg_user_id);
+
+	CREATE TABLE IF NOT EXISTS guests (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		poll_id INTEGER NOT NULL REFERENCES polls(id),
+		host_user_id INTEGER NOT NULL,
+		count INTEGER NOT NULL,
+		actor_user_id INTEGER,
+		updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_guests_poll_host ON guests(poll_id, host_user_id);
+
+	CREATE TABLE IF NOT EXISTS nickname_requests (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tg_chat_id INTEGER NOT NULL,
+		tg_user_id INTEGER NOT NULL,
+		tg_username TEXT,
+		tg_first_name TEXT NOT NULL,
+		game_nick TEXT NOT NULL,
+		gender TEXT,
+		status TEXT NOT NULL DEFAULT 'pending',
+		decided_by INTEGER,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		decided_at TIMESTAMP
+	);
 	`
 
 	// Run migrations for schema updates
@@ -95,6 +194,14 @@ func (d *DB) Migrate() error {
 		// Add club column to polls for multi-club support
 		`ALTER TABLE polls ADD COLUMN club TEXT NOT NULL DEFAULT ''`,
 		`UPDATE polls SET club = 'vanmo' WHERE club = ''`,
+		// Add actor_user_id columns to track which admin entered manual votes and nicknames
+		`ALTER TABLE votes ADD COLUMN actor_user_id INTEGER`,
+		`ALTER TABLE nicknames ADD COLUMN actor_user_id INTEGER`,
+		// Add judge columns to polls for the event host
+		`ALTER TABLE polls ADD COLUMN judge_user_id INTEGER`,
+		`ALTER TABLE polls ADD COLUMN judge_name TEXT`,
+		// Index for per-club statistics over a date range (needs the club column above)
+		`CREATE INDEX IF NOT EXISTS idx_polls_club_event_date ON polls(club, event_date)`,
 	}
 
 	_, err := d.db.Exec(schema)
@@ -111,9 +218,169 @@ func (d *DB) Migrate() error {
 		}
 	}
 
+	if err := d.migratePollPrices(); err != nil {
+		return fmt.Errorf("migrate poll prices: %w", err)
+	}
+	if err := d.migrateNicknameClubs(); err != nil {
+		return fmt.Errorf("migrate nickname clubs: %w", err)
+	}
+	for _, migration := range []string{
+		`DROP INDEX IF EXISTS idx_nicknames_game_nick_unique`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_nicknames_club_game_nick ON nicknames(club, game_nick)`,
+	} {
+		if _, err := d.db.Exec(migration); err != nil {
+			return fmt.Errorf("execute migration: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// pricedEventPrice is what every club charged when prices were added to polls.
+const pricedEventPrice = 20
+
+// migratePollPrices adds the price column to polls, once. Events still being announced
+// get the price they were announced with; past events are left without a price,
+// so that nobody owes for them.
+func (d *DB) migratePollPrices() error {
+	migrated, err := d.hasColumn("polls", "price")
+	if err != nil || migrated {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`ALTER TABLE polls ADD COLUMN price INTEGER`); err != nil {
+		return fmt.Errorf("add price column: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE polls SET price = ? WHERE is_active = 1`, pricedEventPrice); err != nil {
+		return fmt.Errorf("set active poll prices: %w", err)
+	}
+	return tx.Commit()
+}
+
+// hasColumn reports whether a table has a column.
+func (d *DB) hasColumn(table, column string) (bool, error) {
+	var found bool
+	err := d.db.QueryRow(`SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("check %s.%s column: %w", table, column, err)
+	}
+	return found, nil
+}
+
+// migrateNicknameClubs scopes nicknames by club, once: each existing nickname moves to the club
+// its owner voted in. Nicknames of owners who voted in several clubs stay in the shared namespace,
+// as do nicknames of owners who never voted; the latter are listed by UnassignedNicknames.
+func (d *DB) migrateNicknameClubs() error {
+	migrated, err := d.hasColumn("nicknames", "club")
+	if err != nil || migrated {
+		return err
+	}
+
+	type nickname struct {
+		id       int64
+		userID   sql.NullInt64
+		username sql.NullString
+		nick     string
+	}
+	rows, err := d.db.Query(`SELECT id, tg_user_id, tg_username, game_nick FROM nicknames`)
+	if err != nil {
+		return fmt.Errorf("query nicknames: %w", err)
+	}
+	var nicknames []nickname
+	for rows.Next() {
+		var n nickname
+		if err := rows.Scan(&n.id, &n.userID, &n.username, &n.nick); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan nickname: %w", err)
+		}
+		nicknames = append(nicknames, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("query nicknames: %w", err)
+	}
+
+	// The owner's votes are found by their ID, their username, and the synthetic IDs
+	// of manual votes for the username or the nickname
+	clubs := make(map[int64]string, len(nicknames))
+	for _, n := range nicknames {
+		username := poll.NormalizeUsername(n.username.String)
+		rows, err := d.db.Query(`
+			SELECT DISTINCT p.club FROM votes v
+			JOIN polls p ON p.id = v.poll_id
+			WHERE v.tg_user_id IN (?, ?) OR (? <> '' AND (v.tg_user_id = ? OR LOWER(v.tg_username) = ?))
+		`, n.userID.Int64, poll.ManualUserID(n.nick), username, poll.ManualUserID(username), username)
+		if err != nil {
+			return fmt.Errorf("query nickname clubs: %w", err)
+		}
+		var voted []string
+		for rows.Next() {
+			var club string
+			if err := rows.Scan(&club); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan nickname club: %w", err)
+			}
+			voted = append(voted, club)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("query nickname clubs: %w", err)
+		}
+
+		switch len(voted) {
+		case 0:
+			d.unassignedNicknames = append(d.unassignedNicknames, n.nick)
+		case 1:
+			clubs[n.id] = voted[0]
+		}
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`ALTER TABLE nicknames ADD COLUMN club TEXT NOT NULL DEFAULT ''`); err != nil {
+		return fmt.Errorf("add club column: %w", err)
+	}
+	for id, club := range clubs {
+		if _, err := tx.Exec(`UPDATE nicknames SET club = ? WHERE id = ?`, club, id); err != nil {
+			return fmt.Errorf("set nickname club: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UnassignedNicknames returns the nicknames that Migrate left in the shared namespace
+// because their owners never voted, so it is unknown which club they belong to.
+func (d *DB) UnassignedNicknames() []string {
+	return d.unassignedNicknames
+}
+
 func (d *DB) DB() *sql.DB {
-	return d.db
+	return d.db.db
+}
+
+// Ping checks that the database is reachable and not locked for reading.
+func (d *DB) Ping(ctx context.Context) error {
+	var n int
+	if err := d.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&n); err != nil {
+		return fmt.Errorf("ping database: %w", err)
+	}
+	return nil
+}
+
+// nullInt64 converts a zero ID to NULL for nullable columns.
+func nullInt64(v int64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
 }
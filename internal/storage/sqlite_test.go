@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"context"
 	"os"
 	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
 )
 
 func TestNewDB_CreatesFile(t *testing.T) {
@@ -48,3 +52,126 @@ func TestMigrate_CreatesTables(t *testing.T) {
 		t.Errorf("votes table not found: %v", err)
 	}
 }
+
+func TestDB_Ping(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	if err := db.Ping(context.Background()); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+
+	db.Close()
+	if err := db.Ping(context.Background()); err == nil {
+		t.Error("Ping of a closed database: expected error")
+	}
+}
+
+func TestMigrate_AssignsNicknamesToClubs(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	vanmo := &poll.Poll{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	tbilissimo := &poll.Poll{TgChatID: -2, Club: poll.ClubTbilissimo, EventDate: time.Date(2025, 2, 2, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(vanmo)
+	pollRepo.Create(tbilissimo)
+	vote := func(p *poll.Poll, userID int64, username string) {
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: userID, TgUsername: username, TgFirstName: "Player", TgOptionIndex: int(poll.OptionComeAt19)})
+	}
+	vote(vanmo, 1, "")                         // by ID
+	vote(tbilissimo, 2, "Fox")                 // by username
+	vote(vanmo, poll.ManualUserID("Енот"), "") // manual vote by nickname
+	vote(vanmo, 4, "")                         // in both clubs
+	vote(tbilissimo, 4, "")
+
+	// Roll back to the schema before nicknames had clubs
+	for _, stmt := range []string{
+		`DROP INDEX idx_nicknames_club_game_nick`,
+		`ALTER TABLE nicknames DROP COLUMN club`,
+		`CREATE UNIQUE INDEX idx_nicknames_game_nick_unique ON nicknames(game_nick)`,
+		`INSERT INTO nicknames (tg_user_id, tg_username, game_nick) VALUES
+			(1, NULL, 'Кот'), (NULL, 'fox', 'Лиса'), (NULL, NULL, 'Енот'), (4, NULL, 'Гость'), (5, NULL, 'Новичок')`,
+	} {
+		if _, err := db.db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	want := map[string]string{
+		"Кот":     "vanmo",
+		"Лиса":    "tbilissimo",
+		"Енот":    "vanmo",
+		"Гость":   "",
+		"Новичок": "",
+	}
+	for nick, club := range want {
+		var got string
+		if err := db.db.QueryRow(`SELECT club FROM nicknames WHERE game_nick = ?`, nick).Scan(&got); err != nil {
+			t.Fatalf("query %s: %v", nick, err)
+		}
+		if got != club {
+			t.Errorf("club of %s = %q, want %q", nick, got, club)
+		}
+	}
+	if got := db.UnassignedNicknames(); len(got) != 1 || got[0] != "Новичок" {
+		t.Errorf("UnassignedNicknames = %v, want [Новичок]", got)
+	}
+
+	// Nicknames are only moved once, so later shared ones stay shared
+	nickRepo := NewNicknameRepository(db)
+	nickRepo.Create(poll.SharedNicknames, nil, nil, "Сова", "", 0)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("second Migrate failed: %v", err)
+	}
+	if exists, _ := nickRepo.Exists(poll.ClubTbilissimo, "Сова"); !exists {
+		t.Error("shared nickname was moved by a repeated migration")
+	}
+}
+
+func TestMigrate_PricesActivePolls(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	past := &poll.Poll{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	live := &poll.Poll{TgChatID: -1, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 8, 0, 0, 0, 0, time.Local), IsActive: true}
+	pollRepo.Create(past)
+	pollRepo.Create(live)
+
+	// Roll back to the schema before polls had prices
+	if _, err := db.db.Exec(`ALTER TABLE polls DROP COLUMN price`); err != nil {
+		t.Fatalf("drop price column: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		p    *poll.Poll
+		want int
+	}{{past, 0}, {live, pricedEventPrice}} {
+		got, err := pollRepo.GetByID(tt.p.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if got.Price != tt.want {
+			t.Errorf("price of poll %d = %d, want %d", tt.p.ID, got.Price, tt.want)
+		}
+	}
+
+	// A price cleared later stays cleared
+	live.Price = 0
+	pollRepo.Update(live)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("second Migrate failed: %v", err)
+	}
+	if got, _ := pollRepo.GetByID(live.ID); got.Price != 0 {
+		t.Errorf("price after second Migrate = %d, want 0", got.Price)
+	}
+}
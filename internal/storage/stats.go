@@ -0,0 +1,375 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+// sqlDateLayout is the layout of the date prefix of stored event dates.
+// Event dates are stored in Go's time.String() format, so the first 10 characters
+// are always YYYY-MM-DD in the event's local time and can be compared as strings.
+const sqlDateLayout = "2006-01-02"
+
+// sqlDate formats a time as YYYY-MM-DD for comparison with stored event dates.
+// Returns empty string for zero time (no bound).
+func sqlDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(sqlDateLayout)
+}
+
+// playerPollsCTE selects one row per player per poll of a club within a period (player_polls):
+// the player's latest vote under any of their identities, whether any identity was marked as a no-show,
+// and the payments and guests under all identities added up. aliases map synthetic IDs to the ID
+// of the player they belong to; other IDs stand for themselves.
+// played narrows player_polls to the events the player played in: the vote is attending, the event
+// is not cancelled, has already taken place, and the player was not marked as a no-show.
+// Returns the CTE and its query arguments.
+func playerPollsCTE(club poll.Club, period poll.StatsPeriod, aliases map[int64]int64) (string, []any) {
+	// An empty VALUES list is not valid SQL, so no aliases is an empty SELECT instead
+	values := "SELECT 0, 0 WHERE 0"
+	args := make([]any, 0, 2*len(aliases)+6)
+	if len(aliases) > 0 {
+		rows := make([]string, 0, len(aliases))
+		for alias, playerID := range aliases {
+			rows = append(rows, "(?, ?)")
+			args = append(args, alias, playerID)
+		}
+		values = "VALUES " + strings.Join(rows, ", ")
+	}
+
+	from, to := sqlDate(period.From), sqlDate(period.To)
+	args = append(args, string(club), from, from, to, to, sqlDate(time.Now()))
+
+	return `
+	WITH aliases(alias_id, player_id) AS (` + values + `),
+	player_votes AS (
+		SELECT v.poll_id, COALESCE(al.player_id, v.tg_user_id) AS player_id,
+			v.tg_user_id, v.tg_username, v.tg_first_name, v.tg_option_index, v.voted_at
+		FROM votes v
+		JOIN polls p ON p.id = v.poll_id
+		LEFT JOIN aliases al ON al.alias_id = v.tg_user_id
+		WHERE p.club = ?
+			AND (? = '' OR substr(p.event_date, 1, 10) >= ?)
+			AND (? = '' OR substr(p.event_date, 1, 10) < ?)
+	),
+	latest AS (
+		SELECT *, ROW_NUMBER() OVER (PARTITION BY poll_id, player_id ORDER BY voted_at DESC) AS rn
+		FROM player_votes
+	),
+	player_polls AS (
+		SELECT l.poll_id, l.player_id, l.tg_username, l.tg_first_name, l.tg_option_index, l.voted_at,
+			substr(p.event_date, 1, 10) AS event_day,
+			COALESCE(p.tg_cancel_message_id, 0) > 0 AS cancelled,
+			COALESCE(p.price, 0) AS price,
+			(SELECT MIN(a.attended) FROM attendance a LEFT JOIN aliases al ON al.alias_id = a.tg_user_id
+				WHERE a.poll_id = l.poll_id AND COALESCE(al.player_id, a.tg_user_id) = l.player_id) AS attended,
+			(SELECT COALESCE(SUM(pay.amount), 0) FROM payments pay LEFT JOIN aliases al ON al.alias_id = pay.tg_user_id
+				WHERE pay.poll_id = l.poll_id AND COALESCE(al.player_id, pay.tg_user_id) = l.player_id) AS paid,
+			(SELECT COALESCE(SUM(g.count), 0) FROM guests g LEFT JOIN aliases al ON al.alias_id = g.host_user_id
+				WHERE g.poll_id = l.poll_id AND COALESCE(al.player_id, g.host_user_id) = l.player_id) AS guests
+		FROM latest l
+		JOIN polls p ON p.id = l.poll_id
+		WHERE l.rn = 1
+	),
+	played AS (
+		SELECT * FROM player_polls
+		WHERE tg_option_index BETWEEN 0 AND 2 AND NOT cancelled AND event_day <= ? AND COALESCE(attended, 1) = 1
+	)
+	`, args
+}
+
+type StatsRepository struct {
+	db *DB
+}
+
+func NewStatsRepository(db *DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// GetPlayerStats aggregates attendance statistics for a player in a club.
+// userIDs are all identities the player's votes may be recorded under (real and synthetic);
+// the latest vote across all of them counts per poll.
+// Debt sums the unpaid part of the price over events played that had a price set.
+func (r *StatsRepository) GetPlayerStats(club poll.Club, userIDs []int64, period poll.StatsPeriod) (*poll.PlayerStats, error) {
+	stats := &poll.PlayerStats{}
+	if len(userIDs) == 0 {
+		return stats, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	idArgs := make([]any, len(userIDs))
+	for i, id := range userIDs {
+		placeholders[i] = "?"
+		idArgs[i] = id
+	}
+	in := strings.Join(placeholders, ",")
+
+	query := fmt.Sprintf(`
+		WITH latest AS (
+			SELECT poll_id, tg_option_index,
+				ROW_NUMBER() OVER (PARTITION BY poll_id ORDER BY voted_at DESC) AS rn
+			FROM votes
+			WHERE tg_user_id IN (%s)
+		),
+		player_polls AS (
+			SELECT
+				substr(p.event_date, 1, 10) AS event_day,
+				COALESCE(p.tg_cancel_message_id, 0) > 0 AS cancelled,
+				l.tg_option_index AS option_index,
+				(SELECT MIN(a.attended) FROM attendance a WHERE a.poll_id = p.id AND a.tg_user_id IN (%s)) AS attended,
+				COALESCE(p.price, 0) AS price,
+				(SELECT COALESCE(SUM(pay.amount), 0) FROM payments pay WHERE pay.poll_id = p.id AND pay.tg_user_id IN (%s)) AS paid
+			FROM latest l
+			JOIN polls p ON p.id = l.poll_id
+			WHERE l.rn = 1 AND l.tg_option_index BETWEEN 0 AND 2
+				AND p.club = ?
+				AND (? = '' OR substr(p.event_date, 1, 10) >= ?)
+				AND (? = '' OR substr(p.event_date, 1, 10) < ?)
+		),
+		played AS (
+			SELECT * FROM player_polls
+			WHERE NOT cancelled AND event_day <= ? AND COALESCE(attended, 1) = 1
+		)
+		SELECT
+			(SELECT COUNT(*) FROM player_polls),
+			(SELECT COUNT(*) FROM played),
+			(SELECT COUNT(*) FROM player_polls WHERE cancelled),
+			(SELECT COUNT(*) FROM player_polls WHERE attended = 0),
+			(SELECT COUNT(*) FROM player_polls WHERE option_index = ?),
+			(SELECT MAX(event_day) FROM played),
+			(SELECT COALESCE(SUM(MAX(price - paid, 0)), 0) FROM played WHERE price > 0)
+	`, in, in, in)
+
+	from, to := sqlDate(period.From), sqlDate(period.To)
+	args := make([]any, 0, 3*len(userIDs)+7)
+	args = append(args, idArgs...)
+	args = append(args, idArgs...)
+	args = append(args, idArgs...)
+	args = append(args, string(club), from, from, to, to, sqlDate(time.Now()), int(poll.OptionComeAt21OrLater))
+
+	var lastVisit sql.NullString
+	err := r.db.db.QueryRow(query, args...).Scan(
+		&stats.VotedAttending, &stats.EventsPlayed, &stats.Cancelled, &stats.NoShows, &stats.LateArrivals, &lastVisit, &stats.Debt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query player stats: %w", err)
+	}
+
+	if lastVisit.Valid {
+		t, err := time.ParseInLocation(sqlDateLayout, lastVisit.String, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("parse last visit: %w", err)
+		}
+		stats.LastVisit = t
+	}
+
+	return stats, nil
+}
+
+// GetVoterUsernames returns the latest username of every real user who voted in a club
+// under a username.
+func (r *StatsRepository) GetVoterUsernames(club poll.Club) (map[int64]string, error) {
+	rows, err := r.db.db.Query(`
+		SELECT tg_user_id, tg_username FROM (
+			SELECT v.tg_user_id, v.tg_username,
+				ROW_NUMBER() OVER (PARTITION BY v.tg_user_id ORDER BY v.voted_at DESC) AS rn
+			FROM votes v
+			JOIN polls p ON p.id = v.poll_id
+			WHERE p.club = ? AND v.tg_user_id > 0 AND COALESCE(v.tg_username, '') != ''
+		)
+		WHERE rn = 1
+	`, string(club))
+	if err != nil {
+		return nil, fmt.Errorf("query voter usernames: %w", err)
+	}
+	defer rows.Close()
+
+	usernames := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var username string
+		if err := rows.Scan(&userID, &username); err != nil {
+			return nil, fmt.Errorf("scan voter username: %w", err)
+		}
+		usernames[userID] = username
+	}
+	return usernames, rows.Err()
+}
+
+// GetLeaderboard aggregates the statistics of every player who played in a club within a period,
+// counted like GetPlayerStats, ordered by events played and then by the latest vote
+// for an event played (both descending). The entry is named after the player's latest own vote,
+// or the latest manual one if the player never voted themselves. limit <= 0 means no limit.
+func (r *StatsRepository) GetLeaderboard(club poll.Club, period poll.StatsPeriod, aliases map[int64]int64, limit int) ([]*poll.LeaderboardEntry, error) {
+	cte, args := playerPollsCTE(club, period, aliases)
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, int(poll.OptionComeAt21OrLater), limit)
+
+	rows, err := r.db.db.Query(cte+`,
+	names AS (
+		SELECT player_id, tg_username, tg_first_name,
+			ROW_NUMBER() OVER (PARTITION BY player_id ORDER BY tg_user_id > 0 DESC, voted_at DESC) AS rn
+		FROM player_votes
+	)
+	SELECT pp.player_id, COALESCE(n.tg_username, ''), n.tg_first_name,
+		COUNT(*),
+		COUNT(pl.poll_id) AS events_played,
+		SUM(pp.cancelled),
+		SUM(COALESCE(pp.attended, 1) = 0),
+		SUM(pp.tg_option_index = ?),
+		MAX(pl.event_day),
+		COALESCE(SUM(CASE WHEN pl.price > 0 THEN MAX(pl.price - pl.paid, 0) END), 0),
+		MAX(pl.voted_at) AS last_played_vote
+	FROM player_polls pp
+	LEFT JOIN played pl ON pl.poll_id = pp.poll_id AND pl.player_id = pp.player_id
+	JOIN names n ON n.player_id = pp.player_id AND n.rn = 1
+	WHERE pp.tg_option_index BETWEEN 0 AND 2
+	GROUP BY pp.player_id
+	HAVING events_played > 0
+	ORDER BY events_played DESC, last_played_vote DESC, pp.player_id
+	LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*poll.LeaderboardEntry
+	for rows.Next() {
+		e := &poll.LeaderboardEntry{}
+		var lastVisit, lastPlayedVote sql.NullString
+		err := rows.Scan(&e.TgUserID, &e.TgUsername, &e.TgFirstName,
+			&e.VotedAttending, &e.EventsPlayed, &e.Cancelled, &e.NoShows, &e.LateArrivals,
+			&lastVisit, &e.Debt, &lastPlayedVote)
+		if err != nil {
+			return nil, fmt.Errorf("scan leaderboard entry: %w", err)
+		}
+		if lastVisit.Valid {
+			if e.LastVisit, err = time.ParseInLocation(sqlDateLayout, lastVisit.String, time.Local); err != nil {
+				return nil, fmt.Errorf("parse last visit: %w", err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetPollVotes returns every player's latest vote in each poll of a club within a period,
+// with whether the player was marked as a no-show, how much they paid and how many guests they bring.
+func (r *StatsRepository) GetPollVotes(club poll.Club, period poll.StatsPeriod, aliases map[int64]int64) ([]*poll.PollVote, error) {
+	cte, args := playerPollsCTE(club, period, aliases)
+	rows, err := r.db.db.Query(cte+`
+		SELECT poll_id, player_id, tg_username, tg_first_name, tg_option_index, voted_at,
+			event_day, cancelled, COALESCE(attended, 1) = 0, price, paid, guests
+		FROM player_polls
+		ORDER BY poll_id, voted_at
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query poll votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []*poll.PollVote
+	for rows.Next() {
+		v := &poll.PollVote{}
+		var username sql.NullString
+		var eventDay string
+		err := rows.Scan(&v.PollID, &v.TgUserID, &username, &v.TgFirstName, &v.TgOptionIndex, &v.VotedAt,
+			&eventDay, &v.Cancelled, &v.NoShow, &v.Price, &v.Paid, &v.Guests)
+		if err != nil {
+			return nil, fmt.Errorf("scan poll vote: %w", err)
+		}
+		v.TgUsername = username.String
+		if v.EventDay, err = time.ParseInLocation(sqlDateLayout, eventDay, time.Local); err != nil {
+			return nil, fmt.Errorf("parse event date: %w", err)
+		}
+		votes = append(votes, v)
+	}
+	return votes, rows.Err()
+}
+
+// GetClubSummary aggregates club activity within a period:
+// number of events held, total attendance with guests, the tables they made of tableSize
+// (see poll.TableCount) and the busiest weekday.
+func (r *StatsRepository) GetClubSummary(club poll.Club, period poll.StatsPeriod, aliases map[int64]int64, tableSize int) (*poll.ClubSummary, error) {
+	cte, args := playerPollsCTE(club, period, aliases)
+	cte += `,
+	events AS (
+		SELECT poll_id, event_day, SUM(1 + guests) AS players
+		FROM played
+		GROUP BY poll_id
+	)
+	`
+
+	rows, err := r.db.db.Query(cte+`SELECT players FROM events`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query club summary: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &poll.ClubSummary{}
+	for rows.Next() {
+		var players int
+		if err := rows.Scan(&players); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		summary.EventsHeld++
+		summary.TotalAttendance += players
+		summary.Tables += poll.TableCount(players, tableSize)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query club summary: %w", err)
+	}
+	if summary.EventsHeld == 0 {
+		return summary, nil
+	}
+
+	var weekday int
+	err = r.db.db.QueryRow(cte+`
+		SELECT CAST(strftime('%w', event_day) AS INTEGER) AS weekday, SUM(players) AS total
+		FROM events
+		GROUP BY weekday
+		ORDER BY total DESC, weekday
+		LIMIT 1
+	`, args...).Scan(&weekday, new(int))
+	if err != nil {
+		return nil, fmt.Errorf("query busiest weekday: %w", err)
+	}
+	summary.BusiestWeekday = time.Weekday(weekday)
+
+	return summary, nil
+}
+
+// MarkReportPosted records that a periodic report was posted to a chat.
+// Returns true if the report was not posted before (and is now marked), false otherwise.
+func (r *StatsRepository) MarkReportPosted(chatID int64, period string) (bool, error) {
+	result, err := r.db.db.Exec(`
+		INSERT OR IGNORE INTO reports (tg_chat_id, period, posted_at)
+		VALUES (?, ?, ?)
+	`, chatID, period, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("mark report posted: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UnmarkReportPosted removes the mark of a periodic report posted to a chat.
+func (r *StatsRepository) UnmarkReportPosted(chatID int64, period string) error {
+	_, err := r.db.db.Exec(`DELETE FROM reports WHERE tg_chat_id = ? AND period = ?`, chatID, period)
+	if err != nil {
+		return fmt.Errorf("unmark report posted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"nuclight.org/consigliere/internal/poll"
+)
+
+func TestStatsRepository_GetPlayerStats(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+	statsRepo := NewStatsRepository(db)
+
+	const userID = int64(111)
+	syntheticID := poll.ManualUserID("кот")
+
+	newPoll := func(day int, cancelled bool) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:  -123456,
+			Club:      poll.ClubVanmo,
+			EventDate: time.Date(2025, 2, day, 0, 0, 0, 0, time.Local),
+		}
+		if cancelled {
+			p.TgCancelMessageID = 42
+		}
+		pollRepo.Create(p)
+		return p
+	}
+
+	// Played at 19:00
+	p1 := newPoll(1, false)
+	voteRepo.Record(&poll.Vote{PollID: p1.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt19)})
+
+	// Played at 21:00+ via synthetic manual vote
+	p2 := newPoll(5, false)
+	voteRepo.Record(&poll.Vote{PollID: p2.ID, TgUserID: syntheticID, TgFirstName: "Кот", TgOptionIndex: int(poll.OptionComeAt21OrLater), IsManual: true})
+
+	// Voted, but event cancelled
+	p3 := newPoll(8, true)
+	voteRepo.Record(&poll.Vote{PollID: p3.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt20)})
+
+	// Voted, but did not show up
+	p4 := newPoll(12, false)
+	voteRepo.Record(&poll.Vote{PollID: p4.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt20)})
+	attendanceRepo.Toggle(p4.ID, userID, 0)
+
+	// Changed mind to not coming
+	p5 := newPoll(15, false)
+	voteRepo.Record(&poll.Vote{PollID: p5.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt19)})
+	time.Sleep(10 * time.Millisecond)
+	voteRepo.Record(&poll.Vote{PollID: p5.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionNotComing)})
+
+	// Outside the period
+	p6 := newPoll(29, false) // normalizes to March 1
+	voteRepo.Record(&poll.Vote{PollID: p6.ID, TgUserID: userID, TgFirstName: "Kot", TgOptionIndex: int(poll.OptionComeAt19)})
+
+	period := poll.StatsPeriod{
+		From: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	stats, err := statsRepo.GetPlayerStats(poll.ClubVanmo, []int64{userID, syntheticID}, period)
+	if err != nil {
+		t.Fatalf("GetPlayerStats failed: %v", err)
+	}
+
+	if stats.VotedAttending != 4 {
+		t.Errorf("VotedAttending = %d, want 4", stats.VotedAttending)
+	}
+	if stats.EventsPlayed != 2 {
+		t.Errorf("EventsPlayed = %d, want 2", stats.EventsPlayed)
+	}
+	if stats.Cancelled != 1 {
+		t.Errorf("Cancelled = %d, want 1", stats.Cancelled)
+	}
+	if stats.NoShows != 1 {
+		t.Errorf("NoShows = %d, want 1", stats.NoShows)
+	}
+	if stats.LateArrivals != 1 {
+		t.Errorf("LateArrivals = %d, want 1", stats.LateArrivals)
+	}
+	if got := stats.LastVisit.Format("2006-01-02"); got != "2025-02-05" {
+		t.Errorf("LastVisit = %s, want 2025-02-05", got)
+	}
+
+	// Other club sees nothing
+	other, err := statsRepo.GetPlayerStats(poll.ClubTbilissimo, []int64{userID, syntheticID}, period)
+	if err != nil {
+		t.Fatalf("GetPlayerStats failed: %v", err)
+	}
+	if other.VotedAttending != 0 || !other.LastVisit.IsZero() {
+		t.Errorf("expected empty stats for other club, got %+v", other)
+	}
+}
+
+func TestStatsRepository_LeaderboardAndSummary(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	attendanceRepo := NewAttendanceRepository(db)
+	statsRepo := NewStatsRepository(db)
+	svc := poll.NewService(poll.Repositories{
+		Polls:      pollRepo,
+		Votes:      voteRepo,
+		Nicknames:  NewNicknameRepository(db),
+		Attendance: attendanceRepo,
+		Stats:      statsRepo,
+	})
+
+	newPoll := func(day int, cancelled bool) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:  -123456,
+			Club:      poll.ClubVanmo,
+			EventDate: time.Date(2025, 2, day, 0, 0, 0, 0, time.Local),
+		}
+		if cancelled {
+			p.TgCancelMessageID = 42
+		}
+		pollRepo.Create(p)
+		return p
+	}
+	vote := func(p *poll.Poll, userID int64, name string, option poll.OptionKind) {
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: userID, TgFirstName: name, TgOptionIndex: int(option)})
+	}
+
+	// Saturday, Feb 1: three players, one of them did not show up
+	p1 := newPoll(1, false)
+	vote(p1, 1, "Alice", poll.OptionComeAt19)
+	vote(p1, 2, "Bob", poll.OptionComeAt20)
+	vote(p1, 3, "Carol", poll.OptionComeAt19)
+	attendanceRepo.Toggle(p1.ID, 3, 0)
+
+	// Monday, Feb 3: two players and one not coming
+	p2 := newPoll(3, false)
+	vote(p2, 1, "Alice", poll.OptionComeAt21OrLater)
+	vote(p2, 3, "Carol", poll.OptionComeAt19)
+	vote(p2, 2, "Bob", poll.OptionNotComing)
+
+	// Saturday, Feb 8: one player
+	p3 := newPoll(8, false)
+	vote(p3, 1, "Alice", poll.OptionComeAt19)
+
+	// Cancelled event does not count
+	p4 := newPoll(10, true)
+	vote(p4, 2, "Bob", poll.OptionComeAt19)
+
+	period := poll.StatsPeriod{
+		From: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	entries, err := svc.GetLeaderboard(poll.ClubVanmo, period, 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].TgUserID != 1 || entries[0].EventsPlayed != 3 || entries[0].TgFirstName != "Alice" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	for _, e := range entries[1:] {
+		if e.EventsPlayed != 1 {
+			t.Errorf("expected 1 event played for user %d, got %d", e.TgUserID, e.EventsPlayed)
+		}
+	}
+
+	limited, err := svc.GetLeaderboard(poll.ClubVanmo, period, 1)
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("expected 1 entry with limit, got %d", len(limited))
+	}
+
+	summary, err := svc.GetClubSummary(poll.ClubVanmo, period, 0)
+	if err != nil {
+		t.Fatalf("GetClubSummary failed: %v", err)
+	}
+	if summary.EventsHeld != 3 {
+		t.Errorf("EventsHeld = %d, want 3", summary.EventsHeld)
+	}
+	if summary.TotalAttendance != 5 {
+		t.Errorf("TotalAttendance = %d, want 5", summary.TotalAttendance)
+	}
+	if summary.BusiestWeekday != time.Saturday {
+		t.Errorf("BusiestWeekday = %s, want Saturday", summary.BusiestWeekday)
+	}
+	if got := summary.AveragePlayersPerEvent(); got < 1.66 || got > 1.67 {
+		t.Errorf("AveragePlayersPerEvent = %f, want ~1.67", got)
+	}
+	// Too few players to split, a table per event
+	if summary.Tables != 3 || summary.AverageTableSize() != summary.AveragePlayersPerEvent() {
+		t.Errorf("Tables = %d, AverageTableSize = %f; want 3 tables of ~1.67", summary.Tables, summary.AverageTableSize())
+	}
+
+	empty, err := svc.GetClubSummary(poll.ClubTbilissimo, period, 0)
+	if err != nil {
+		t.Fatalf("GetClubSummary failed: %v", err)
+	}
+	if empty.EventsHeld != 0 || empty.AveragePlayersPerEvent() != 0 || empty.AverageTableSize() != 0 {
+		t.Errorf("expected empty summary for other club, got %+v", empty)
+	}
+}
+
+func TestStatsRepository_MarkReportPosted(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	statsRepo := NewStatsRepository(db)
+
+	isNew, err := statsRepo.MarkReportPosted(-123456, "2025-02")
+	if err != nil {
+		t.Fatalf("MarkReportPosted failed: %v", err)
+	}
+	if !isNew {
+		t.Error("expected first mark to be new")
+	}
+
+	isNew, err = statsRepo.MarkReportPosted(-123456, "2025-02")
+	if err != nil {
+		t.Fatalf("MarkReportPosted failed: %v", err)
+	}
+	if isNew {
+		t.Error("expected second mark to be a duplicate")
+	}
+
+	isNew, err = statsRepo.MarkReportPosted(-654321, "2025-02")
+	if err != nil {
+		t.Fatalf("MarkReportPosted failed: %v", err)
+	}
+	if !isNew {
+		t.Error("expected mark for another chat to be new")
+	}
+
+	if err := statsRepo.UnmarkReportPosted(-123456, "2025-02"); err != nil {
+		t.Fatalf("UnmarkReportPosted failed: %v", err)
+	}
+	isNew, err = statsRepo.MarkReportPosted(-123456, "2025-02")
+	if err != nil {
+		t.Fatalf("MarkReportPosted failed: %v", err)
+	}
+	if !isNew {
+		t.Error("expected mark after unmark to be new")
+	}
+}
+
+func TestService_GetLeaderboard_MergesIdentities(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	nickRepo := NewNicknameRepository(db)
+	paymentRepo := NewPaymentRepository(db)
+	guestRepo := NewGuestRepository(db)
+	statsRepo := NewStatsRepository(db)
+	svc := poll.NewService(poll.Repositories{
+		Polls:      pollRepo,
+		Votes:      voteRepo,
+		Nicknames:  nickRepo,
+		Attendance: NewAttendanceRepository(db),
+		Stats:      statsRepo,
+		Payments:   paymentRepo,
+		Guests:     guestRepo,
+	})
+
+	const dogID = int64(4)
+	dogUsername := "dog"
+	dogUserID := dogID
+	nickRepo.Create(poll.ClubVanmo, &dogUserID, &dogUsername, "Пёс", "", 0)
+
+	newPoll := func(day int) *poll.Poll {
+		p := &poll.Poll{
+			TgChatID:  -123456,
+			Club:      poll.ClubVanmo,
+			EventDate: time.Date(2025, 2, day, 0, 0, 0, 0, time.Local),
+			Price:     30,
+		}
+		pollRepo.Create(p)
+		return p
+	}
+	vote := func(p *poll.Poll, userID int64, username, name string) {
+		voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: userID, TgUsername: username, TgFirstName: name, TgOptionIndex: int(poll.OptionComeAt19), IsManual: userID < 0})
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The player voted themselves, was added by game nick and by username,
+	// and once both ways for the same event
+	p1 := newPoll(1)
+	vote(p1, poll.ManualUserID("Пёс"), "", "Пёс")
+	vote(p1, dogID, dogUsername, "Dog")
+	p2 := newPoll(3)
+	vote(p2, poll.ManualUserID("Пёс"), "", "Пёс")
+	p3 := newPoll(5)
+	vote(p3, poll.ManualUserID(dogUsername), dogUsername, "dog")
+	paymentRepo.Record(&poll.Payment{PollID: p3.ID, TgUserID: poll.ManualUserID(dogUsername), Amount: 30})
+
+	// Another player who played once
+	vote(p1, 5, "", "Cat")
+
+	period := poll.StatsPeriod{
+		From: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local),
+	}
+	entries, err := svc.GetLeaderboard(poll.ClubVanmo, period, 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	dog := entries[0]
+	if dog.TgUserID != dogID || dog.TgUsername != dogUsername || dog.TgFirstName != "Dog" {
+		t.Errorf("unexpected first entry: %+v", dog)
+	}
+	if dog.EventsPlayed != 3 || dog.VotedAttending != 3 || dog.Debt != 60 {
+		t.Errorf("merged stats = %+v, want 3 events played and 60 debt", dog.PlayerStats)
+	}
+
+	// The same as the player's own stats
+	stats, err := svc.GetPlayerStats(poll.ClubVanmo, dogID, dogUsername, period)
+	if err != nil {
+		t.Fatalf("GetPlayerStats failed: %v", err)
+	}
+	if *stats != dog.PlayerStats {
+		t.Errorf("leaderboard stats %+v differ from player stats %+v", dog.PlayerStats, *stats)
+	}
+
+	// The summary counts the player once per event, with their guests
+	guestRepo.Set(p1.ID, dogID, 2, 0)
+	summary, err := svc.GetClubSummary(poll.ClubVanmo, period, 0)
+	if err != nil {
+		t.Fatalf("GetClubSummary failed: %v", err)
+	}
+	if summary.EventsHeld != 3 || summary.TotalAttendance != 6 {
+		t.Errorf("summary = %+v, want 3 events held and 6 attendance", summary)
+	}
+
+	votes, err := svc.GetPollVotes(poll.ClubVanmo, period)
+	if err != nil {
+		t.Fatalf("GetPollVotes failed: %v", err)
+	}
+	if len(votes) != 4 {
+		t.Fatalf("expected 4 merged poll votes, got %d", len(votes))
+	}
+	for _, v := range votes {
+		if v.TgUserID != dogID && v.TgUserID != 5 {
+			t.Errorf("unexpected vote identity %d in poll %d", v.TgUserID, v.PollID)
+		}
+	}
+}
@@ -17,15 +17,53 @@ func NewVoteRepository(db *DB) *VoteRepository {
 }
 
 // Record inserts a new vote record.
-// Username is normalized to lowercase before storing.
+// Username is normalized to lowercase before storing. A voter whose vote is not attending
+// loses their guests.
 func (r *VoteRepository) Record(v *poll.Vote) error {
+	return r.RecordAll([]*poll.Vote{v})
+}
+
+// RecordAll inserts several vote records in one transaction: either all of them are stored or none.
+// Voters whose new vote is not attending lose their guests in the same transaction.
+func (r *VoteRepository) RecordAll(votes []*poll.Vote) error {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, v := range votes {
+		if err := insertVote(tx, v); err != nil {
+			return err
+		}
+		if !v.OptionKind().IsAttending() {
+			if _, err := tx.Exec(`DELETE FROM guests WHERE poll_id = ? AND host_user_id = ?`, v.PollID, v.TgUserID); err != nil {
+				return fmt.Errorf("delete guests: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit votes: %w", err)
+	}
+	return nil
+}
+
+// execer is implemented by both the database handle and transactions.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertVote inserts v and sets its ID.
+func insertVote(db execer, v *poll.Vote) error {
 	// Normalize username for storage
 	normalizedUsername := poll.NormalizeUsername(v.TgUsername)
 
-	result, err := r.db.db.Exec(`
-		INSERT INTO votes (poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, voted_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, v.PollID, v.TgUserID, normalizedUsername, v.TgFirstName, v.TgOptionIndex, v.IsManual, time.Now())
+	// The actor is stored only for votes entered on behalf of someone else
+	result, err := db.Exec(`
+		INSERT INTO votes (poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, actor_user_id, voted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, v.PollID, v.TgUserID, normalizedUsername, v.TgFirstName, v.TgOptionIndex, v.IsManual, nullInt64(v.ActorUserID), time.Now())
 	if err != nil {
 		return fmt.Errorf("insert vote: %w", err)
 	}
@@ -42,12 +80,12 @@ func (r *VoteRepository) GetCurrentVotes(pollID int64) ([]*poll.Vote, error) {
 	rows, err := r.db.db.Query(`
 		WITH ranked AS (
 			SELECT
-				id, poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, voted_at,
+				id, poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, actor_user_id, voted_at,
 				ROW_NUMBER() OVER (PARTITION BY tg_user_id ORDER BY voted_at DESC) as rn
 			FROM votes
 			WHERE poll_id = ?
 		)
-		SELECT id, poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, voted_at
+		SELECT id, poll_id, tg_user_id, tg_username, tg_first_name, tg_option_index, is_manual, actor_user_id, voted_at
 		FROM ranked
 		WHERE rn = 1 AND tg_option_index >= 0
 		ORDER BY tg_option_index, voted_at
@@ -60,10 +98,12 @@ func (r *VoteRepository) GetCurrentVotes(pollID int64) ([]*poll.Vote, error) {
 	var votes []*poll.Vote
 	for rows.Next() {
 		var v poll.Vote
-		err := rows.Scan(&v.ID, &v.PollID, &v.TgUserID, &v.TgUsername, &v.TgFirstName, &v.TgOptionIndex, &v.IsManual, &v.VotedAt)
+		var actorUserID sql.NullInt64
+		err := rows.Scan(&v.ID, &v.PollID, &v.TgUserID, &v.TgUsername, &v.TgFirstName, &v.TgOptionIndex, &v.IsManual, &actorUserID, &v.VotedAt)
 		if err != nil {
 			return nil, fmt.Errorf("scan vote: %w", err)
 		}
+		v.ActorUserID = actorUserID.Int64
 		votes = append(votes, &v)
 	}
 
@@ -109,9 +149,28 @@ func (r *VoteRepository) LookupUsernameByUserID(userID int64) (string, bool, err
 	return username, true, nil
 }
 
+// LookupLatestChatID returns the chat of the poll the user voted in most recently.
+func (r *VoteRepository) LookupLatestChatID(userID int64) (int64, bool, error) {
+	var chatID int64
+	err := r.db.db.QueryRow(`
+		SELECT p.tg_chat_id FROM votes v
+		JOIN polls p ON p.id = v.poll_id
+		WHERE v.tg_user_id = ?
+		ORDER BY v.voted_at DESC
+		LIMIT 1
+	`, userID).Scan(&chatID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("lookup latest chat id: %w", err)
+	}
+	return chatID, true, nil
+}
+
 // ConsolidateSyntheticVotes updates synthetic votes to use real user data.
 // For a given poll, finds votes with synthetic IDs (derived from username or game nicks)
-// and updates them to use the real user ID and username.
+// and updates them to use the real user ID and username. The guests move along with the votes.
 func (r *VoteRepository) ConsolidateSyntheticVotes(pollID int64, realUserID int64, username string, gameNicks []string) error {
 	normalizedUsername := poll.NormalizeUsername(username)
 
@@ -126,6 +185,9 @@ func (r *VoteRepository) ConsolidateSyntheticVotes(pollID int64, realUserID int6
 		if err != nil {
 			return fmt.Errorf("consolidate votes by username: %w", err)
 		}
+		if err := r.moveGuests(pollID, syntheticID, realUserID); err != nil {
+			return err
+		}
 	}
 
 	// Update synthetic votes by game nicks
@@ -139,6 +201,9 @@ func (r *VoteRepository) ConsolidateSyntheticVotes(pollID int64, realUserID int6
 		if err != nil {
 			return fmt.Errorf("consolidate votes by game nick %q: %w", nick, err)
 		}
+		if err := r.moveGuests(pollID, syntheticID, realUserID); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -147,6 +212,7 @@ func (r *VoteRepository) ConsolidateSyntheticVotes(pollID int64, realUserID int6
 // UpdateVotesUserID updates votes with oldUserID to use newUserID for a specific poll.
 // Also updates tg_username if provided (non-empty) and the vote has no username.
 // Used for backfilling votes when nickname is set. Only affects the specified poll.
+// The guests move along with the votes.
 func (r *VoteRepository) UpdateVotesUserID(pollID int64, oldUserID, newUserID int64, tgUsername string) error {
 	var err error
 	if tgUsername != "" {
@@ -166,5 +232,28 @@ func (r *VoteRepository) UpdateVotesUserID(pollID int64, oldUserID, newUserID in
 	if err != nil {
 		return fmt.Errorf("update votes user id: %w", err)
 	}
+	return r.moveGuests(pollID, oldUserID, newUserID)
+}
+
+// moveGuests re-keys a poll's guests from oldUserID to newUserID.
+// If both have guests, the count set last wins.
+func (r *VoteRepository) moveGuests(pollID, oldUserID, newUserID int64) error {
+	_, err := r.db.db.Exec(`
+		DELETE FROM guests WHERE poll_id = ? AND host_user_id = ?
+			AND updated_at <= (SELECT updated_at FROM guests WHERE poll_id = ? AND host_user_id = ?)
+	`, pollID, newUserID, pollID, oldUserID)
+	if err != nil {
+		return fmt.Errorf("replace guests: %w", err)
+	}
+	_, err = r.db.db.Exec(`
+		UPDATE OR IGNORE guests SET host_user_id = ? WHERE poll_id = ? AND host_user_id = ?
+	`, newUserID, pollID, oldUserID)
+	if err != nil {
+		return fmt.Errorf("move guests: %w", err)
+	}
+	// Left over if the new user's guests were set later
+	if _, err := r.db.db.Exec(`DELETE FROM guests WHERE poll_id = ? AND host_user_id = ?`, pollID, oldUserID); err != nil {
+		return fmt.Errorf("delete moved guests: %w", err)
+	}
 	return nil
 }
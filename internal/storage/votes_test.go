@@ -87,3 +87,172 @@ func TestVoteRepository_LatestVoteWins(t *testing.T) {
 		t.Errorf("expected option 1, got %d", votes[0].TgOptionIndex)
 	}
 }
+
+func TestVoteRepository_ActorUserID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+
+	p := &poll.Poll{
+		TgChatID:  -123456,
+		EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:  true,
+	}
+	pollRepo.Create(p)
+
+	// Self-cast vote has no actor
+	voteRepo.Record(&poll.Vote{
+		PollID:        p.ID,
+		TgUserID:      111,
+		TgFirstName:   "Alice",
+		TgOptionIndex: 0,
+	})
+
+	// Manual vote entered by admin 999
+	voteRepo.Record(&poll.Vote{
+		PollID:        p.ID,
+		TgUserID:      poll.ManualUserID("кот"),
+		TgFirstName:   "Кот",
+		TgOptionIndex: 1,
+		IsManual:      true,
+		ActorUserID:   999,
+	})
+
+	votes, err := voteRepo.GetCurrentVotes(p.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentVotes failed: %v", err)
+	}
+	if len(votes) != 2 {
+		t.Fatalf("expected 2 votes, got %d", len(votes))
+	}
+	if votes[0].ActorUserID != 0 {
+		t.Errorf("expected no actor for self-cast vote, got %d", votes[0].ActorUserID)
+	}
+	if votes[1].ActorUserID != 999 {
+		t.Errorf("expected actor 999 for manual vote, got %d", votes[1].ActorUserID)
+	}
+}
+
+func TestVoteRepository_RecordAll(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+
+	p := &poll.Poll{
+		TgChatID:  -123456,
+		EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		IsActive:  true,
+	}
+	pollRepo.Create(p)
+
+	votes := []*poll.Vote{
+		{PollID: p.ID, TgUserID: 111, TgUsername: "Alice", TgFirstName: "Alice", TgOptionIndex: 0, IsManual: true, ActorUserID: 999},
+		{PollID: p.ID, TgUserID: poll.ManualUserID("Кот"), TgFirstName: "Кот", TgOptionIndex: 0, IsManual: true, ActorUserID: 999},
+	}
+	if err := voteRepo.RecordAll(votes); err != nil {
+		t.Fatalf("RecordAll failed: %v", err)
+	}
+	if votes[0].ID == 0 || votes[1].ID == 0 {
+		t.Error("expected IDs to be set")
+	}
+
+	// A failing vote rolls back the whole batch
+	err := voteRepo.RecordAll([]*poll.Vote{
+		{PollID: p.ID, TgUserID: 222, TgFirstName: "Bob", TgOptionIndex: 1},
+		{PollID: p.ID + 100, TgUserID: 333, TgFirstName: "Carol", TgOptionIndex: 1},
+	})
+	if err == nil {
+		t.Fatal("expected error for vote in a missing poll")
+	}
+
+	current, err := voteRepo.GetCurrentVotes(p.ID)
+	if err != nil {
+		t.Fatalf("GetCurrentVotes failed: %v", err)
+	}
+	if len(current) != 2 {
+		t.Fatalf("expected 2 votes after rollback, got %d", len(current))
+	}
+	if current[0].TgUsername != "alice" {
+		t.Errorf("expected normalized username, got %q", current[0].TgUsername)
+	}
+}
+
+func TestVoteRepository_ConsolidationMovesGuests(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	guestRepo := NewGuestRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+
+	const realID = int64(111)
+	byNick := poll.ManualUserID("Кот")
+	byUsername := poll.ManualUserID("cat")
+	voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: byNick, TgFirstName: "Кот", TgOptionIndex: int(poll.OptionComeAt19), IsManual: true})
+	guestRepo.Set(p.ID, byNick, 2, 99)
+
+	if err := voteRepo.ConsolidateSyntheticVotes(p.ID, realID, "cat", []string{"Кот"}); err != nil {
+		t.Fatalf("ConsolidateSyntheticVotes failed: %v", err)
+	}
+	guests, err := guestRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(guests) != 1 || guests[realID] != 2 {
+		t.Errorf("guests after consolidation = %v, want 2 for the real user", guests)
+	}
+
+	// The count set last wins over the one already kept under the new ID
+	time.Sleep(10 * time.Millisecond)
+	guestRepo.Set(p.ID, byUsername, 1, 99)
+	if err := voteRepo.UpdateVotesUserID(p.ID, byUsername, realID, "cat"); err != nil {
+		t.Fatalf("UpdateVotesUserID failed: %v", err)
+	}
+	guests, err = guestRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(guests) != 1 || guests[realID] != 1 {
+		t.Errorf("guests after backfill = %v, want 1 for the real user", guests)
+	}
+}
+
+func TestVoteRepository_RecordDropsGuestsOfAbsent(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	pollRepo := NewPollRepository(db)
+	voteRepo := NewVoteRepository(db)
+	guestRepo := NewGuestRepository(db)
+
+	p := &poll.Poll{TgChatID: -123456, Club: poll.ClubVanmo, EventDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)}
+	pollRepo.Create(p)
+
+	voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: 1, TgFirstName: "Host", TgOptionIndex: int(poll.OptionComeAt19)})
+	voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: 2, TgFirstName: "Other", TgOptionIndex: int(poll.OptionComeAt20)})
+	guestRepo.Set(p.ID, 1, 2, 0)
+	guestRepo.Set(p.ID, 2, 1, 0)
+
+	// Changing the time keeps the guests, not coming drops them
+	if err := voteRepo.Record(&poll.Vote{PollID: p.ID, TgUserID: 2, TgFirstName: "Other", TgOptionIndex: int(poll.OptionComeAt21OrLater)}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := voteRepo.RecordAll([]*poll.Vote{{PollID: p.ID, TgUserID: 1, TgFirstName: "Host", TgOptionIndex: int(poll.OptionNotComing)}}); err != nil {
+		t.Fatalf("RecordAll failed: %v", err)
+	}
+
+	guests, err := guestRepo.GetByPoll(p.ID)
+	if err != nil {
+		t.Fatalf("GetByPoll failed: %v", err)
+	}
+	if len(guests) != 1 || guests[2] != 1 {
+		t.Errorf("guests = %v, want only 1 guest of the voter still coming", guests)
+	}
+}